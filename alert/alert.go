@@ -0,0 +1,406 @@
+/*
+Package alert evaluates rules over the tickers, balances, fills and margin
+account of a PoloniexApi, and sends the alerts they raise to notifiers:
+
+	rules:
+	  - name: eth-move
+	    kind: price-change
+	    pair: BTC_ETH
+	    percent: 5
+	    window: 1h
+	  - name: low-btc
+	    kind: balance-below
+	    currency: BTC
+	    threshold: 0.5
+	    cooldown: 6h
+	    notify: [mail]
+	notifiers:
+	  - name: stdout
+	    kind: stdout
+	  - name: mail
+	    kind: smtp
+	    addr: smtp.example.com:587
+	    from: bot@example.com
+	    to: [me@example.com]
+
+Only the data needed by the rules is requested on each check.
+*/
+package alert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"gopkg.in/yaml.v3"
+)
+
+// Clock tells the time to the engine, so that rules can be tested.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Alert is raised by a rule. Subject is the market or currency of the rule.
+type Alert struct {
+	Rule    string    `json:"rule"`
+	Kind    Kind      `json:"kind"`
+	Time    time.Time `json:"time"`
+	Subject string    `json:"subject,omitempty"`
+	Value   float64   `json:"value"`
+	Message string    `json:"message"`
+}
+
+func (a Alert) String() string {
+	return fmt.Sprintf("%s [%s] %s", a.Time.UTC().Format(poloniexapi.TradeDateLayout), a.Rule, a.Message)
+}
+
+// Snapshot is the data rules are evaluated on. Fills are your trades since
+// the last check, by market. Nil fields were not requested.
+type Snapshot struct {
+	Tickers  map[string]poloniexapi.Ticker
+	Balances map[string]poloniexapi.Balance
+	Margin   *poloniexapi.MarginAccountSummary
+	Fills    map[string][]poloniexapi.Trade
+}
+
+// NotifierConfig configures a notifier; Kind is stdout, file, webhook or
+// smtp, and the other fields are those of the notifier of that kind.
+type NotifierConfig struct {
+	Name     string        `yaml:"name"`
+	Kind     string        `yaml:"kind"`
+	Path     string        `yaml:"path,omitempty"`
+	URL      string        `yaml:"url,omitempty"`
+	Addr     string        `yaml:"addr,omitempty"`
+	From     string        `yaml:"from,omitempty"`
+	To       []string      `yaml:"to,omitempty"`
+	Username string        `yaml:"username,omitempty"`
+	Password string        `yaml:"password,omitempty"`
+	Timeout  time.Duration `yaml:"timeout,omitempty"`
+}
+
+func (c NotifierConfig) Notifier() (Notifier, error) {
+	switch c.Kind {
+	case "stdout":
+		return Writer{W: os.Stdout}, nil
+	case "file":
+		if c.Path == "" {
+			return nil, fmt.Errorf("Notifier %s has no path", c.Name)
+		}
+		return File{Path: c.Path}, nil
+	case "webhook":
+		if c.URL == "" {
+			return nil, fmt.Errorf("Notifier %s has no url", c.Name)
+		}
+		return Webhook{URL: c.URL}, nil
+	case "smtp":
+		if c.Addr == "" || c.From == "" || len(c.To) == 0 {
+			return nil, fmt.Errorf("Notifier %s needs addr, from and to", c.Name)
+		}
+		return SMTP{Addr: c.Addr, From: c.From, To: c.To, Username: c.Username, Password: c.Password, Timeout: c.Timeout}, nil
+	}
+
+	return nil, fmt.Errorf("Notifier %s has unknown kind %q", c.Name, c.Kind)
+}
+
+type Config struct {
+	Rules     []Rule           `yaml:"rules"`
+	Notifiers []NotifierConfig `yaml:"notifiers"`
+}
+
+func ParseConfig(data []byte) (*Config, error) {
+	config := new(Config)
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read rules from %s (%s)", path, err.Error())
+	}
+
+	return config, nil
+}
+
+// Engine returns an engine with the rules and notifiers of the configuration.
+func (c *Config) Engine(api *poloniexapi.PoloniexApi) (*Engine, error) {
+	notifiers := make(map[string]Notifier)
+
+	for _, nc := range c.Notifiers {
+		if _, ok := notifiers[nc.Name]; ok {
+			return nil, fmt.Errorf("Notifier %s is configured twice", nc.Name)
+		}
+
+		n, err := nc.Notifier()
+		if err != nil {
+			return nil, err
+		}
+		notifiers[nc.Name] = n
+	}
+
+	return NewEngine(api, c.Rules, notifiers)
+}
+
+/*
+Engine checks rules and sends their alerts to the named notifiers.
+
+The state of the rules, such as the prices seen within the window of a price
+change, is kept in memory: it starts over when the engine is restarted.
+*/
+type Engine struct {
+	Clock Clock
+
+	api       *poloniexapi.PoloniexApi
+	rules     []Rule
+	states    []state
+	notifiers map[string]Notifier
+
+	since   time.Time      // fills are requested from there
+	seen    map[int64]bool // trades of the last request, returned again by the next
+	pending []pendingAlert // alerts not delivered yet
+}
+
+// pendingAlert is an alert to send again to the notifiers that failed.
+type pendingAlert struct {
+	alert     Alert
+	notifiers []string
+}
+
+// Most undelivered alerts kept by an engine to be sent again; the oldest are
+// dropped first.
+var MaxPendingAlerts = 100
+
+// NewEngine validates rules and the notifiers they name.
+func NewEngine(api *poloniexapi.PoloniexApi, rules []Rule, notifiers map[string]Notifier) (*Engine, error) {
+	names := make(map[string]bool)
+
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+
+		if names[rules[i].Name] {
+			return nil, fmt.Errorf("%w: %s is defined twice", ErrInvalidRule, rules[i].Name)
+		}
+		names[rules[i].Name] = true
+
+		for _, n := range rules[i].Notify {
+			if _, ok := notifiers[n]; !ok {
+				return nil, fmt.Errorf("%w: %s: unknown notifier %s", ErrInvalidRule, rules[i].Name, n)
+			}
+		}
+	}
+
+	return &Engine{
+		Clock:     systemClock{},
+		api:       api,
+		rules:     rules,
+		states:    make([]state, len(rules)),
+		notifiers: notifiers,
+		seen:      make(map[int64]bool),
+	}, nil
+}
+
+func (e *Engine) needs(kind Kind) bool {
+	for _, r := range e.rules {
+		if r.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetch requests the data needed by the rules.
+func (e *Engine) fetch(ctx context.Context, now time.Time) (Snapshot, error) {
+	var snapshot Snapshot
+	var err error
+
+	if e.needs(PriceChange) {
+		if snapshot.Tickers, err = e.api.ApiPublicTickerContext(ctx); err != nil {
+			return snapshot, err
+		}
+	}
+
+	if e.needs(BalanceBelow) {
+		if snapshot.Balances, err = e.api.ApiPrivateCompleteBalancesContext(ctx, false); err != nil {
+			return snapshot, err
+		}
+	}
+
+	if e.needs(MarginBelow) {
+		if snapshot.Margin, err = e.api.ApiPrivateMarginAccountSummaryContext(ctx); err != nil {
+			return snapshot, err
+		}
+	}
+
+	if e.needs(OrderFilled) {
+		if e.since.IsZero() {
+			e.since = now
+		}
+
+		trades, err := e.api.ApiPrivateTradeHistoryContext(ctx, "all", int(e.since.Unix()), 0)
+		if err != nil {
+			return snapshot, err
+		}
+
+		// The start is rounded to the second: trades of that second come again.
+		seen := make(map[int64]bool)
+		snapshot.Fills = make(map[string][]poloniexapi.Trade)
+		for _, pair := range sortedKeys(trades) {
+			// Oldest first.
+			for i := len(trades[pair]) - 1; i >= 0; i-- {
+				t := trades[pair][i]
+				seen[t.GlobalTradeID] = true
+				if !e.seen[t.GlobalTradeID] {
+					snapshot.Fills[pair] = append(snapshot.Fills[pair], t)
+				}
+			}
+		}
+
+		e.since, e.seen = now, seen
+	}
+
+	return snapshot, nil
+}
+
+// Evaluate returns the alerts raised by snapshot, at the time of the clock.
+func (e *Engine) Evaluate(snapshot Snapshot) []Alert {
+	now := e.Clock.Now()
+	alerts := make([]Alert, 0)
+
+	for i := range e.rules {
+		alerts = append(alerts, e.rules[i].evaluate(&e.states[i], snapshot, now)...)
+	}
+
+	return alerts
+}
+
+// Notify sends a to the notifiers of its rule. Every notifier is tried; the
+// first error is returned.
+func (e *Engine) Notify(ctx context.Context, a Alert) error {
+	_, err := e.deliver(ctx, a, e.targets(a.Rule))
+	return err
+}
+
+// targets returns the notifiers of a rule, all of them when it names none.
+func (e *Engine) targets(rule string) []string {
+	var targets []string
+	for _, r := range e.rules {
+		if r.Name == rule {
+			targets = r.Notify
+		}
+	}
+	if len(targets) == 0 {
+		targets = sortedKeys(e.notifiers)
+	}
+
+	return targets
+}
+
+// deliver sends a to the named notifiers, and returns those that failed with
+// the first error.
+func (e *Engine) deliver(ctx context.Context, a Alert, names []string) ([]string, error) {
+	var failed []string
+	var first error
+
+	for _, name := range names {
+		if err := e.notifiers[name].Notify(ctx, a); err != nil {
+			failed = append(failed, name)
+			if first == nil {
+				first = fmt.Errorf("Could not notify %s (%s)", name, err.Error())
+			}
+		}
+	}
+
+	return failed, first
+}
+
+/*
+Check requests the data needed by the rules, evaluates them and notifies the
+alerts raised, which are returned. Rules are not evaluated when a request
+fails.
+
+A rule that fired does not fire again until its condition clears or its
+cooldown elapses, even when its alert could not be delivered: such alerts are
+kept and sent again to the notifiers that failed on the following checks,
+before the new ones, up to MaxPendingAlerts.
+*/
+func (e *Engine) Check(ctx context.Context) ([]Alert, error) {
+	snapshot, err := e.fetch(ctx, e.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := e.Evaluate(snapshot)
+
+	queue := e.pending
+	e.pending = nil
+	for _, a := range alerts {
+		queue = append(queue, pendingAlert{a, e.targets(a.Rule)})
+	}
+
+	var first error
+	for _, p := range queue {
+		failed, err := e.deliver(ctx, p.alert, p.notifiers)
+		if err != nil && first == nil {
+			first = err
+		}
+
+		if len(failed) > 0 {
+			e.pending = append(e.pending, pendingAlert{p.alert, failed})
+		}
+	}
+
+	if n := len(e.pending) - MaxPendingAlerts; n > 0 {
+		e.pending = e.pending[n:]
+	}
+
+	return alerts, first
+}
+
+// Run checks the rules every interval until ctx is done. Errors are passed to
+// onError, which may be nil.
+func (e *Engine) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.Check(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
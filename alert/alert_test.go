@@ -0,0 +1,256 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/internal/polotest"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+const testConfig = `
+rules:
+  - name: eth-move
+    kind: price-change
+    pair: BTC_ETH
+    percent: 5
+    window: 1h
+  - name: low-btc
+    kind: balance-below
+    currency: BTC
+    threshold: 0.5
+    cooldown: 6h
+    notify: [out]
+notifiers:
+  - name: out
+    kind: stdout
+`
+
+func testEngine(t *testing.T, config string) (*Engine, *fakeClock, *bytes.Buffer) {
+	c, err := ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := polotest.Api(polotest.Answers(map[string]string{
+		"returnTradeHistory": `{"BTC_XMR":[
+			{"globalTradeID":2,"tradeID":"12","date":"2018-03-01 10:00:01","rate":"0.03","amount":"2","total":"0.06","fee":"0.0015","orderNumber":"7","type":"sell","category":"exchange"},
+			{"globalTradeID":1,"tradeID":"11","date":"2018-03-01 10:00:00","rate":"0.03","amount":"1","total":"0.03","fee":"0.0015","orderNumber":"7","type":"sell","category":"exchange"}]}`,
+	}))
+
+	e, err := c.Engine(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer)
+	e.notifiers["out"] = Writer{W: out}
+
+	clock := &fakeClock{now: time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)}
+	e.Clock = clock
+
+	return e, clock, out
+}
+
+func TestRules(t *testing.T) {
+	e, clock, _ := testEngine(t, testConfig)
+
+	snapshot := func(price, btc float64) Snapshot {
+		return Snapshot{
+			Tickers:  map[string]poloniexapi.Ticker{"BTC_ETH": {Last: price}},
+			Balances: map[string]poloniexapi.Balance{"BTC": {Available: btc, OnOrders: 0.1}},
+		}
+	}
+
+	steps := []struct {
+		advance time.Duration
+		price   float64
+		btc     float64
+		fired   []string
+	}{
+		{0, 0.05, 1, nil},
+		{30 * time.Minute, 0.052, 1, nil},
+		{20 * time.Minute, 0.0526, 0.3, []string{"eth-move", "low-btc"}},
+		{5 * time.Minute, 0.0527, 0.3, nil},                 // both still true, not fired again
+		{30 * time.Minute, 0.0527, 0.3, nil},                // 0.05 left the window, 0.052 -> 0.0527 is 1.3%
+		{10 * time.Minute, 0.05, 0.3, []string{"eth-move"}}, // down from 0.0527
+		{6 * time.Hour, 0.05, 0.3, []string{"low-btc"}},     // cooldown elapsed
+		{time.Minute, 0.05, 1, nil},
+		{time.Minute, 0.05, 0.2, []string{"low-btc"}},
+	}
+
+	for i, s := range steps {
+		clock.Advance(s.advance)
+
+		var fired []string
+		for _, a := range e.Evaluate(snapshot(s.price, s.btc)) {
+			fired = append(fired, a.Rule)
+		}
+
+		if strings.Join(fired, ",") != strings.Join(s.fired, ",") {
+			t.Errorf("step %d: fired %v, want %v", i, fired, s.fired)
+		}
+	}
+}
+
+func TestCheckFills(t *testing.T) {
+	e, clock, out := testEngine(t, `
+rules:
+  - name: fills
+    kind: order-filled
+notifiers:
+  - name: out
+    kind: stdout
+`)
+
+	alerts, err := e.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Value != 3 || !alerts[0].Time.Equal(clock.now) {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+
+	want := "2018-03-01 10:00:00 [fills] order 7 on BTC_XMR filled: sell 3.00000000 at 0.03000000\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+
+	// The same trades are returned again.
+	clock.Advance(time.Minute)
+	if alerts, err = e.Check(context.Background()); err != nil || len(alerts) != 0 {
+		t.Errorf("second check: got %+v %v, want no alert", alerts, err)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, config := range []string{
+		"rules: [{name: a, kind: price-change, pair: BTC_ETH, percent: 5}]",
+		"rules: [{name: a, kind: balance-below, threshold: 1}]",
+		"rules: [{name: a, kind: order-filled}, {name: a, kind: order-filled}]",
+		"rules: [{name: a, kind: order-filled, notify: [mail]}]",
+		"rules: [{name: a, kind: volume}]",
+		"notifiers: [{name: mail, kind: smtp}]",
+	} {
+		c, err := ParseConfig([]byte(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = c.Engine(poloniexapi.New("key", "secret")); err == nil {
+			t.Errorf("%s: no error", config)
+		}
+	}
+}
+
+// flaky fails its first failures notifications.
+type flaky struct {
+	failures int
+	sent     []Alert
+}
+
+func (f *flaky) Notify(ctx context.Context, a Alert) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("mail server down")
+	}
+
+	f.sent = append(f.sent, a)
+	return nil
+}
+
+func TestCheckRetriesFailedNotifications(t *testing.T) {
+	e, clock, out := testEngine(t, `
+rules:
+  - name: fills
+    kind: order-filled
+notifiers:
+  - name: out
+    kind: stdout
+  - name: mail
+    kind: stdout
+`)
+	mail := &flaky{failures: 2}
+	e.notifiers["mail"] = mail
+
+	if _, err := e.Check(context.Background()); err == nil {
+		t.Error("no error when a notifier failed")
+	}
+
+	// Still failing, then delivered, to the failed notifier only.
+	for i := 0; i < 2; i++ {
+		clock.Advance(time.Minute)
+		alerts, err := e.Check(context.Background())
+		if len(alerts) != 0 || (err == nil) != (i == 1) {
+			t.Errorf("check %d: got %+v %v", i, alerts, err)
+		}
+	}
+
+	if len(mail.sent) != 1 || mail.sent[0].Rule != "fills" {
+		t.Errorf("mailed %+v, want the fills alert once", mail.sent)
+	}
+	if strings.Count(out.String(), "[fills]") != 1 {
+		t.Errorf("got %q, want the fills alert once", out.String())
+	}
+	if len(e.pending) != 0 {
+		t.Errorf("still pending: %+v", e.pending)
+	}
+}
+
+func TestSMTPTimeout(t *testing.T) {
+	// A server that accepts connections and never answers.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	s := SMTP{Addr: listener.Addr().String(), From: "bot@example.com", To: []string{"me@example.com"}, Timeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	if err = s.Notify(context.Background(), Alert{Rule: "test"}); err == nil {
+		t.Error("no error from a server that does not answer")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("gave up after %s, want the timeout", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Timeout = time.Hour
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start = time.Now()
+	if err = s.Notify(ctx, Alert{Rule: "test"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("gave up after %s, want when the context is cancelled", elapsed)
+	}
+}
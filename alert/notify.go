@@ -0,0 +1,188 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Notifier delivers alerts.
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// Writer writes alerts as lines to W, os.Stdout for instance.
+type Writer struct {
+	W io.Writer
+}
+
+func (w Writer) Notify(ctx context.Context, a Alert) error {
+	_, err := fmt.Fprintln(w.W, a.String())
+	return err
+}
+
+// File appends alerts as lines to the file at Path, created if needed.
+type File struct {
+	Path string
+}
+
+func (f File) Notify(ctx context.Context, a Alert) error {
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintln(file, a.String()); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Webhook POSTs alerts as JSON to URL. Answers other than 2xx are errors.
+type Webhook struct {
+	URL    string
+	Client *http.Client // http.DefaultClient when nil
+}
+
+func (w Webhook) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Could not post alert to %s (%s)", w.URL, resp.Status)
+	}
+
+	return nil
+}
+
+/*
+SMTP mails alerts through the server at Addr, upgrading the connection with
+STARTTLS when the server offers it, and authenticating with PLAIN when
+Username is set. Delivery is given up after Timeout, 30 seconds when zero, or
+when the context is done.
+*/
+type SMTP struct {
+	Addr     string
+	From     string
+	To       []string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+func (s SMTP) Notify(ctx context.Context, a Alert) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err = conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	// The deadline does not cover the context being cancelled.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Poloniex alert: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		s.From, strings.Join(s.To, ", "), a.Rule, a.Time.Format(time.RFC1123Z), a.String())
+
+	if err = s.send(conn, host, []byte(msg)); err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// send does what smtp.SendMail does, over conn.
+func (s SMTP) send(conn net.Conn, host string, msg []byte) error {
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if s.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err = c.Mail(s.From); err != nil {
+		return err
+	}
+
+	for _, to := range s.To {
+		if err = c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
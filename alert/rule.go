@@ -0,0 +1,220 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+type Kind string
+
+const (
+	PriceChange  Kind = "price-change"  // the last price of Pair moves by Percent within Window
+	BalanceBelow Kind = "balance-below" // the balance of Currency, on orders included, falls below Threshold
+	OrderFilled  Kind = "order-filled"  // one of your orders on Pair, or any market when empty, trades
+	MarginBelow  Kind = "margin-below"  // the current margin of the margin account falls below Threshold
+)
+
+var ErrInvalidRule = errors.New("invalid rule")
+
+/*
+Rule is a condition evaluated on each check. A rule fires when its condition
+becomes true, and again every Cooldown while it stays true if Cooldown is set.
+Order filled rules fire once for each order that traded since the last check.
+
+Notify lists the names of the notifiers the alerts of the rule are sent to,
+all of them when empty.
+*/
+type Rule struct {
+	Name      string        `yaml:"name"`
+	Kind      Kind          `yaml:"kind"`
+	Pair      string        `yaml:"pair,omitempty"`
+	Currency  string        `yaml:"currency,omitempty"`
+	Percent   float64       `yaml:"percent,omitempty"`
+	Window    time.Duration `yaml:"window,omitempty"`
+	Threshold float64       `yaml:"threshold,omitempty"`
+	Cooldown  time.Duration `yaml:"cooldown,omitempty"`
+	Notify    []string      `yaml:"notify,omitempty"`
+}
+
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: no name", ErrInvalidRule)
+	}
+
+	if r.Cooldown < 0 {
+		return fmt.Errorf("%w: %s: cooldown must not be negative", ErrInvalidRule, r.Name)
+	}
+
+	switch r.Kind {
+	case PriceChange:
+		if r.Pair == "" || r.Pair == "all" {
+			return fmt.Errorf("%w: %s: a single market must be given", ErrInvalidRule, r.Name)
+		}
+		if r.Percent <= 0 || r.Window <= 0 {
+			return fmt.Errorf("%w: %s: percent and window must be positive", ErrInvalidRule, r.Name)
+		}
+	case BalanceBelow:
+		if r.Currency == "" {
+			return fmt.Errorf("%w: %s: no currency", ErrInvalidRule, r.Name)
+		}
+		if r.Threshold <= 0 {
+			return fmt.Errorf("%w: %s: threshold must be positive", ErrInvalidRule, r.Name)
+		}
+	case MarginBelow:
+		if r.Threshold <= 0 {
+			return fmt.Errorf("%w: %s: threshold must be positive", ErrInvalidRule, r.Name)
+		}
+	case OrderFilled:
+	default:
+		return fmt.Errorf("%w: %s: unknown kind %q", ErrInvalidRule, r.Name, r.Kind)
+	}
+
+	return nil
+}
+
+type sample struct {
+	time  time.Time
+	price float64
+}
+
+// state is what a rule remembers between checks.
+type state struct {
+	active  bool      // the condition held on the last check
+	firedAt time.Time // last time the rule fired
+	samples []sample  // last prices within the window, for price changes
+}
+
+// fire tells if a condition rule fires at now, and updates its state.
+func (s *state) fire(r *Rule, cond bool, now time.Time) bool {
+	was := s.active
+	s.active = cond
+
+	if !cond {
+		return false
+	}
+
+	if !was || (r.Cooldown > 0 && now.Sub(s.firedAt) >= r.Cooldown) {
+		s.firedAt = now
+		return true
+	}
+
+	return false
+}
+
+// priceChange records price and returns the largest move, in percent, from
+// the prices seen within the window, with the price it moved from.
+func (s *state) priceChange(r *Rule, price float64, now time.Time) (float64, float64) {
+	kept := s.samples[:0]
+	for _, p := range s.samples {
+		if !p.time.Before(now.Add(-r.Window)) {
+			kept = append(kept, p)
+		}
+	}
+	s.samples = append(kept, sample{time: now, price: price})
+
+	var change, from float64
+	for _, p := range s.samples {
+		if p.price == 0 {
+			continue
+		}
+
+		c := (price/p.price - 1) * 100
+		if math.Abs(c) > math.Abs(change) {
+			change, from = c, p.price
+		}
+	}
+
+	return change, from
+}
+
+// evaluate returns the alerts of the rule on snapshot. Rules whose data is
+// missing from the snapshot are left untouched.
+func (r *Rule) evaluate(s *state, snapshot Snapshot, now time.Time) []Alert {
+	alert := Alert{Rule: r.Name, Kind: r.Kind, Time: now}
+
+	switch r.Kind {
+	case PriceChange:
+		ticker, ok := snapshot.Tickers[r.Pair]
+		if !ok {
+			return nil
+		}
+
+		change, from := s.priceChange(r, ticker.Last, now)
+		if !s.fire(r, math.Abs(change) >= r.Percent, now) {
+			return nil
+		}
+
+		alert.Subject, alert.Value = r.Pair, change
+		alert.Message = fmt.Sprintf("%s moved %+.2f%% within %s (%.8f to %.8f)", r.Pair, change, r.Window, from, ticker.Last)
+
+	case BalanceBelow:
+		if snapshot.Balances == nil {
+			return nil
+		}
+
+		b := snapshot.Balances[r.Currency]
+		total := b.Available + b.OnOrders
+		if !s.fire(r, total < r.Threshold, now) {
+			return nil
+		}
+
+		alert.Subject, alert.Value = r.Currency, total
+		alert.Message = fmt.Sprintf("%s balance is %.8f, below %.8f", r.Currency, total, r.Threshold)
+
+	case MarginBelow:
+		if snapshot.Margin == nil {
+			return nil
+		}
+
+		margin := snapshot.Margin.CurrentMargin
+		if !s.fire(r, margin < r.Threshold, now) {
+			return nil
+		}
+
+		alert.Value = margin
+		alert.Message = fmt.Sprintf("current margin is %.4f, below %.4f", margin, r.Threshold)
+
+	case OrderFilled:
+		return r.fills(snapshot.Fills, alert)
+	}
+
+	return []Alert{alert}
+}
+
+// fills returns an alert for each order of fills, trades summed.
+func (r *Rule) fills(fills map[string][]poloniexapi.Trade, alert Alert) []Alert {
+	alerts := make([]Alert, 0)
+
+	for _, pair := range sortedKeys(fills) {
+		if r.Pair != "" && r.Pair != "all" && r.Pair != pair {
+			continue
+		}
+
+		var orders []int64
+		amounts := make(map[int64]float64)
+		totals := make(map[int64]float64)
+		sides := make(map[int64]string)
+
+		for _, t := range fills[pair] {
+			if _, ok := amounts[t.OrderNumber]; !ok {
+				orders = append(orders, t.OrderNumber)
+			}
+			amounts[t.OrderNumber] += t.Amount
+			totals[t.OrderNumber] += t.Total
+			sides[t.OrderNumber] = t.Type
+		}
+
+		for _, order := range orders {
+			a := alert
+			a.Subject, a.Value = pair, amounts[order]
+			a.Message = fmt.Sprintf("order %d on %s filled: %s %.8f at %.8f", order, pair, sides[order], amounts[order], totals[order]/amounts[order])
+			alerts = append(alerts, a)
+		}
+	}
+
+	return alerts
+}
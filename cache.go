@@ -0,0 +1,153 @@
+package poloniexapi
+
+import (
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// TTLs of NewCache for the public commands called over and over by most
+// components. Tickers move, currencies hardly ever change.
+var DefaultCacheTTLs = map[string]time.Duration{
+	CMD_PUBLIC_TICKER:     2 * time.Second,
+	CMD_PUBLIC_24HVOLUME:  time.Minute,
+	CMD_PUBLIC_CURRENCIES: time.Hour,
+}
+
+type cacheEntry struct {
+	command string
+	body    []byte
+	expires time.Time
+}
+
+/*
+Cache keeps the responses of public commands for a TTL set by command, so
+that components sharing a PoloniexApi do not request the same data again and
+again. Identical requests made at the same time are sent once, and all callers
+get its response. Errors are never cached.
+
+Signed requests and commands without a TTL always go to the exchange. The
+cache is safe for concurrent use; a request shared by several callers is
+cancelled with the context of the first.
+
+	api.Cache = poloniexapi.NewCache(poloniexapi.DefaultCacheTTLs)
+*/
+type Cache struct {
+	mu      sync.Mutex
+	ttls    map[string]time.Duration
+	entries map[string]cacheEntry
+	gens    map[string]int // changed by Invalidate, so that a fetch started before is not kept
+	group   singleflight.Group
+	now     func() time.Time
+}
+
+func NewCache(ttls map[string]time.Duration) *Cache {
+	c := &Cache{
+		ttls:    make(map[string]time.Duration, len(ttls)),
+		entries: make(map[string]cacheEntry),
+		gens:    make(map[string]int),
+		now:     time.Now,
+	}
+
+	for command, ttl := range ttls {
+		c.ttls[command] = ttl
+	}
+
+	return c
+}
+
+// SetTTL sets the TTL of command, or stops caching it when ttl is zero.
+func (c *Cache) SetTTL(command string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 {
+		delete(c.ttls, command)
+	} else {
+		c.ttls[command] = ttl
+	}
+
+	c.invalidate(command)
+}
+
+/*
+Invalidate drops the responses of commands, or of all commands when none is
+given. Requests in flight are not stored when they complete, and later
+callers do not wait for them but send a new request.
+*/
+func (c *Cache) Invalidate(commands ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(commands) == 0 {
+		for command := range c.ttls {
+			commands = append(commands, command)
+		}
+	}
+
+	for _, command := range commands {
+		c.invalidate(command)
+	}
+}
+
+func (c *Cache) invalidate(command string) {
+	c.gens[command]++
+
+	for key, entry := range c.entries {
+		if entry.command == command {
+			delete(c.entries, key)
+		}
+	}
+}
+
+/*
+get returns the response of params from the cache, or from fetch, called once
+for concurrent identical requests. A nil cache or a command without TTL always
+calls fetch.
+*/
+func (c *Cache) get(params url.Values, fetch func() ([]byte, error)) ([]byte, error) {
+	if c == nil {
+		return fetch()
+	}
+
+	command := params.Get("command")
+	key := params.Encode()
+
+	c.mu.Lock()
+	ttl, ok := c.ttls[command]
+	entry, cached := c.entries[key]
+	gen := c.gens[command]
+	c.mu.Unlock()
+
+	if !ok {
+		return fetch()
+	}
+
+	if cached && c.now().Before(entry.expires) {
+		return entry.body, nil
+	}
+
+	// Requests started before and after an invalidation are not shared.
+	body, err, _ := c.group.Do(key+"#"+strconv.Itoa(gen), func() (interface{}, error) {
+		body, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		if c.gens[command] == gen {
+			c.entries[key] = cacheEntry{command: command, body: body, expires: c.now().Add(ttl)}
+		}
+		c.mu.Unlock()
+
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return body.([]byte), nil
+}
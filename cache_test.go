@@ -0,0 +1,221 @@
+package poloniexapi
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testCache returns a cache of the ticker with ttl, whose clock is moved by
+// the returned function.
+func testCache(ttl time.Duration) (*Cache, func(time.Duration)) {
+	c := NewCache(map[string]time.Duration{CMD_PUBLIC_TICKER: ttl})
+
+	var mu sync.Mutex
+	now := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	return c, func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+}
+
+var tickerParams = url.Values{"command": {CMD_PUBLIC_TICKER}}
+
+func TestCacheSingleflight(t *testing.T) {
+	c, _ := testCache(time.Second)
+
+	const callers = 20
+
+	var loads int32
+	release := make(chan struct{})
+	load := func() ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return []byte("ticker"), nil
+	}
+
+	var started, done sync.WaitGroup
+	bodies := make([][]byte, callers)
+	errs := make([]error, callers)
+
+	for i := 0; i < callers; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			bodies[i], errs[i] = c.get(tickerParams, load)
+		}(i)
+	}
+
+	// Leave the callers time to join the load in flight.
+	started.Wait()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if loads != 1 {
+		t.Errorf("%d callers made %d loads, want 1", callers, loads)
+	}
+
+	for i := range bodies {
+		if errs[i] != nil || string(bodies[i]) != "ticker" {
+			t.Errorf("caller %d got %q, %v", i, bodies[i], errs[i])
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	c, advance := testCache(2 * time.Second)
+
+	loads := 0
+	load := func() ([]byte, error) {
+		loads++
+		return []byte{byte(loads)}, nil
+	}
+
+	steps := []struct {
+		after time.Duration
+		loads int
+	}{
+		{0, 1},
+		{time.Second, 1},
+		{999 * time.Millisecond, 1},
+		{time.Millisecond, 2}, // expired at 2s
+		{time.Second, 2},
+		{time.Second, 3},
+	}
+
+	for i, step := range steps {
+		advance(step.after)
+
+		body, err := c.get(tickerParams, load)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if loads != step.loads || body[0] != byte(loads) {
+			t.Errorf("step %d: got body %v after %d loads, want %d loads", i, body, loads, step.loads)
+		}
+	}
+
+	c.Invalidate(CMD_PUBLIC_TICKER)
+	if _, err := c.get(tickerParams, load); err != nil || loads != 4 {
+		t.Errorf("got %d loads after Invalidate, want 4 (%v)", loads, err)
+	}
+
+	// Commands without TTL are always loaded.
+	for i := 0; i < 2; i++ {
+		c.get(url.Values{"command": {CMD_PUBLIC_ORDER_BOOK}}, load)
+	}
+	if loads != 6 {
+		t.Errorf("got %d loads, want 6", loads)
+	}
+}
+
+func TestCacheErrors(t *testing.T) {
+	c, _ := testCache(time.Minute)
+
+	failure := errors.New("exchange down")
+
+	loads := 0
+	load := func() ([]byte, error) {
+		if loads++; loads == 1 {
+			return nil, failure
+		}
+		return []byte("ticker"), nil
+	}
+
+	if _, err := c.get(tickerParams, load); !errors.Is(err, failure) {
+		t.Errorf("got %v, want %v", err, failure)
+	}
+
+	body, err := c.get(tickerParams, load)
+	if err != nil || string(body) != "ticker" || loads != 2 {
+		t.Errorf("error cached: got %q, %v after %d loads", body, err, loads)
+	}
+
+	if _, err = c.get(tickerParams, load); err != nil || loads != 2 {
+		t.Errorf("got %d loads, want 2 (%v)", loads, err)
+	}
+}
+
+func TestCacheNil(t *testing.T) {
+	var c *Cache
+
+	loads := 0
+	for i := 0; i < 2; i++ {
+		c.get(tickerParams, func() ([]byte, error) {
+			loads++
+			return nil, nil
+		})
+	}
+
+	if loads != 2 {
+		t.Errorf("nil cache made %d loads, want 2", loads)
+	}
+}
+
+func TestCacheInvalidateInFlight(t *testing.T) {
+	c, _ := testCache(time.Minute)
+
+	loads := 0
+	load := func() ([]byte, error) {
+		loads++
+		if loads == 1 {
+			// Invalidated while the first request is in flight.
+			c.Invalidate(CMD_PUBLIC_TICKER)
+			return []byte("stale"), nil
+		}
+		return []byte("fresh"), nil
+	}
+
+	if body, err := c.get(tickerParams, load); err != nil || string(body) != "stale" {
+		t.Fatalf("got %q, %v", body, err)
+	}
+
+	body, err := c.get(tickerParams, load)
+	if err != nil || string(body) != "fresh" || loads != 2 {
+		t.Errorf("got %q, %v after %d loads, want the response fetched after Invalidate", body, err, loads)
+	}
+
+	// Invalidating all commands also drops requests in flight.
+	c.Invalidate()
+	if _, err = c.get(tickerParams, load); err != nil || loads != 3 {
+		t.Errorf("got %d loads after Invalidate of all commands, want 3 (%v)", loads, err)
+	}
+
+	release := make(chan struct{})
+	joined := make(chan []byte)
+	go func() {
+		body, _ := c.get(url.Values{"command": {CMD_PUBLIC_TICKER}, "x": {"1"}}, func() ([]byte, error) {
+			<-release
+			return []byte("stale"), nil
+		})
+		joined <- body
+	}()
+
+	// A caller coming after Invalidate does not wait for the request in
+	// flight before it.
+	time.Sleep(10 * time.Millisecond)
+	c.Invalidate(CMD_PUBLIC_TICKER)
+	body, err = c.get(url.Values{"command": {CMD_PUBLIC_TICKER}, "x": {"1"}}, func() ([]byte, error) {
+		return []byte("fresh"), nil
+	})
+	close(release)
+	<-joined
+
+	if err != nil || string(body) != "fresh" {
+		t.Errorf("got %q, %v, want a new request after Invalidate", body, err)
+	}
+}
@@ -0,0 +1,256 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// How many cancellations CancelWhere sends at once, how many times a
+// cancellation failing on a transient error is attempted, and how long it
+// waits before the first retry, doubled after each one.
+var (
+	CancelConcurrency = 4
+	CancelAttempts    = 3
+	CancelRetryDelay  = 500 * time.Millisecond
+)
+
+type CancelStatus int
+
+const (
+	CancelDone          CancelStatus = iota // the order was cancelled
+	CancelAlreadyFilled                     // the order was filled before it could be cancelled
+	CancelNotFound                          // the order is unknown or was already cancelled
+	CancelFailed                            // the order may still be open, see Err
+)
+
+func (s CancelStatus) String() string {
+	switch s {
+	case CancelDone:
+		return "cancelled"
+	case CancelAlreadyFilled:
+		return "already filled"
+	case CancelNotFound:
+		return "not found"
+	case CancelFailed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+// CancelResult is the outcome of the cancellation of one order.
+type CancelResult struct {
+	Pair     string
+	Order    OpenOrder
+	Status   CancelStatus
+	Attempts int
+	Err      error
+}
+
+// CancelReport has one result per order, in no particular order.
+type CancelReport []CancelResult
+
+// Failed returns the results of the orders that may still be open.
+func (r CancelReport) Failed() CancelReport {
+	out := CancelReport{}
+	for _, result := range r {
+		if result.Status == CancelFailed {
+			out = append(out, result)
+		}
+	}
+
+	return out
+}
+
+/*
+CancelAll cancels every open order of a market, or of all markets when
+currencyPair is "all". See CancelWhere.
+*/
+func (api *PoloniexApi) CancelAll(currencyPair string) (CancelReport, error) {
+	return api.CancelAllContext(context.Background(), currencyPair)
+}
+
+// CancelAllContext is CancelAll with the context of the requests.
+func (api *PoloniexApi) CancelAllContext(ctx context.Context, currencyPair string) (CancelReport, error) {
+	return api.cancelOpen(ctx, currencyPair, nil)
+}
+
+/*
+CancelWhere cancels the open orders, on all markets, for which match returns
+true.
+
+Cancellations are sent concurrently, within the api rate limit. Those failing
+without an answer from the exchange, or refused for a nonce or the rate limit,
+are retried up to CancelAttempts times. When the exchange refuses a
+cancellation, the trades and open orders are checked to tell a filled order
+from one that is not open anymore; orders still open are reported as failed.
+
+The error is only set when the open orders could not be fetched; failed
+cancellations are reported in the CancelReport.
+*/
+func (api *PoloniexApi) CancelWhere(match func(pair string, order OpenOrder) bool) (CancelReport, error) {
+	return api.CancelWhereContext(context.Background(), match)
+}
+
+// CancelWhereContext is CancelWhere with the context of the requests. Retries
+// stop once ctx is done, the orders left being reported as failed.
+func (api *PoloniexApi) CancelWhereContext(ctx context.Context, match func(pair string, order OpenOrder) bool) (CancelReport, error) {
+	return api.cancelOpen(ctx, "all", match)
+}
+
+func (api *PoloniexApi) cancelOpen(ctx context.Context, currencyPair string, match func(string, OpenOrder) bool) (CancelReport, error) {
+	open, err := api.ApiPrivateOpenOrdersContext(ctx, currencyPair)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make(chan CancelResult)
+	results := make(chan CancelResult)
+
+	concurrency := CancelConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- api.cancelOne(ctx, job)
+			}
+		}()
+	}
+
+	go func() {
+		for pair, orders := range open {
+			for _, order := range orders {
+				if match == nil || match(pair, order) {
+					jobs <- CancelResult{Pair: pair, Order: order}
+				}
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	report := CancelReport{}
+	for result := range results {
+		report = append(report, result)
+	}
+
+	return report, nil
+}
+
+func (api *PoloniexApi) cancelOne(ctx context.Context, result CancelResult) CancelResult {
+	orderNumber, err := strconv.ParseInt(result.Order.OrderNumber, 10, 64)
+	if err != nil {
+		result.Status, result.Err = CancelFailed, err
+		return result
+	}
+
+	attempts := CancelAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := CancelRetryDelay
+	ambiguous := false
+
+	for result.Attempts < attempts {
+		result.Attempts++
+		if result.Attempts > 1 {
+			api.observeRetry(CMD_PRIVATE_CANCEL_ORDER)
+		}
+
+		var ok bool
+		var cancel *CancelOrder
+		ok, cancel, err = api.ApiPrivateCancelContext(withAttempt(ctx, result.Attempts-1), orderNumber)
+		if err == nil && ok {
+			result.Status, result.Err = CancelDone, nil
+			return result
+		}
+
+		if err == nil {
+			err = &ApiError{Command: CMD_PRIVATE_CANCEL_ORDER, Message: cancel.Message}
+		}
+
+		var apiError *ApiError
+		switch {
+		case IsNonceError(err) || IsRateLimitError(err):
+			// Refused before being looked at: safe to send again.
+		case errors.As(err, &apiError):
+			return api.classifyRefused(ctx, result, ambiguous, err)
+		default:
+			ambiguous = true
+		}
+
+		result.Status, result.Err = CancelFailed, err
+
+		if result.Attempts < attempts {
+			if sleepContext(ctx, delay) != nil {
+				return result
+			}
+			delay *= 2
+		}
+	}
+
+	return result
+}
+
+/*
+classifyRefused finds out why the exchange refused to cancel an order: it is
+either filled, or not open anymore for another reason (cancelled elsewhere,
+or by an earlier attempt whose answer was lost). Orders still open, or whose
+state cannot be fetched, are reported as failed.
+*/
+func (api *PoloniexApi) classifyRefused(ctx context.Context, result CancelResult, ambiguous bool, refused error) CancelResult {
+	trades, err := api.ApiPrivateOrderTradesContext(ctx, result.Order.OrderNumber)
+
+	var apiError *ApiError
+	if err != nil && !errors.As(err, &apiError) {
+		result.Status, result.Err = CancelFailed, refused
+		return result
+	}
+
+	filled := 0.0
+	for _, t := range trades {
+		filled += t.Amount
+	}
+
+	amount := result.Order.StartingAmount
+	if amount == 0 {
+		amount = result.Order.Amount
+	}
+
+	if filled >= amount-amountEpsilon {
+		result.Status, result.Err = CancelAlreadyFilled, nil
+		return result
+	}
+
+	open, err := api.ApiPrivateOpenOrdersContext(ctx, result.Pair)
+	if err != nil {
+		result.Status, result.Err = CancelFailed, refused
+		return result
+	}
+
+	for _, o := range open[result.Pair] {
+		if o.OrderNumber == result.Order.OrderNumber {
+			result.Status, result.Err = CancelFailed, refused
+			return result
+		}
+	}
+
+	if ambiguous {
+		result.Status, result.Err = CancelDone, nil
+	} else {
+		result.Status, result.Err = CancelNotFound, refused
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+package poloniexapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCancelAll(t *testing.T) {
+	delay := CancelRetryDelay
+	CancelRetryDelay = time.Millisecond
+	defer func() { CancelRetryDelay = delay }()
+
+	const garbage = "<html>502 Bad Gateway</html>"
+
+	cases := map[string]struct {
+		answers  []string // to the successive cancels
+		trades   string
+		open     bool // still open after the cancels
+		status   CancelStatus
+		attempts int
+	}{
+		"1": {[]string{`{"success":1,"amount":"1","message":"Order #1 canceled."}`}, "", false, CancelDone, 1},
+		"2": {[]string{`{"error":"Nonce must be greater than 2. You provided 1."}`, `{"success":1}`}, "", false, CancelDone, 2},
+		"3": {[]string{`{"error":"Please do not make more than 6 API calls per second."}`, `{"success":1}`}, "", false, CancelDone, 2},
+		"4": {[]string{`{"success":0,"message":"Order #4 could not be canceled."}`}, "", true, CancelFailed, 1},
+		"5": {[]string{`{"error":"Invalid order number, or you are not the person who placed the order."}`}, `[{"tradeID":1,"amount":"1"}]`, false, CancelAlreadyFilled, 1},
+		"6": {[]string{`{"error":"Invalid order number, or you are not the person who placed the order."}`}, "", false, CancelNotFound, 1},
+		"7": {[]string{`{"error":"Something unexpected."}`}, "", true, CancelFailed, 1},
+		"8": {[]string{garbage, `{"error":"Invalid order number, or you are not the person who placed the order."}`}, "", false, CancelDone, 2},
+		"9": {[]string{garbage, garbage, garbage}, "", true, CancelFailed, 3},
+	}
+
+	var mu sync.Mutex
+	sent := make(map[string]int)
+	listed := false
+
+	api := testApi(func(command string, params url.Values) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		number := params.Get("orderNumber")
+
+		switch command {
+		case CMD_PRIVATE_OPEN_ORDERS:
+			var orders []string
+			for n, c := range cases {
+				if !listed || c.open {
+					orders = append(orders, fmt.Sprintf(`{"orderNumber":%q,"type":"buy","rate":"0.0125","startingAmount":"1","amount":"1","total":"0.0125","date":"2018-03-01 12:00:00","margin":0}`, n))
+				}
+			}
+			listed = true
+			return `[` + strings.Join(orders, ",") + `]`
+
+		case CMD_PRIVATE_CANCEL_ORDER:
+			sent[number]++
+			return cases[number].answers[sent[number]-1]
+
+		case CMD_PRIVATE_ORDER_TRADES:
+			if trades := cases[number].trades; trades != "" {
+				return trades
+			}
+			return `{"error":"Order not found, or you are not the person who placed it."}`
+		}
+
+		return `{"error":"unexpected command"}`
+	})
+
+	report, err := api.CancelAll("BTC_XMR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report) != len(cases) {
+		t.Fatalf("got %d results, want %d", len(report), len(cases))
+	}
+
+	for _, result := range report {
+		c := cases[result.Order.OrderNumber]
+		if result.Status != c.status || result.Attempts != c.attempts {
+			t.Errorf("order %s: got %s after %d attempts (%v), want %s after %d", result.Order.OrderNumber, result.Status, result.Attempts, result.Err, c.status, c.attempts)
+		}
+	}
+
+	if failed := report.Failed(); len(failed) != 3 {
+		t.Errorf("got %d failed, want 3", len(failed))
+	}
+}
+
+func TestCancelAllContext(t *testing.T) {
+	delay := CancelRetryDelay
+	CancelRetryDelay = time.Hour
+	defer func() { CancelRetryDelay = delay }()
+
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_OPEN_ORDERS:
+			return `[{"orderNumber":"1","type":"buy","rate":"0.0125","startingAmount":"1","amount":"1","total":"0.0125","date":"2018-03-01 12:00:00","margin":0}]`
+		case CMD_PRIVATE_CANCEL_ORDER:
+			return "<html>502 Bad Gateway</html>"
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	report, err := api.CancelAllContext(ctx, "BTC_XMR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %s, want as soon as the context is done", elapsed)
+	}
+
+	if len(report) != 1 || report[0].Status != CancelFailed || report[0].Attempts != 1 {
+		t.Errorf("got %+v, want one failed attempt", report)
+	}
+}
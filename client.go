@@ -1,6 +1,7 @@
 package poloniexapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,18 +10,46 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (api *PoloniexApi) query(url string, params url.Values, with_signature bool) ([]byte, error) {
+func (api *PoloniexApi) query(ctx context.Context, url string, params url.Values, with_signature bool) ([]byte, error) {
 	headers := map[string]string{}
 	method := "GET"
 
+	command := params.Get("command")
+	dryRun := api.DryRun && with_signature && isStateChanging(command)
+
+	ctx, span := api.startSpan(ctx, command, params)
+
+	// Waiting before signing keeps nonces in the order requests are sent.
+	if api.Limiter != nil && !dryRun {
+		wait, err := api.Limiter.Wait(ctx)
+		if api.Observer != nil {
+			api.Observer.LimiterWaited(command, wait)
+		}
+		limiterWaited(span, wait)
+
+		if err != nil {
+			endSpan(span, 0, err)
+			return nil, err
+		}
+	}
+
 	if with_signature {
+		key, secret, err := api.getCredentials()
+		if err != nil {
+			endSpan(span, 0, err)
+			return nil, err
+		}
+
 		params.Set("nonce", fmt.Sprintf("%d", time.Now().UnixNano()/1000))
 
-		signature := createPoloniexSignature(params, api.secret)
+		signature := createPoloniexSignature(params, secret)
+		wipe(secret)
 
-		headers["Key"] = api.Key
+		headers["Key"] = key
 		headers["Sign"] = signature
 
 		method = "POST"
@@ -32,7 +61,38 @@ func (api *PoloniexApi) query(url string, params url.Values, with_signature bool
 
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	return executeHttpQuery(method, url, headers, params)
+	if dryRun {
+		if span != nil {
+			span.SetAttributes(attribute.Bool("poloniex.dry_run", true))
+		}
+		body, err := dryRunQuery(params)
+		endSpan(span, 0, err)
+		return body, err
+	}
+
+	client := api.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	start := time.Now()
+
+	body, status, err := executeHttpQuery(ctx, client, method, url, headers, params)
+	if err != nil {
+		err = &RequestError{Command: command, Err: err}
+		api.observeRequest(command, start, err)
+		endSpan(span, status, err)
+		return nil, err
+	}
+
+	err = checkApiError(command, body)
+	api.observeRequest(command, start, err)
+	endSpan(span, status, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
 }
 
 func (api *PoloniexApi) parse(resp []byte, out interface{}) (interface{}, error) {
@@ -50,10 +110,19 @@ func (api *PoloniexApi) parse(resp []byte, out interface{}) (interface{}, error)
 	return response, nil
 }
 
-func (api *PoloniexApi) queryparse(url string, params url.Values, with_signature bool, out interface{}) (interface{}, error) {
+func (api *PoloniexApi) queryparse(ctx context.Context, url string, params url.Values, with_signature bool, out interface{}) (interface{}, error) {
 	var response interface{}
 
-	resp, err := api.query(url, params, with_signature)
+	var resp []byte
+	var err error
+
+	if with_signature {
+		resp, err = api.query(ctx, url, params, true)
+	} else {
+		resp, err = api.Cache.get(params, func() ([]byte, error) {
+			return api.query(ctx, url, params, false)
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -62,22 +131,22 @@ func (api *PoloniexApi) queryparse(url string, params url.Values, with_signature
 		response = out
 	}
 
-	fmt.Println("Response:")
-	fmt.Println(string(resp))
+	if api.Debug {
+		fmt.Println("Response:")
+		fmt.Println(string(resp))
+	}
 
 	_, err = api.parse(resp, &response)
 	if err != nil {
-		return nil, err
+		return nil, &RequestError{Command: params.Get("command"), Err: err}
 	}
 
 	return response, err
 }
 
-func executeHttpQuery(method string, url string, headers map[string]string, values url.Values) ([]byte, error) {
+func executeHttpQuery(ctx context.Context, client *http.Client, method string, url string, headers map[string]string, values url.Values) ([]byte, int, error) {
 	var bodyReader io.Reader
 
-	client := &http.Client{}
-
 	if method == "GET" {
 		bodyReader = nil
 		url = url + "?" + values.Encode()
@@ -85,9 +154,9 @@ func executeHttpQuery(method string, url string, headers map[string]string, valu
 		bodyReader = strings.NewReader(values.Encode())
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("Could not execute request! (%s)", err.Error())
+		return nil, 0, fmt.Errorf("Could not execute request! (%s)", err.Error())
 	}
 
 	for key, value := range headers {
@@ -96,14 +165,14 @@ func executeHttpQuery(method string, url string, headers map[string]string, valu
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("Could not execute request! (%s)", err.Error())
+		return nil, 0, fmt.Errorf("Could not execute request! (%s)", err.Error())
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("Could not execute request! (%s)", err.Error())
+		return nil, resp.StatusCode, fmt.Errorf("Could not execute request! (%s)", err.Error())
 	}
 
-	return body, nil
+	return body, resp.StatusCode, nil
 }
@@ -0,0 +1,71 @@
+// Command poloniex-alert checks alerting rules on the account and sends the
+// alerts they raise to the configured notifiers.
+//
+// Usage:
+//
+//	poloniex-alert -rules alerts.yaml [-interval 1m]
+//
+// Credentials are read from $POLONIEX_API_KEY and $POLONIEX_API_SECRET, then
+// from the keystore if given, then from the configuration file. See package
+// alert for the rules file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/alert"
+	"github.com/mycroft/poloniex-api/credentials"
+)
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "poloniex-alert: %s\n", err.Error())
+	os.Exit(1)
+}
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the credentials file (see config.json.sample)")
+	keystorePath := flag.String("keystore", "", "path to an encrypted keystore, its passphrase is prompted")
+	rulesPath := flag.String("rules", "alerts.yaml", "path to the rules and notifiers")
+	interval := flag.Duration("interval", time.Minute, "interval between checks")
+	flag.Parse()
+
+	provider := credentials.Chain{credentials.Env{}}
+	if *keystorePath != "" {
+		provider = append(provider, &credentials.Keystore{
+			Path:       *keystorePath,
+			Passphrase: credentials.CachePassphrase(credentials.PromptPassphrase),
+		})
+	}
+	provider = append(provider, credentials.File{Path: *configPath})
+
+	api, err := poloniexapi.NewFromCredentials(provider)
+	if err != nil {
+		fatal(err)
+	}
+
+	config, err := alert.LoadConfig(*rulesPath)
+	if err != nil {
+		fatal(err)
+	}
+
+	engine, err := config.Engine(api)
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	log.Printf("Checking %d rules every %s", len(config.Rules), *interval)
+
+	engine.Run(ctx, *interval, func(err error) {
+		log.Printf("Check failed: %s", err.Error())
+	})
+}
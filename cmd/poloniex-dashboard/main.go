@@ -0,0 +1,170 @@
+// Command poloniex-dashboard is an interactive terminal dashboard showing the
+// Poloniex tickers, the order book of a selected market, and your open orders
+// and balances.
+//
+// Keys:
+//
+//	tab        switch focus between the ticker list and your orders
+//	up/down    move the cursor (also k/j)
+//	enter      show the order book of the selected market
+//	c          cancel the selected order
+//	m          move the selected order to a new rate
+//	r          refresh orders and balances now
+//	q          quit
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/credentials"
+)
+
+// feeds owns the polling goroutines and forwards their results to the
+// program as messages.
+type feeds struct {
+	api     *poloniexapi.PoloniexApi
+	program *tea.Program
+	ctx     context.Context
+	depth   int
+
+	tickerInterval  time.Duration
+	bookInterval    time.Duration
+	privateInterval time.Duration
+
+	mu         sync.Mutex
+	bookCancel context.CancelFunc
+}
+
+func (f *feeds) start() {
+	go func() {
+		for ev := range f.api.PollTicker(f.ctx, f.tickerInterval) {
+			f.program.Send(tickerMsg(ev))
+		}
+	}()
+
+	if f.api.Key != "" {
+		go func() {
+			ticker := time.NewTicker(f.privateInterval)
+			defer ticker.Stop()
+
+			for {
+				f.program.Send(f.fetchPrivate())
+
+				select {
+				case <-ticker.C:
+				case <-f.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// watchBook replaces the order book feed by one for pair.
+func (f *feeds) watchBook(pair string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.bookCancel != nil {
+		f.bookCancel()
+	}
+
+	ctx, cancel := context.WithCancel(f.ctx)
+	f.bookCancel = cancel
+
+	go func() {
+		for ev := range f.api.PollOrderBook(ctx, pair, f.depth, f.bookInterval) {
+			f.program.Send(bookMsg(ev))
+		}
+	}()
+}
+
+func (f *feeds) fetchPrivate() privateMsg {
+	orders, err := f.api.ApiPrivateOpenOrders("all")
+	if err != nil {
+		return privateMsg{err: err}
+	}
+
+	balances, err := f.api.ApiPrivateCompleteBalances(false)
+	if err != nil {
+		return privateMsg{err: err}
+	}
+
+	return privateMsg{orders: orders, balances: balances}
+}
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the credentials file (see config.json.sample)")
+	keystorePath := flag.String("keystore", "", "path to an encrypted keystore, its passphrase is prompted")
+	pair := flag.String("pair", "BTC_ETH", "market whose order book is shown at start")
+	depth := flag.Int("depth", 15, "order book depth")
+	tickerInterval := flag.Duration("ticker-interval", 5*time.Second, "ticker refresh interval")
+	bookInterval := flag.Duration("book-interval", 2*time.Second, "order book refresh interval")
+	privateInterval := flag.Duration("private-interval", 10*time.Second, "orders and balances refresh interval")
+	dryRun := flag.Bool("dry-run", false, "log cancels and moves instead of sending them")
+	logPath := flag.String("log", "", "file receiving log messages, which would otherwise garble the screen")
+	flag.Parse()
+
+	log.SetOutput(ioutil.Discard)
+	if *logPath != "" {
+		logFile, err := os.OpenFile(*logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "poloniex-dashboard: %s\n", err.Error())
+			os.Exit(1)
+		}
+		defer logFile.Close()
+		log.SetOutput(logFile)
+	}
+
+	provider := credentials.Chain{credentials.Env{}}
+	if *keystorePath != "" {
+		provider = append(provider, &credentials.Keystore{
+			Path:       *keystorePath,
+			Passphrase: credentials.CachePassphrase(credentials.PromptPassphrase),
+		})
+	}
+	provider = append(provider, credentials.File{Path: *configPath})
+
+	// Without credentials the dashboard still shows public data.
+	api, err := poloniexapi.NewFromCredentials(provider)
+	if errors.Is(err, credentials.ErrNotFound) {
+		api = poloniexapi.New("", "")
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "poloniex-dashboard: %s\n", err.Error())
+		os.Exit(1)
+	}
+	api.DryRun = *dryRun
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := &feeds{
+		api:             api,
+		ctx:             ctx,
+		depth:           *depth,
+		tickerInterval:  *tickerInterval,
+		bookInterval:    *bookInterval,
+		privateInterval: *privateInterval,
+	}
+
+	m := newModel(api, f, *pair)
+	f.program = tea.NewProgram(m, tea.WithAltScreen())
+
+	f.start()
+	f.watchBook(*pair)
+
+	if _, err := f.program.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "poloniex-dashboard: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+type tickerMsg poloniexapi.TickerEvent
+type bookMsg poloniexapi.OrderBookEvent
+
+type privateMsg struct {
+	orders   map[string][]poloniexapi.OpenOrder
+	balances map[string]poloniexapi.Balance
+	err      error
+}
+
+type statusMsg struct {
+	text    string
+	refresh bool
+}
+
+type focus int
+
+const (
+	focusTickers focus = iota
+	focusOrders
+)
+
+type orderRow struct {
+	pair  string
+	order poloniexapi.OpenOrder
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	headerStyle   = lipgloss.NewStyle().Faint(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	askStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	bidStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	panelStyle    = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+)
+
+type model struct {
+	api   *poloniexapi.PoloniexApi
+	feeds *feeds
+
+	width, height int
+	focus         focus
+
+	tickers      map[string]poloniexapi.Ticker
+	pairs        []string
+	tickerCursor int
+	tickerTime   time.Time
+
+	bookPair string
+	book     poloniexapi.OrderBookEntry
+
+	orders      []orderRow
+	orderCursor int
+	balances    map[string]poloniexapi.Balance
+
+	// Non-empty while the user is typing the new rate of a moved order.
+	moving *orderRow
+	input  string
+
+	status string
+}
+
+func newModel(api *poloniexapi.PoloniexApi, f *feeds, pair string) *model {
+	return &model{
+		api:      api,
+		feeds:    f,
+		bookPair: pair,
+		status:   "loading...",
+	}
+}
+
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tickerMsg:
+		if msg.Err != nil {
+			m.status = "ticker: " + msg.Err.Error()
+			break
+		}
+		m.tickers = msg.Tickers
+		m.tickerTime = msg.Time
+		m.pairs = m.pairs[:0]
+		for pair := range msg.Tickers {
+			m.pairs = append(m.pairs, pair)
+		}
+		sort.Strings(m.pairs)
+		m.tickerCursor = clamp(m.tickerCursor, len(m.pairs))
+		if m.status == "loading..." {
+			m.status = ""
+		}
+
+	case bookMsg:
+		if msg.Pair != m.bookPair {
+			break
+		}
+		if msg.Err != nil {
+			m.status = "book: " + msg.Err.Error()
+			break
+		}
+		m.book = msg.Book
+
+	case privateMsg:
+		if msg.err != nil {
+			m.status = "account: " + msg.err.Error()
+			break
+		}
+		m.balances = msg.balances
+		m.orders = m.orders[:0]
+		for pair, orders := range msg.orders {
+			for _, o := range orders {
+				m.orders = append(m.orders, orderRow{pair, o})
+			}
+		}
+		sort.Slice(m.orders, func(i, j int) bool {
+			if m.orders[i].pair != m.orders[j].pair {
+				return m.orders[i].pair < m.orders[j].pair
+			}
+			return m.orders[i].order.OrderNumber < m.orders[j].order.OrderNumber
+		})
+		m.orderCursor = clamp(m.orderCursor, len(m.orders))
+
+	case statusMsg:
+		m.status = msg.text
+		if msg.refresh {
+			return m, m.refresh()
+		}
+
+	case tea.KeyMsg:
+		if m.moving != nil {
+			return m.updateMoveInput(msg)
+		}
+		return m.updateKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m *model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "tab":
+		if m.focus == focusTickers && m.api.Key != "" {
+			m.focus = focusOrders
+		} else {
+			m.focus = focusTickers
+		}
+
+	case "up", "k":
+		if m.focus == focusTickers {
+			m.tickerCursor = clamp(m.tickerCursor-1, len(m.pairs))
+		} else {
+			m.orderCursor = clamp(m.orderCursor-1, len(m.orders))
+		}
+
+	case "down", "j":
+		if m.focus == focusTickers {
+			m.tickerCursor = clamp(m.tickerCursor+1, len(m.pairs))
+		} else {
+			m.orderCursor = clamp(m.orderCursor+1, len(m.orders))
+		}
+
+	case "enter":
+		if m.focus == focusTickers && len(m.pairs) != 0 {
+			m.bookPair = m.pairs[m.tickerCursor]
+			m.book = poloniexapi.OrderBookEntry{}
+			m.feeds.watchBook(m.bookPair)
+		}
+
+	case "r":
+		if m.api.Key != "" {
+			return m, m.refresh()
+		}
+
+	case "c":
+		if row := m.selectedOrder(); row != nil {
+			return m, m.cancel(*row)
+		}
+
+	case "m":
+		if row := m.selectedOrder(); row != nil {
+			m.moving = row
+			m.input = ""
+		}
+	}
+
+	return m, nil
+}
+
+func (m *model) updateMoveInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.moving = nil
+
+	case tea.KeyEnter:
+		row := *m.moving
+		m.moving = nil
+
+		rate, err := strconv.ParseFloat(m.input, 64)
+		if err != nil || rate <= 0 {
+			m.status = fmt.Sprintf("invalid rate %q", m.input)
+			break
+		}
+		return m, m.move(row, rate)
+
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if (r >= '0' && r <= '9') || r == '.' {
+				m.input += string(r)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *model) selectedOrder() *orderRow {
+	if m.focus != focusOrders || len(m.orders) == 0 {
+		return nil
+	}
+
+	row := m.orders[m.orderCursor]
+	return &row
+}
+
+func (m *model) refresh() tea.Cmd {
+	return func() tea.Msg {
+		return m.feeds.fetchPrivate()
+	}
+}
+
+func (m *model) cancel(row orderRow) tea.Cmd {
+	return func() tea.Msg {
+		orderNumber, err := strconv.ParseInt(row.order.OrderNumber, 10, 64)
+		if err != nil {
+			return statusMsg{text: err.Error()}
+		}
+
+		_, out, err := m.api.ApiPrivateCancel(orderNumber)
+		if err != nil {
+			return statusMsg{text: "cancel: " + err.Error()}
+		}
+
+		return statusMsg{text: fmt.Sprintf("order %s cancelled: %s", row.order.OrderNumber, out.Message), refresh: true}
+	}
+}
+
+func (m *model) move(row orderRow, rate float64) tea.Cmd {
+	return func() tea.Msg {
+		orderNumber, err := strconv.ParseInt(row.order.OrderNumber, 10, 64)
+		if err != nil {
+			return statusMsg{text: err.Error()}
+		}
+
+		out, err := m.api.ApiPrivateMoveOrder(orderNumber, rate, 0, nil)
+		if err != nil {
+			return statusMsg{text: "move: " + err.Error()}
+		}
+
+		return statusMsg{text: fmt.Sprintf("order %s moved to %s as %d", row.order.OrderNumber, formatFloat(rate), out.OrderNumber), refresh: true}
+	}
+}
+
+func (m *model) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+
+	// Leave room for the bottom panels, borders and the status line.
+	bodyHeight := m.height - 4
+	if m.api.Key != "" {
+		bodyHeight = m.height/2 - 2
+	}
+	if bodyHeight < 3 {
+		bodyHeight = 3
+	}
+
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		panelStyle.Render(m.viewTickers(bodyHeight)),
+		panelStyle.Render(m.viewBook(bodyHeight)),
+	)
+
+	parts := []string{top}
+
+	if m.api.Key != "" {
+		bottomHeight := m.height - bodyHeight - 6
+		if bottomHeight < 3 {
+			bottomHeight = 3
+		}
+		parts = append(parts, lipgloss.JoinHorizontal(lipgloss.Top,
+			panelStyle.Render(m.viewOrders(bottomHeight)),
+			panelStyle.Render(m.viewBalances(bottomHeight)),
+		))
+	}
+
+	parts = append(parts, m.viewStatus())
+
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (m *model) viewTickers(height int) string {
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Tickers (%s)", m.tickerTime.Format("15:04:05"))),
+		headerStyle.Render(fmt.Sprintf("%-12s %14s %14s %14s %8s %12s", "pair", "last", "bid", "ask", "change", "volume")),
+	}
+
+	first, last := window(m.tickerCursor, len(m.pairs), height-2)
+	for i := first; i < last; i++ {
+		t := m.tickers[m.pairs[i]]
+		line := fmt.Sprintf("%-12s %14s %14s %14s %7.2f%% %12.3f",
+			m.pairs[i], formatFloat(t.Last), formatFloat(t.HighestBid), formatFloat(t.LowestAsk),
+			t.PercentChange*100, t.BaseVolume)
+		if i == m.tickerCursor && m.focus == focusTickers {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *model) viewBook(height int) string {
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Order book %s (seq %.0f)", m.bookPair, m.book.Seq)),
+		headerStyle.Render(fmt.Sprintf("%14s %16s", "rate", "amount")),
+	}
+
+	side := (height - 2) / 2
+	asks := m.book.Asks
+	if len(asks) > side {
+		asks = asks[:side]
+	}
+	for i := len(asks) - 1; i >= 0; i-- {
+		lines = append(lines, askStyle.Render(fmt.Sprintf("%14s %16s", formatFloat(asks[i][0]), formatFloat(asks[i][1]))))
+	}
+
+	bids := m.book.Bids
+	if len(bids) > side {
+		bids = bids[:side]
+	}
+	for _, bid := range bids {
+		lines = append(lines, bidStyle.Render(fmt.Sprintf("%14s %16s", formatFloat(bid[0]), formatFloat(bid[1]))))
+	}
+
+	if m.book.IsFrozen != 0 {
+		lines = append(lines, "market is frozen")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *model) viewOrders(height int) string {
+	lines := []string{
+		titleStyle.Render("Open orders"),
+		headerStyle.Render(fmt.Sprintf("%-12s %-14s %-4s %14s %16s %16s", "pair", "order", "type", "rate", "amount", "total")),
+	}
+
+	first, last := window(m.orderCursor, len(m.orders), height-2)
+	for i := first; i < last; i++ {
+		row := m.orders[i]
+		line := fmt.Sprintf("%-12s %-14s %-4s %14s %16s %16s",
+			row.pair, row.order.OrderNumber, row.order.Type,
+			formatFloat(row.order.Rate), formatFloat(row.order.Amount), formatFloat(row.order.Total))
+		if i == m.orderCursor && m.focus == focusOrders {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *model) viewBalances(height int) string {
+	lines := []string{
+		titleStyle.Render("Balances"),
+		headerStyle.Render(fmt.Sprintf("%-6s %16s %16s %14s", "coin", "available", "on orders", "btc value")),
+	}
+
+	currencies := make([]string, 0)
+	for currency, b := range m.balances {
+		if b.Available != 0.0 || b.OnOrders != 0.0 {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+
+	for i, currency := range currencies {
+		if i >= height-2 {
+			break
+		}
+		b := m.balances[currency]
+		lines = append(lines, fmt.Sprintf("%-6s %16s %16s %14s",
+			currency, formatFloat(b.Available), formatFloat(b.OnOrders), formatFloat(b.BtcValue)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *model) viewStatus() string {
+	if m.moving != nil {
+		return fmt.Sprintf("move order %s (%s %s @ %s) to rate: %s_",
+			m.moving.order.OrderNumber, m.moving.order.Type, m.moving.pair,
+			formatFloat(m.moving.order.Rate), m.input)
+	}
+
+	help := "tab focus  ↑/↓ move  enter book  q quit"
+	if m.api.Key != "" {
+		help = "tab focus  ↑/↓ move  enter book  c cancel  m move  r refresh  q quit"
+	}
+
+	if m.status != "" {
+		return m.status + "  |  " + headerStyle.Render(help)
+	}
+
+	return headerStyle.Render(help)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 8, 64)
+}
+
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+
+	return i
+}
+
+// window returns the range of rows to display so that cursor stays visible.
+func window(cursor, n, height int) (int, int) {
+	if height < 1 {
+		height = 1
+	}
+
+	first := 0
+	if cursor >= height {
+		first = cursor - height + 1
+	}
+
+	last := first + height
+	if last > n {
+		last = n
+	}
+
+	return first, last
+}
@@ -0,0 +1,90 @@
+// Command poloniex-exporter serves Prometheus metrics on a Poloniex account:
+// balances by currency, last prices, and optionally open orders and the margin
+// account, along with the request metrics of the client polling them.
+//
+// Usage:
+//
+//	poloniex-exporter [-listen :9747] [-interval 1m] [-quote USDT] [-orders] [-margin]
+//
+// Credentials are read from $POLONIEX_API_KEY and $POLONIEX_API_SECRET, then
+// from the keystore if given, then from the configuration file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/credentials"
+	"github.com/mycroft/poloniex-api/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "poloniex-exporter: %s\n", err.Error())
+	os.Exit(1)
+}
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the credentials file (see config.json.sample)")
+	keystorePath := flag.String("keystore", "", "path to an encrypted keystore, its passphrase is prompted")
+	listen := flag.String("listen", ":9747", "address serving /metrics")
+	interval := flag.Duration("interval", time.Minute, "account refresh interval")
+	quote := flag.String("quote", "", "also export the account value in this currency")
+	orders := flag.Bool("orders", false, "export the number of open orders by market")
+	margin := flag.Bool("margin", false, "export the margin account summary")
+	flag.Parse()
+
+	provider := credentials.Chain{credentials.Env{}}
+	if *keystorePath != "" {
+		provider = append(provider, &credentials.Keystore{
+			Path:       *keystorePath,
+			Passphrase: credentials.CachePassphrase(credentials.PromptPassphrase),
+		})
+	}
+	provider = append(provider, credentials.File{Path: *configPath})
+
+	api, err := poloniexapi.NewFromCredentials(provider)
+	if err != nil {
+		fatal(err)
+	}
+
+	client := metrics.NewClient()
+	api.Observer = client
+
+	account := metrics.NewAccount(api)
+	account.Quote = *quote
+	account.OpenOrders = *orders
+	account.Margin = *margin
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(client, account, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go account.Run(ctx, *interval, func(err error) {
+		log.Printf("Could not update account metrics (%s)", err.Error())
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{Addr: *listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err = server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+// Command poloniex-gateway serves the Poloniex API as JSON over HTTP to local
+// tools, holding the credentials and rate-limiting their requests.
+//
+// Usage:
+//
+//	poloniex-gateway -tokens tokens.json [-listen 127.0.0.1:8470] [-dry-run]
+//
+// Credentials are read from $POLONIEX_API_KEY and $POLONIEX_API_SECRET, then
+// from the keystore if given, then from the configuration file. See package
+// gateway for the endpoints and the tokens file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/credentials"
+	"github.com/mycroft/poloniex-api/gateway"
+)
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "poloniex-gateway: %s\n", err.Error())
+	os.Exit(1)
+}
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the credentials file (see config.json.sample)")
+	keystorePath := flag.String("keystore", "", "path to an encrypted keystore, its passphrase is prompted")
+	tokensPath := flag.String("tokens", "tokens.json", "path to the client tokens")
+	listen := flag.String("listen", "127.0.0.1:8470", "address to serve on")
+	dryRun := flag.Bool("dry-run", false, "log orders and cancels instead of sending them")
+	flag.Parse()
+
+	provider := credentials.Chain{credentials.Env{}}
+	if *keystorePath != "" {
+		provider = append(provider, &credentials.Keystore{
+			Path:       *keystorePath,
+			Passphrase: credentials.CachePassphrase(credentials.PromptPassphrase),
+		})
+	}
+	provider = append(provider, credentials.File{Path: *configPath})
+
+	api, err := poloniexapi.NewFromCredentials(provider)
+	if err != nil {
+		fatal(err)
+	}
+	api.DryRun = *dryRun
+
+	tokens, err := gateway.LoadTokens(*tokensPath)
+	if err != nil {
+		fatal(err)
+	}
+
+	server, err := gateway.NewServer(api, tokens)
+	if err != nil {
+		fatal(err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *listen,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdown)
+	}()
+
+	log.Printf("Serving %d tokens on %s", len(tokens), *listen)
+
+	if err = httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fatal(err)
+	}
+}
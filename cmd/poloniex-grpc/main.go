@@ -0,0 +1,113 @@
+// Command poloniex-grpc serves the Poloniex API over gRPC, holding the
+// credentials and rate-limiting the requests of its clients.
+//
+// Usage:
+//
+//	poloniex-grpc -tokens tokens.json [-listen 127.0.0.1:8471] [-tls-cert cert.pem -tls-key key.pem] [-dry-run]
+//
+// Credentials are read from $POLONIEX_API_KEY and $POLONIEX_API_SECRET, then
+// from the keystore if given, then from the configuration file. Clients send
+// a token of the tokens file, in the format of poloniex-gateway; serve with
+// TLS beyond the loopback, since tokens are sent in the clear otherwise. See
+// package rpc for the service and its Go client.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/credentials"
+	"github.com/mycroft/poloniex-api/gateway"
+	"github.com/mycroft/poloniex-api/rpc"
+	"google.golang.org/grpc"
+	grpccredentials "google.golang.org/grpc/credentials"
+)
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "poloniex-grpc: %s\n", err.Error())
+	os.Exit(1)
+}
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the credentials file (see config.json.sample)")
+	keystorePath := flag.String("keystore", "", "path to an encrypted keystore, its passphrase is prompted")
+	tokensPath := flag.String("tokens", "tokens.json", "path to the client tokens")
+	listen := flag.String("listen", "127.0.0.1:8471", "address to serve on")
+	tlsCert := flag.String("tls-cert", "", "certificate to serve TLS with, with -tls-key")
+	tlsKey := flag.String("tls-key", "", "private key of -tls-cert")
+	dryRun := flag.Bool("dry-run", false, "log orders and cancels instead of sending them")
+	flag.Parse()
+
+	provider := credentials.Chain{credentials.Env{}}
+	if *keystorePath != "" {
+		provider = append(provider, &credentials.Keystore{
+			Path:       *keystorePath,
+			Passphrase: credentials.CachePassphrase(credentials.PromptPassphrase),
+		})
+	}
+	provider = append(provider, credentials.File{Path: *configPath})
+
+	api, err := poloniexapi.NewFromCredentials(provider)
+	if err != nil {
+		fatal(err)
+	}
+	api.DryRun = *dryRun
+
+	tokens, err := gateway.LoadTokens(*tokensPath)
+	if err != nil {
+		fatal(err)
+	}
+
+	auth, err := rpc.NewAuth(tokens)
+	if err != nil {
+		fatal(err)
+	}
+
+	opts := []grpc.ServerOption{grpc.UnaryInterceptor(auth.Unary), grpc.StreamInterceptor(auth.Stream)}
+
+	switch {
+	case *tlsCert != "" && *tlsKey != "":
+		creds, err := grpccredentials.NewServerTLSFromFile(*tlsCert, *tlsKey)
+		if err != nil {
+			fatal(err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	case *tlsCert != "" || *tlsKey != "":
+		fatal(fmt.Errorf("-tls-cert and -tls-key go together"))
+	default:
+		log.Printf("Serving without TLS: tokens are sent in the clear")
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fatal(err)
+	}
+
+	server := grpc.NewServer(opts...)
+	rpc.RegisterPoloniexServer(server, rpc.NewServer(api))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		// Streams only end with their clients, do not wait for them forever.
+		timer := time.AfterFunc(30*time.Second, server.Stop)
+		defer timer.Stop()
+		server.GracefulStop()
+	}()
+
+	log.Printf("Serving %d tokens on %s", len(tokens), *listen)
+
+	if err = server.Serve(listener); err != nil {
+		fatal(err)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/credentials"
+)
+
+func init() {
+	register(&command{
+		name:  "keystore",
+		usage: "keystore [-from config.json] <output>",
+		help:  "encrypt plaintext credentials into a keystore file",
+		run:   runKeystore,
+	})
+}
+
+func runKeystore(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("keystore", flag.ContinueOnError)
+	from := fs.String("from", "config.json", "plaintext credentials to encrypt")
+
+	pos, err := parseArgs(fs, args, "keystore [-from config.json] <output>", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	key, secret, err := credentials.File{Path: *from}.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	defer credentials.Wipe(secret)
+
+	passphrase, err := newPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	defer credentials.Wipe(passphrase)
+
+	if err = credentials.WriteKeystore(pos[0], key, secret, passphrase, nil); err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"keystore", "key"},
+		raw:    map[string]string{"keystore": pos[0], "key": key},
+	}
+	res.add(pos[0], key)
+
+	return res, nil
+}
+
+func newPassphrase() ([]byte, error) {
+	if _, ok := os.LookupEnv(passphraseVariable); ok {
+		return credentials.EnvPassphrase(passphraseVariable)()
+	}
+
+	passphrase, err := credentials.PromptPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprint(os.Stderr, "Again, ")
+	confirm, err := credentials.PromptPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	defer credentials.Wipe(confirm)
+
+	if !bytes.Equal(passphrase, confirm) {
+		credentials.Wipe(passphrase)
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+
+	return passphrase, nil
+}
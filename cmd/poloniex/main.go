@@ -0,0 +1,137 @@
+// Command poloniex is a command-line client for the Poloniex exchange built
+// on top of the poloniexapi package.
+//
+// Usage:
+//
+//	poloniex [-config config.json] [-keystore keystore.json] [-format table|json|csv] <command> [arguments]
+//
+// Credentials are read from $POLONIEX_API_KEY and $POLONIEX_API_SECRET, then
+// from the keystore if given, then from the configuration file.
+//
+// Run "poloniex help" for the list of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/credentials"
+)
+
+const passphraseVariable = "POLONIEX_KEYSTORE_PASSPHRASE"
+
+type command struct {
+	name    string
+	usage   string
+	help    string
+	private bool
+	run     func(api *poloniexapi.PoloniexApi, args []string) (*result, error)
+}
+
+var commands = map[string]*command{}
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+// credentialsProvider looks for credentials in the environment, then in the
+// keystore if one is given, then in the plaintext configuration file.
+func credentialsProvider(configPath, keystorePath string) credentials.Provider {
+	chain := credentials.Chain{credentials.Env{}}
+
+	if keystorePath != "" {
+		passphrase := credentials.PromptPassphrase
+		if _, ok := os.LookupEnv(passphraseVariable); ok {
+			passphrase = credentials.EnvPassphrase(passphraseVariable)
+		}
+
+		chain = append(chain, &credentials.Keystore{
+			Path:       keystorePath,
+			Passphrase: credentials.CachePassphrase(passphrase),
+		})
+	}
+
+	return append(chain, credentials.File{Path: configPath})
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: poloniex [flags] <command> [arguments]\n\nFlags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-55s %s\n", commands[name].usage, commands[name].help)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "poloniex: %s\n", err.Error())
+	os.Exit(1)
+}
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the credentials file (see config.json.sample)")
+	keystorePath := flag.String("keystore", "", "path to an encrypted keystore (passphrase from $"+passphraseVariable+" or prompted)")
+	format := flag.String("format", "table", "output format: table, json or csv")
+	debug := flag.Bool("debug", false, "dump raw API responses")
+	dryRun := flag.Bool("dry-run", false, "log orders, cancels and withdrawals instead of sending them")
+
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if flag.Arg(0) == "help" {
+		usage()
+		return
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "poloniex: unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	writer, err := newWriter(*format, os.Stdout)
+	if err != nil {
+		fatal(err)
+	}
+
+	// Public commands do not need credentials, but some of them (trades -mine)
+	// switch to private calls when they are available.
+	api, err := poloniexapi.NewFromCredentials(credentialsProvider(*configPath, *keystorePath))
+	if err != nil {
+		if cmd.private || !errors.Is(err, credentials.ErrNotFound) {
+			fatal(err)
+		}
+		api = poloniexapi.New("", "")
+	}
+	api.Debug = *debug
+	api.DryRun = *dryRun
+
+	// Commands may return a partial result along with an error.
+	res, err := cmd.run(api, flag.Args()[1:])
+	if res != nil {
+		if writeErr := writer.write(res); writeErr != nil {
+			fatal(writeErr)
+		}
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// result is what every command returns: a tabular view used for the table
+// and csv formats, and the raw API value used for the json format.
+type result struct {
+	header []string
+	rows   [][]string
+	raw    interface{}
+}
+
+func (r *result) add(fields ...string) {
+	r.rows = append(r.rows, fields)
+}
+
+type writer interface {
+	write(res *result) error
+}
+
+func newWriter(format string, out io.Writer) (writer, error) {
+	switch format {
+	case "table":
+		return &tableWriter{out}, nil
+	case "json":
+		return &jsonWriter{out}, nil
+	case "csv":
+		return &csvWriter{out}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown output format %q (expected table, json or csv)", format)
+}
+
+type tableWriter struct {
+	out io.Writer
+}
+
+func (w *tableWriter) write(res *result) error {
+	tw := tabwriter.NewWriter(w.out, 0, 4, 2, ' ', 0)
+
+	if len(res.header) != 0 {
+		fmt.Fprintln(tw, strings.Join(res.header, "\t"))
+	}
+	for _, row := range res.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+type jsonWriter struct {
+	out io.Writer
+}
+
+func (w *jsonWriter) write(res *result) error {
+	enc := json.NewEncoder(w.out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(res.raw)
+}
+
+type csvWriter struct {
+	out io.Writer
+}
+
+func (w *csvWriter) write(res *result) error {
+	cw := csv.NewWriter(w.out)
+
+	if len(res.header) != 0 {
+		if err := cw.Write(res.header); err != nil {
+			return err
+		}
+	}
+	if err := cw.WriteAll(res.rows); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 8, 64)
+}
+
+func formatInt(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
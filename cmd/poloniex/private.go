@@ -0,0 +1,642 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/tax"
+)
+
+func init() {
+	register(&command{
+		name:    "balances",
+		usage:   "balances [-all] [-zero]",
+		help:    "show your balances",
+		private: true,
+		run:     runBalances,
+	})
+	register(&command{
+		name:    "portfolio",
+		usage:   "portfolio [-quote currency]",
+		help:    "value all your accounts in one currency",
+		private: true,
+		run:     runPortfolio,
+	})
+	register(&command{
+		name:    "pnl",
+		usage:   "pnl [-method fifo|lifo|average] [-period all|day|month|year] [-days n]",
+		help:    "show realized and unrealized profit and loss",
+		private: true,
+		run:     runPnL,
+	})
+	register(&command{
+		name:    "tax",
+		usage:   "tax [-layout generic|koinly|cointracking|gains] [-method fifo|lifo|average] [-quote currency] [-from YYYY-MM-DD] [-to YYYY-MM-DD]",
+		help:    "export trades, transfers and lending as CSV for accounting",
+		private: true,
+		run:     runTax,
+	})
+	register(&command{
+		name:    "orders",
+		usage:   "orders [pair]",
+		help:    "show your open orders",
+		private: true,
+		run:     runOrders,
+	})
+	register(&command{
+		name:    "buy",
+		usage:   "buy [-fok|-ioc] [-postonly] [-clientid id] <pair> <rate> <amount>",
+		help:    "place a limit buy order",
+		private: true,
+		run:     runBuy,
+	})
+	register(&command{
+		name:    "sell",
+		usage:   "sell [-fok|-ioc] [-postonly] [-clientid id] <pair> <rate> <amount>",
+		help:    "place a limit sell order",
+		private: true,
+		run:     runSell,
+	})
+	register(&command{
+		name:    "cancel",
+		usage:   "cancel <orderNumber>",
+		help:    "cancel an open order",
+		private: true,
+		run:     runCancel,
+	})
+	register(&command{
+		name:    "cancel-all",
+		usage:   "cancel-all [pair]",
+		help:    "cancel every open order, of one market or all of them",
+		private: true,
+		run:     runCancelAll,
+	})
+	register(&command{
+		name:    "watchdog",
+		usage:   "watchdog [-timeout d] [-close-margin] <heartbeat file>",
+		help:    "cancel every open order when a heartbeat file stops being touched",
+		private: true,
+		run:     runWatchdog,
+	})
+	register(&command{
+		name:    "move",
+		usage:   "move [-ioc] [-postonly] [-clientid id] <orderNumber> <rate> [amount]",
+		help:    "atomically replace an open order at a new rate",
+		private: true,
+		run:     runMove,
+	})
+	register(&command{
+		name:    "withdraw",
+		usage:   "withdraw [-paymentid id] [-allowlist file] [-limit n] <currency> <address> <amount>",
+		help:    "withdraw funds to an allowed external address",
+		private: true,
+		run:     runWithdraw,
+	})
+	register(&command{
+		name:    "fees",
+		usage:   "fees",
+		help:    "show your maker/taker fees and 30-day volume",
+		private: true,
+		run:     runFees,
+	})
+}
+
+func parseFloats(args ...string) ([]float64, error) {
+	out := make([]float64, len(args))
+
+	for i, arg := range args {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = f
+	}
+
+	return out, nil
+}
+
+func runBalances(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("balances", flag.ContinueOnError)
+	all := fs.Bool("all", false, "include margin and lending accounts")
+	zero := fs.Bool("zero", false, "include empty balances")
+
+	if _, err := parseArgs(fs, args, "balances [-all] [-zero]", 0, 0); err != nil {
+		return nil, err
+	}
+
+	balances, err := api.ApiPrivateCompleteBalances(*all)
+	if err != nil {
+		return nil, err
+	}
+
+	if !*zero {
+		for currency, b := range balances {
+			if b.Available == 0.0 && b.OnOrders == 0.0 && b.BtcValue == 0.0 {
+				delete(balances, currency)
+			}
+		}
+	}
+
+	res := &result{
+		header: []string{"currency", "available", "onOrders", "btcValue"},
+		raw:    balances,
+	}
+
+	for _, currency := range sortedKeys(balances) {
+		b := balances[currency]
+		res.add(currency, formatFloat(b.Available), formatFloat(b.OnOrders), formatFloat(b.BtcValue))
+	}
+
+	return res, nil
+}
+
+func runPortfolio(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("portfolio", flag.ContinueOnError)
+	quote := fs.String("quote", "USDT", "currency to value holdings in")
+
+	if _, err := parseArgs(fs, args, "portfolio [-quote currency]", 0, 0); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := poloniexapi.NewPortfolio(api, *quote).Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"account", "currency", "amount", "price", "value"},
+		raw:    snapshot,
+	}
+
+	for _, h := range snapshot.Holdings {
+		res.add(h.Account, h.Currency, formatFloat(h.Amount), formatFloat(h.Price), formatFloat(h.Value))
+	}
+
+	for _, account := range sortedKeys(snapshot.Accounts) {
+		res.add(account, "total", "", "", formatFloat(snapshot.Accounts[account]))
+	}
+	res.add("all", "total", "", "", formatFloat(snapshot.Total))
+
+	return res, nil
+}
+
+var costMethods = map[string]poloniexapi.CostMethod{
+	"fifo":    poloniexapi.FIFO,
+	"lifo":    poloniexapi.LIFO,
+	"average": poloniexapi.AverageCost,
+}
+
+func runPnL(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	usage := "pnl [-method fifo|lifo|average] [-quote currency] [-period all|day|month|year] [-days n]"
+	fs := flag.NewFlagSet("pnl", flag.ContinueOnError)
+	methodName := fs.String("method", "fifo", "cost basis method")
+	quote := fs.String("quote", "BTC", "currency of costs and P&L")
+	periodName := fs.String("period", "month", "group realized P&L by period")
+	days := fs.Int("days", 365, "history to load, in days")
+
+	if _, err := parseArgs(fs, args, usage, 0, 0); err != nil {
+		return nil, err
+	}
+
+	periods := map[string]poloniexapi.Period{
+		"all":   poloniexapi.PeriodAll,
+		"day":   poloniexapi.PeriodDay,
+		"month": poloniexapi.PeriodMonth,
+		"year":  poloniexapi.PeriodYear,
+	}
+
+	method, ok := costMethods[*methodName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown cost method %q", *methodName)
+	}
+
+	period, ok := periods[*periodName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown period %q", *periodName)
+	}
+
+	end := time.Now()
+	engine, err := api.LoadPnL(method, *quote, end.AddDate(0, 0, -*days), end)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers, err := api.ApiPublicTicker()
+	if err != nil {
+		return nil, err
+	}
+
+	report := engine.Report(tickers, period)
+
+	res := &result{
+		header: []string{"pair", "period", "trades", "fees", "realized", "unknown cost", "unrealized"},
+		raw:    report,
+	}
+
+	for _, row := range report.Rows {
+		res.add(row.Pair, row.Period, strconv.Itoa(row.Trades), formatFloat(row.Fees), formatFloat(row.Realized), formatFloat(row.UnknownCost), "")
+	}
+
+	for _, p := range report.Positions {
+		if p.Amount-p.UnknownAmount > 0 {
+			res.add(p.Currency, "open", "", "", "", "", formatFloat(p.Unrealized))
+		}
+	}
+
+	return res, nil
+}
+
+// runTax writes CSV directly, whatever the -format.
+func runTax(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	usage := "tax [-layout generic|koinly|cointracking|gains] [-method fifo|lifo|average] [-quote currency] [-from YYYY-MM-DD] [-to YYYY-MM-DD]"
+	fs := flag.NewFlagSet("tax", flag.ContinueOnError)
+	layout := fs.String("layout", "generic", "CSV layout")
+	methodName := fs.String("method", "fifo", "lot matching for capital gains")
+	quote := fs.String("quote", "BTC", "currency of capital gains")
+	from := fs.String("from", time.Now().AddDate(-1, 0, 0).Format("2006-01-02"), "first day exported")
+	to := fs.String("to", time.Now().Format("2006-01-02"), "last day exported")
+
+	if _, err := parseArgs(fs, args, usage, 0, 0); err != nil {
+		return nil, err
+	}
+
+	method, ok := costMethods[*methodName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown cost method %q", *methodName)
+	}
+
+	start, err := time.Parse("2006-01-02", *from)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := time.Parse("2006-01-02", *to)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := tax.Load(api, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	switch *layout {
+	case "generic":
+		err = tax.WriteGeneric(os.Stdout, activity)
+	case "koinly":
+		err = tax.WriteKoinly(os.Stdout, activity)
+	case "cointracking":
+		err = tax.WriteCoinTracking(os.Stdout, activity)
+	case "gains":
+		err = tax.WriteCapitalGains(os.Stdout, activity, method, *quote, nil, tax.OneYear)
+	default:
+		err = fmt.Errorf("Unknown layout %q", *layout)
+	}
+
+	return nil, err
+}
+
+func runOrders(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	pos, err := parseArgs(flag.NewFlagSet("orders", flag.ContinueOnError), args, "orders [pair]", 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	pair := "all"
+	if len(pos) == 1 {
+		pair = pos[0]
+	}
+
+	orders, err := api.ApiPrivateOpenOrders(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range orders {
+		if len(v) == 0 {
+			delete(orders, k)
+		}
+	}
+
+	res := &result{
+		header: []string{"pair", "orderNumber", "type", "rate", "startingAmount", "amount", "total", "date", "margin"},
+		raw:    orders,
+	}
+
+	for _, pair := range sortedKeys(orders) {
+		for _, o := range orders[pair] {
+			res.add(pair,
+				o.OrderNumber,
+				o.Type,
+				formatFloat(o.Rate),
+				formatFloat(o.StartingAmount),
+				formatFloat(o.Amount),
+				formatFloat(o.Total),
+				o.Date,
+				formatInt(o.Margin),
+			)
+		}
+	}
+
+	return res, nil
+}
+
+func orderResult(order *poloniexapi.Order) *result {
+	res := &result{
+		header: []string{"orderNumber", "pair", "tradeID", "date", "type", "rate", "amount", "total"},
+		raw:    order,
+	}
+
+	if len(order.ResultingTrades) == 0 {
+		res.add(formatInt(order.OrderNumber), "", "", "", "", "", "", "")
+	}
+
+	for _, pair := range sortedKeys(order.ResultingTrades) {
+		for _, t := range order.ResultingTrades[pair] {
+			res.add(formatInt(order.OrderNumber),
+				pair,
+				formatInt(t.TradeID),
+				t.Date,
+				t.Type,
+				formatFloat(t.Rate),
+				formatFloat(t.Amount),
+				formatFloat(t.Total),
+			)
+		}
+	}
+
+	return res
+}
+
+func orderOptions(fok, ioc, postOnly bool, clientOrderID int64) (*poloniexapi.OrderOptions, error) {
+	opts := &poloniexapi.OrderOptions{PostOnly: postOnly, ClientOrderID: clientOrderID}
+
+	switch {
+	case fok && ioc:
+		return nil, fmt.Errorf("-fok and -ioc are mutually exclusive")
+	case fok:
+		opts.TimeInForce = poloniexapi.TimeInForceFOK
+	case ioc:
+		opts.TimeInForce = poloniexapi.TimeInForceIOC
+	}
+
+	return opts, opts.Validate()
+}
+
+func placeOrder(api *poloniexapi.PoloniexApi, side poloniexapi.OrderSide, args []string) (*result, error) {
+	usage := string(side) + " [-fok|-ioc] [-postonly] [-clientid id] <pair> <rate> <amount>"
+
+	fs := flag.NewFlagSet(string(side), flag.ContinueOnError)
+	fok := fs.Bool("fok", false, "fill-or-kill")
+	ioc := fs.Bool("ioc", false, "immediate-or-cancel")
+	postOnly := fs.Bool("postonly", false, "post-only (maker)")
+	clientOrderID := fs.Int64("clientid", 0, "client order id")
+
+	pos, err := parseArgs(fs, args, usage, 3, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseFloats(pos[1], pos[2])
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := orderOptions(*fok, *ioc, *postOnly, *clientOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := api.PlaceOrder(side, pos[0], values[0], values[1], opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderResult(order), nil
+}
+
+func runBuy(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	return placeOrder(api, poloniexapi.SideBuy, args)
+}
+
+func runSell(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	return placeOrder(api, poloniexapi.SideSell, args)
+}
+
+func runCancel(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	pos, err := parseArgs(flag.NewFlagSet("cancel", flag.ContinueOnError), args, "cancel <orderNumber>", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	orderNumber, err := strconv.ParseInt(pos[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	_, out, err := api.ApiPrivateCancel(orderNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"orderNumber", "success", "amount", "message"},
+		raw:    out,
+	}
+	res.add(pos[0], formatInt(out.Success), formatFloat(out.Amount), out.Message)
+
+	return res, nil
+}
+
+func runCancelAll(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	pos, err := parseArgs(flag.NewFlagSet("cancel-all", flag.ContinueOnError), args, "cancel-all [pair]", 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	pair := "all"
+	if len(pos) == 1 {
+		pair = pos[0]
+	}
+
+	report, err := api.CancelAll(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors do not marshal to JSON.
+	type cancelled struct {
+		Pair        string `json:"pair"`
+		OrderNumber string `json:"orderNumber"`
+		Status      string `json:"status"`
+		Attempts    int    `json:"attempts"`
+		Error       string `json:"error,omitempty"`
+	}
+
+	raw := make([]cancelled, 0, len(report))
+	res := &result{header: []string{"pair", "orderNumber", "status", "attempts", "error"}}
+
+	for _, r := range report {
+		c := cancelled{Pair: r.Pair, OrderNumber: r.Order.OrderNumber, Status: r.Status.String(), Attempts: r.Attempts}
+		if r.Err != nil {
+			c.Error = r.Err.Error()
+		}
+
+		raw = append(raw, c)
+		res.add(c.Pair, c.OrderNumber, c.Status, strconv.Itoa(c.Attempts), c.Error)
+	}
+	res.raw = raw
+
+	if failed := len(report.Failed()); failed != 0 {
+		return res, fmt.Errorf("%d orders could not be cancelled", failed)
+	}
+
+	return res, nil
+}
+
+func runWatchdog(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("watchdog", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 30*time.Second, "time without heartbeat before cancelling")
+	closeMargin := fs.Bool("close-margin", false, "close margin positions as well")
+
+	pos, err := parseArgs(fs, args, "watchdog [-timeout d] [-close-margin] <heartbeat file>", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	watchdog, err := poloniexapi.NewWatchdog(api, *timeout)
+	if err != nil {
+		return nil, err
+	}
+	watchdog.HeartbeatFile = pos[0]
+	watchdog.CloseMarginPositions = *closeMargin
+	watchdog.OnTrip = func(trip poloniexapi.WatchdogTrip) {
+		fmt.Fprintf(os.Stderr, "poloniex: no heartbeat since %s, %d orders cancelled\n",
+			trip.LastHeartbeat.Format(time.RFC3339), len(trip.Cancels)-len(trip.Cancels.Failed()))
+		for _, err := range trip.Errors {
+			fmt.Fprintf(os.Stderr, "poloniex: %s\n", err.Error())
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return nil, watchdog.Run(ctx)
+}
+
+func runMove(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	ioc := fs.Bool("ioc", false, "immediate-or-cancel")
+	postOnly := fs.Bool("postonly", false, "post-only (maker)")
+
+	clientOrderID := fs.Int64("clientid", 0, "client order id")
+
+	pos, err := parseArgs(fs, args, "move [-ioc] [-postonly] [-clientid id] <orderNumber> <rate> [amount]", 2, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	orderNumber, err := strconv.ParseInt(pos[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseFloats(pos[1:]...)
+	if err != nil {
+		return nil, err
+	}
+
+	// An amount of 0 keeps the amount of the original order.
+	values = append(values, 0.0)
+
+	opts, err := orderOptions(false, *ioc, *postOnly, *clientOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := api.ApiPrivateMoveOrder(orderNumber, values[0], values[1], opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderResult(order), nil
+}
+
+func runWithdraw(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("withdraw", flag.ContinueOnError)
+	paymentId := fs.String("paymentid", "", "payment id, for XMR withdrawals")
+	allowlistPath := fs.String("allowlist", "withdraw-allowlist.json", `addresses allowed by currency, as {"BTC": ["address", ...]}`)
+	limit := fs.Float64("limit", 0, "most of the currency withdrawn over 24 hours, on top of the exchange limit")
+
+	pos, err := parseArgs(fs, args, "withdraw [-paymentid id] [-allowlist file] [-limit n] <currency> <address> <amount>", 3, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := parseFloats(pos[2])
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(*allowlistPath)
+	if err != nil {
+		return nil, err
+	}
+
+	allowlist := make(map[string][]string)
+	if err = json.Unmarshal(data, &allowlist); err != nil {
+		return nil, fmt.Errorf("Could not read allowlist %s (%s)", *allowlistPath, err.Error())
+	}
+
+	guard := poloniexapi.NewWithdrawGuard(api)
+	for currency, addresses := range allowlist {
+		guard.Allow(currency, addresses...)
+	}
+	if *limit > 0 {
+		guard.SetDailyLimit(pos[0], *limit)
+	}
+
+	response, err := guard.Withdraw(poloniexapi.WithdrawRequest{
+		Currency:  pos[0],
+		Address:   pos[1],
+		Amount:    amount[0],
+		PaymentId: *paymentId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"response"},
+		raw:    response,
+	}
+	res.add(response.Response)
+
+	return res, nil
+}
+
+func runFees(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	if _, err := parseArgs(flag.NewFlagSet("fees", flag.ContinueOnError), args, "fees", 0, 0); err != nil {
+		return nil, err
+	}
+
+	fees, err := api.ApiPrivateFeeInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"makerFee", "takerFee", "thirtyDayVolume", "nextTier"},
+		raw:    fees,
+	}
+	res.add(formatFloat(fees.MakerFee), formatFloat(fees.TakerFee), formatFloat(fees.ThirtyDayVolume), formatFloat(fees.NextTier))
+
+	return res, nil
+}
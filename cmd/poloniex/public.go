@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/recorder"
+)
+
+func init() {
+	register(&command{
+		name:  "ticker",
+		usage: "ticker [pair ...]",
+		help:  "show the ticker for all or some markets",
+		run:   runTicker,
+	})
+	register(&command{
+		name:  "volume",
+		usage: "volume",
+		help:  "show the 24-hour volume for all markets",
+		run:   runVolume,
+	})
+	register(&command{
+		name:  "book",
+		usage: "book [-depth n] <pair>",
+		help:  "show the order book of a market",
+		run:   runBook,
+	})
+	register(&command{
+		name:  "trades",
+		usage: "trades [-mine] [-start ts] [-end ts] <pair|all>",
+		help:  "show public trade history of a market, or your own with -mine",
+		run:   runTrades,
+	})
+	register(&command{
+		name:  "chart",
+		usage: "chart [-period s] [-start ts] [-end ts] <pair>",
+		help:  "show candlestick data of a market",
+		run:   runChart,
+	})
+	register(&command{
+		name:  "currencies",
+		usage: "currencies",
+		help:  "list currencies and their withdrawal fees",
+		run:   runCurrencies,
+	})
+	register(&command{
+		name:  "loans",
+		usage: "loans <currency>",
+		help:  "show loan offers and demands for a currency",
+		run:   runLoans,
+	})
+	register(&command{
+		name:  "record",
+		usage: "record [-dir d] [-compression gzip|zstd|none] [-depth n] <pair ...>",
+		help:  "archive tickers, order books and trades until interrupted",
+		run:   runRecord,
+	})
+}
+
+// parseArgs parses the flags of a command and checks its positional
+// argument count is within [min, max] (max < 0 meaning unbounded).
+func parseArgs(fs *flag.FlagSet, args []string, usage string, min, max int) ([]string, error) {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: poloniex %s\n", usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if fs.NArg() < min || (max >= 0 && fs.NArg() > max) {
+		return nil, fmt.Errorf("Usage: poloniex %s", usage)
+	}
+
+	return fs.Args(), nil
+}
+
+// sortedKeys returns the keys of a map[string]T in order, so tables come out
+// stable from one run to the next.
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func runTicker(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	pairs, err := parseArgs(flag.NewFlagSet("ticker", flag.ContinueOnError), args, "ticker [pair ...]", 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers, err := api.ApiPublicTicker()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pairs) != 0 {
+		selected := make(map[string]poloniexapi.Ticker)
+		for _, pair := range pairs {
+			ticker, ok := tickers[pair]
+			if !ok {
+				return nil, fmt.Errorf("Unknown market %s", pair)
+			}
+			selected[pair] = ticker
+		}
+		tickers = selected
+	}
+
+	res := &result{
+		header: []string{"pair", "last", "lowestAsk", "highestBid", "percentChange", "baseVolume", "quoteVolume", "high24hr", "low24hr", "isFrozen"},
+		raw:    tickers,
+	}
+
+	for _, pair := range sortedKeys(tickers) {
+		t := tickers[pair]
+		res.add(pair,
+			formatFloat(t.Last),
+			formatFloat(t.LowestAsk),
+			formatFloat(t.HighestBid),
+			formatFloat(t.PercentChange),
+			formatFloat(t.BaseVolume),
+			formatFloat(t.QuoteVolume),
+			formatFloat(t.High24hr),
+			formatFloat(t.Low24hr),
+			formatInt(int64(t.IsFrozen)),
+		)
+	}
+
+	return res, nil
+}
+
+func runVolume(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	if _, err := parseArgs(flag.NewFlagSet("volume", flag.ContinueOnError), args, "volume", 0, 0); err != nil {
+		return nil, err
+	}
+
+	totals, volumes, err := api.ApiPublic24hVolume()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"pair", "currency", "volume"},
+		raw: map[string]interface{}{
+			"totals":  totals,
+			"markets": volumes,
+		},
+	}
+
+	for _, pair := range sortedKeys(volumes) {
+		for _, currency := range sortedKeys(volumes[pair]) {
+			res.add(pair, currency, formatFloat(volumes[pair][currency]))
+		}
+	}
+	for _, total := range sortedKeys(totals) {
+		res.add("total", total, formatFloat(totals[total]))
+	}
+
+	return res, nil
+}
+
+func runBook(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("book", flag.ContinueOnError)
+	depth := fs.Int("depth", 20, "number of entries per side")
+
+	pos, err := parseArgs(fs, args, "book [-depth n] <pair>", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := api.ApiPublicOrderBook(pos[0], *depth)
+	if err != nil {
+		return nil, err
+	}
+
+	book := books[pos[0]]
+	res := &result{
+		header: []string{"side", "rate", "amount"},
+		raw:    book,
+	}
+
+	for i := len(book.Asks) - 1; i >= 0; i-- {
+		res.add("ask", formatFloat(book.Asks[i][0]), formatFloat(book.Asks[i][1]))
+	}
+	for _, bid := range book.Bids {
+		res.add("bid", formatFloat(bid[0]), formatFloat(bid[1]))
+	}
+
+	return res, nil
+}
+
+func tradesResult(trades map[string][]poloniexapi.Trade) *result {
+	res := &result{
+		header: []string{"pair", "globalTradeID", "tradeID", "date", "type", "rate", "amount", "total", "fee", "orderNumber"},
+		raw:    trades,
+	}
+
+	for _, pair := range sortedKeys(trades) {
+		for _, t := range trades[pair] {
+			res.add(pair,
+				formatInt(t.GlobalTradeID),
+				formatInt(t.TradeID),
+				t.Date,
+				t.Type,
+				formatFloat(t.Rate),
+				formatFloat(t.Amount),
+				formatFloat(t.Total),
+				formatFloat(t.Fee),
+				formatInt(t.OrderNumber),
+			)
+		}
+	}
+
+	return res
+}
+
+func runTrades(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("trades", flag.ContinueOnError)
+	mine := fs.Bool("mine", false, "show your own trades (requires credentials)")
+	start := fs.Int("start", 0, "start of the range, as a UNIX timestamp")
+	end := fs.Int("end", 0, "end of the range, as a UNIX timestamp")
+
+	pos, err := parseArgs(fs, args, "trades [-mine] [-start ts] [-end ts] <pair|all>", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if *mine {
+		if api.Key == "" {
+			return nil, fmt.Errorf("trades -mine requires credentials")
+		}
+
+		trades, err := api.ApiPrivateTradeHistory(pos[0], *start, *end)
+		if err != nil {
+			return nil, err
+		}
+
+		return tradesResult(trades), nil
+	}
+
+	trades, err := api.ApiPublicTradeHistory(pos[0], *start, *end)
+	if err != nil {
+		return nil, err
+	}
+
+	res := tradesResult(map[string][]poloniexapi.Trade{pos[0]: trades})
+	res.raw = trades
+
+	return res, nil
+}
+
+func runChart(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	now := time.Now().Unix()
+
+	fs := flag.NewFlagSet("chart", flag.ContinueOnError)
+	period := fs.Int64("period", 300, "candle period in seconds (300, 900, 1800, 7200, 14400 or 86400)")
+	start := fs.Int64("start", now-24*60*60, "start of the range, as a UNIX timestamp")
+	end := fs.Int64("end", now, "end of the range, as a UNIX timestamp")
+
+	pos, err := parseArgs(fs, args, "chart [-period s] [-start ts] [-end ts] <pair>", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := api.ApiChartData(pos[0], *start, *end, *period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"date", "open", "high", "low", "close", "volume", "quoteVolume", "weightedAverage"},
+		raw:    entries,
+	}
+
+	for _, e := range entries {
+		res.add(
+			time.Unix(e.Date, 0).UTC().Format("2006-01-02 15:04:05"),
+			formatFloat(e.Open),
+			formatFloat(e.High),
+			formatFloat(e.Low),
+			formatFloat(e.Close),
+			formatFloat(e.Volume),
+			formatFloat(e.QuoteVolume),
+			formatFloat(e.WeightedAverage),
+		)
+	}
+
+	return res, nil
+}
+
+func runCurrencies(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	if _, err := parseArgs(flag.NewFlagSet("currencies", flag.ContinueOnError), args, "currencies", 0, 0); err != nil {
+		return nil, err
+	}
+
+	currencies, err := api.ApiCurrencies()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"currency", "id", "name", "txFee", "minConf", "disabled", "delisted", "frozen"},
+		raw:    currencies,
+	}
+
+	for _, code := range sortedKeys(currencies) {
+		c := currencies[code]
+		res.add(code,
+			formatInt(c.Id),
+			c.Name,
+			formatFloat(c.TxFee),
+			formatInt(c.MinConf),
+			formatInt(c.Disabled),
+			formatInt(c.Delisted),
+			formatInt(c.Frozen),
+		)
+	}
+
+	return res, nil
+}
+
+func runLoans(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	pos, err := parseArgs(flag.NewFlagSet("loans", flag.ContinueOnError), args, "loans <currency>", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	loans, err := api.ApiLoanOrders(pos[0])
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{
+		header: []string{"side", "rate", "amount", "rangeMin", "rangeMax"},
+		raw:    loans,
+	}
+
+	for _, l := range loans.Offers {
+		res.add("offer", formatFloat(l.Rate), formatFloat(l.Amount), formatInt(l.RangeMin), formatInt(l.RangeMax))
+	}
+	for _, l := range loans.Demands {
+		res.add("demand", formatFloat(l.Rate), formatFloat(l.Amount), formatInt(l.RangeMin), formatInt(l.RangeMax))
+	}
+
+	return res, nil
+}
+
+func runRecord(api *poloniexapi.PoloniexApi, args []string) (*result, error) {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	dir := fs.String("dir", "data", "directory of the archives")
+	compression := fs.String("compression", "gzip", "compression: gzip, zstd or none")
+	depth := fs.Int("depth", 50, "order book depth")
+
+	pairs, err := parseArgs(fs, args, "record [-dir d] [-compression gzip|zstd|none] [-depth n] <pair ...>", 1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := recorder.New(api, *dir, pairs...)
+	rec.Depth = *depth
+	if rec.Compression, err = recorder.ParseCompression(*compression); err != nil {
+		return nil, err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return nil, rec.Run(ctx, func(err error) {
+		fmt.Fprintf(os.Stderr, "poloniex: %s\n", err.Error())
+	})
+}
@@ -0,0 +1,36 @@
+package poloniexapi
+
+import (
+	"fmt"
+)
+
+/*
+CredentialsProvider supplies the API key and secret used to sign private
+requests. The returned secret is wiped by the caller once the request is
+signed, so providers must return a fresh slice on each call.
+
+See the credentials sub-package for environment, file and keystore providers.
+*/
+type CredentialsProvider interface {
+	Credentials() (key string, secret []byte, err error)
+}
+
+func (api *PoloniexApi) getCredentials() (string, []byte, error) {
+	if api.credentials == nil {
+		return api.Key, []byte(api.secret), nil
+	}
+
+	key, secret, err := api.credentials.Credentials()
+	if err != nil {
+		return "", nil, fmt.Errorf("Could not get credentials! (%s)", err.Error())
+	}
+
+	return key, secret, nil
+}
+
+// wipe overwrites a secret once it is not needed anymore.
+func wipe(secret []byte) {
+	for i := range secret {
+		secret[i] = 0
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Package credentials provides sources for the Poloniex API key and secret, to be
+used with poloniexapi.NewFromCredentials:
+
+	provider := credentials.Chain{
+		credentials.Env{},
+		&credentials.Keystore{Path: "keystore.json", Passphrase: credentials.PromptPassphrase},
+	}
+
+	api, err := poloniexapi.NewFromCredentials(provider)
+
+Every provider returns a fresh secret slice, which the client wipes as soon as
+the request is signed. Env and File read their source again on each call; a
+Keystore decrypts its file once and keeps the secret until Close.
+*/
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned by a provider whose source holds no credentials.
+var ErrNotFound = errors.New("credentials not found")
+
+// Provider is satisfied by every provider of this package, and matches
+// poloniexapi.CredentialsProvider.
+type Provider interface {
+	Credentials() (key string, secret []byte, err error)
+}
+
+// Chain tries each provider in turn and returns the first credentials found.
+// Providers failing with ErrNotFound are skipped; any other error stops the
+// search.
+type Chain []Provider
+
+func (c Chain) Credentials() (string, []byte, error) {
+	for _, p := range c {
+		key, secret, err := p.Credentials()
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		return key, secret, err
+	}
+
+	return "", nil, ErrNotFound
+}
+
+// Wipe overwrites a secret once it is not needed anymore.
+func Wipe(secret []byte) {
+	for i := range secret {
+		secret[i] = 0
+	}
+}
+
+func checkCredentials(key string, secret []byte, source string) error {
+	if strings.TrimSpace(key) == "" || len(secret) == 0 {
+		Wipe(secret)
+		return fmt.Errorf("%s: %w", source, ErrNotFound)
+	}
+
+	return nil
+}
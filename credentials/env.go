@@ -0,0 +1,38 @@
+package credentials
+
+import (
+	"os"
+)
+
+const (
+	DefaultKeyVariable    = "POLONIEX_API_KEY"
+	DefaultSecretVariable = "POLONIEX_API_SECRET"
+)
+
+// Env reads the credentials from environment variables, POLONIEX_API_KEY and
+// POLONIEX_API_SECRET unless other names are given.
+type Env struct {
+	KeyVariable    string
+	SecretVariable string
+}
+
+func (e Env) Credentials() (string, []byte, error) {
+	keyVariable := e.KeyVariable
+	if keyVariable == "" {
+		keyVariable = DefaultKeyVariable
+	}
+
+	secretVariable := e.SecretVariable
+	if secretVariable == "" {
+		secretVariable = DefaultSecretVariable
+	}
+
+	key := os.Getenv(keyVariable)
+	secret := []byte(os.Getenv(secretVariable))
+
+	if err := checkCredentials(key, secret, "environment"); err != nil {
+		return "", nil, err
+	}
+
+	return key, secret, nil
+}
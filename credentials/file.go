@@ -0,0 +1,91 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+)
+
+/*
+File reads plaintext credentials from a JSON file in the format of
+config.json.sample:
+
+	{"key": "...", "secret": "..."}
+
+The file must not be readable by group or others (mode 0600 or stricter),
+unless AllowInsecure is set. The check is skipped on Windows, where Unix
+permission bits do not apply.
+*/
+type File struct {
+	Path          string
+	AllowInsecure bool
+}
+
+func (f File) Credentials() (string, []byte, error) {
+	info, err := os.Stat(f.Path)
+	if os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("%s: %w", f.Path, ErrNotFound)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = checkPermissions(f.Path, info, f.AllowInsecure); err != nil {
+		return "", nil, err
+	}
+
+	content, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer Wipe(content)
+
+	return parsePlaintext(content, f.Path)
+}
+
+func checkPermissions(path string, info os.FileInfo, allowInsecure bool) error {
+	if allowInsecure || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("%s: permissions %04o are too open, it must not be accessible by group or others (chmod 600)", path, perm)
+	}
+
+	return nil
+}
+
+// parsePlaintext decodes {"key": ..., "secret": ...} without going through a
+// Go string for the secret, so that every copy of it can be wiped.
+func parsePlaintext(content []byte, source string) (string, []byte, error) {
+	var config struct {
+		Key    string
+		Secret json.RawMessage
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		return "", nil, fmt.Errorf("%s: could not parse credentials (%s)", source, err.Error())
+	}
+	defer Wipe(config.Secret)
+
+	raw := bytes.TrimSpace(config.Secret)
+	if len(raw) == 0 {
+		return "", nil, checkCredentials(config.Key, nil, source)
+	}
+
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' || bytes.IndexByte(raw, '\\') >= 0 {
+		return "", nil, fmt.Errorf("%s: secret must be a plain JSON string", source)
+	}
+
+	secret := make([]byte, len(raw)-2)
+	copy(secret, raw[1:len(raw)-1])
+
+	if err := checkCredentials(config.Key, secret, source); err != nil {
+		return "", nil, err
+	}
+
+	return config.Key, secret, nil
+}
@@ -0,0 +1,286 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+	keystoreCipher  = "aes-256-gcm"
+
+	// Authenticated along with the ciphertext, binds it to this format.
+	keystoreAAD = "poloniex-api keystore v1"
+)
+
+// ErrBadPassphrase is returned when a keystore cannot be decrypted.
+var ErrBadPassphrase = errors.New("wrong passphrase or corrupted keystore")
+
+// ScryptParams are the key derivation parameters stored in a keystore.
+type ScryptParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// DefaultScryptParams costs about 100ms and 32MB per derivation.
+var DefaultScryptParams = ScryptParams{N: 1 << 15, R: 8, P: 1}
+
+type keystoreFile struct {
+	Version    int          `json:"version"`
+	KDF        string       `json:"kdf"`
+	KDFParams  ScryptParams `json:"kdfparams"`
+	Cipher     string       `json:"cipher"`
+	Nonce      []byte       `json:"nonce"`
+	Ciphertext []byte       `json:"ciphertext"`
+}
+
+/*
+Keystore reads credentials from a passphrase-encrypted file created by
+WriteKeystore. The key is derived from the passphrase with scrypt and the
+credentials are sealed with AES-256-GCM.
+
+The passphrase is asked and the file decrypted on the first call only: the
+derivation is too costly to run on every signed request. The secret then stays
+in memory, each call returning a copy of it, until Close wipes it; the next
+call asks the passphrase again.
+*/
+type Keystore struct {
+	Path       string
+	Passphrase func() ([]byte, error)
+
+	mu     sync.Mutex
+	key    string
+	secret []byte
+}
+
+func (k *Keystore) Credentials() (string, []byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.secret == nil {
+		key, secret, err := k.unlock()
+		if err != nil {
+			return "", nil, err
+		}
+		k.key, k.secret = key, secret
+	}
+
+	secret := make([]byte, len(k.secret))
+	copy(secret, k.secret)
+
+	return k.key, secret, nil
+}
+
+// Close wipes the decrypted secret.
+func (k *Keystore) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	Wipe(k.secret)
+	k.key, k.secret = "", nil
+
+	return nil
+}
+
+func (k *Keystore) unlock() (string, []byte, error) {
+	content, err := ioutil.ReadFile(k.Path)
+	if os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("%s: %w", k.Path, ErrNotFound)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	var ks keystoreFile
+	if err = json.Unmarshal(content, &ks); err != nil {
+		return "", nil, fmt.Errorf("%s: could not parse keystore (%s)", k.Path, err.Error())
+	}
+
+	if ks.Version != keystoreVersion || ks.KDF != keystoreKDF || ks.Cipher != keystoreCipher {
+		return "", nil, fmt.Errorf("%s: unsupported keystore (version %d, %s, %s)", k.Path, ks.Version, ks.KDF, ks.Cipher)
+	}
+
+	passphrase, err := k.Passphrase()
+	if err != nil {
+		return "", nil, err
+	}
+
+	aead, err := newKeystoreAEAD(passphrase, ks.KDFParams)
+	Wipe(passphrase)
+	if err != nil {
+		return "", nil, err
+	}
+
+	plaintext, err := aead.Open(nil, ks.Nonce, ks.Ciphertext, []byte(keystoreAAD))
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", k.Path, ErrBadPassphrase)
+	}
+	defer Wipe(plaintext)
+
+	return parsePlaintext(plaintext, k.Path)
+}
+
+func newKeystoreAEAD(passphrase []byte, params ScryptParams) (cipher.AEAD, error) {
+	derived, err := scrypt.Key(passphrase, params.Salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer Wipe(derived)
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+/*
+WriteKeystore encrypts key and secret with passphrase and writes them to path
+with mode 0600. It fails if path exists. A nil params uses DefaultScryptParams;
+the salt is always generated.
+*/
+func WriteKeystore(path, key string, secret, passphrase []byte, params *ScryptParams) error {
+	if params == nil {
+		params = &DefaultScryptParams
+	}
+
+	ks := keystoreFile{
+		Version:   keystoreVersion,
+		KDF:       keystoreKDF,
+		KDFParams: *params,
+		Cipher:    keystoreCipher,
+	}
+
+	ks.KDFParams.Salt = make([]byte, 32)
+	if _, err := rand.Read(ks.KDFParams.Salt); err != nil {
+		return err
+	}
+
+	aead, err := newKeystoreAEAD(passphrase, ks.KDFParams)
+	if err != nil {
+		return err
+	}
+
+	ks.Nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(ks.Nonce); err != nil {
+		return err
+	}
+
+	plaintext, err := marshalPlaintext(key, secret)
+	if err != nil {
+		return err
+	}
+	defer Wipe(plaintext)
+
+	ks.Ciphertext = aead.Seal(nil, ks.Nonce, plaintext, []byte(keystoreAAD))
+
+	content, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// marshalPlaintext encodes key and secret as read by parsePlaintext, in a
+// single buffer so that no copy of the secret is left unwiped.
+func marshalPlaintext(key string, secret []byte) ([]byte, error) {
+	for _, c := range secret {
+		if c == '"' || c == '\\' || c < 0x20 {
+			return nil, errors.New("secret must be a plain JSON string")
+		}
+	}
+
+	quotedKey, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, 0, len(quotedKey)+len(secret)+len(`{"key":,"secret":""}`))
+	plaintext = append(plaintext, `{"key":`...)
+	plaintext = append(plaintext, quotedKey...)
+	plaintext = append(plaintext, `,"secret":"`...)
+	plaintext = append(plaintext, secret...)
+	plaintext = append(plaintext, `"}`...)
+
+	return plaintext, nil
+}
+
+// EnvPassphrase returns a passphrase function reading the given environment
+// variable.
+func EnvPassphrase(variable string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		value, ok := os.LookupEnv(variable)
+		if !ok {
+			return nil, fmt.Errorf("%s is not set", variable)
+		}
+
+		return []byte(value), nil
+	}
+}
+
+// PromptPassphrase asks the passphrase on the terminal, without echo.
+func PromptPassphrase() ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, errors.New("cannot prompt for the keystore passphrase: stdin is not a terminal")
+	}
+
+	fmt.Fprint(os.Stderr, "Keystore passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+
+	return passphrase, err
+}
+
+/*
+CachePassphrase calls fn once and returns a copy of its result on every later
+call. It trades the passphrase staying in memory for not prompting the user on
+every request, and for asking it again once a Keystore is closed.
+*/
+func CachePassphrase(fn func() ([]byte, error)) func() ([]byte, error) {
+	var mu sync.Mutex
+	var cached []byte
+
+	return func() ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cached == nil {
+			passphrase, err := fn()
+			if err != nil {
+				return nil, err
+			}
+			cached = passphrase
+		}
+
+		out := make([]byte, len(cached))
+		copy(out, cached)
+
+		return out, nil
+	}
+}
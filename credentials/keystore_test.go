@@ -0,0 +1,145 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Cheap parameters, the defaults would make the test slow.
+var testScryptParams = ScryptParams{N: 1 << 10, R: 8, P: 1}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	err := WriteKeystore(path, "my-key", []byte("my-secret"), []byte("passphrase"), &testScryptParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("keystore mode is %04o, expected 0600", info.Mode().Perm())
+	}
+
+	ks := &Keystore{Path: path, Passphrase: func() ([]byte, error) { return []byte("passphrase"), nil }}
+
+	key, secret, err := ks.Credentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "my-key" || string(secret) != "my-secret" {
+		t.Errorf("got %q/%q, expected my-key/my-secret", key, secret)
+	}
+
+	// Asked again once closed.
+	ks.Close()
+	ks.Passphrase = func() ([]byte, error) { return []byte("wrong"), nil }
+	if _, _, err = ks.Credentials(); !errors.Is(err, ErrBadPassphrase) {
+		t.Errorf("expected ErrBadPassphrase, got %v", err)
+	}
+
+	if err = WriteKeystore(path, "other-key", []byte("other-secret"), []byte("passphrase"), &testScryptParams); !errors.Is(err, os.ErrExist) {
+		t.Errorf("expected os.ErrExist writing over a keystore, got %v", err)
+	}
+}
+
+func TestKeystoreCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	if err := WriteKeystore(path, "my-key", []byte("my-secret"), []byte("passphrase"), &testScryptParams); err != nil {
+		t.Fatal(err)
+	}
+
+	asked := 0
+	ks := &Keystore{Path: path, Passphrase: func() ([]byte, error) {
+		asked++
+		return []byte("passphrase"), nil
+	}}
+
+	for i := 0; i < 3; i++ {
+		key, secret, err := ks.Credentials()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != "my-key" || string(secret) != "my-secret" {
+			t.Errorf("got %q/%q, expected my-key/my-secret", key, secret)
+		}
+
+		// As the client does once the request is signed.
+		Wipe(secret)
+	}
+
+	if asked != 1 {
+		t.Errorf("passphrase asked %d times, expected once", asked)
+	}
+
+	cached := ks.secret
+	if err := ks.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cached {
+		if c != 0 {
+			t.Fatalf("secret not wiped on Close: %q", cached)
+		}
+	}
+
+	if _, _, err := ks.Credentials(); err != nil || asked != 2 {
+		t.Errorf("passphrase asked %d times after Close, expected twice (%v)", asked, err)
+	}
+}
+
+func TestMarshalPlaintext(t *testing.T) {
+	plaintext, err := marshalPlaintext(`my"key`, []byte("my-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, secret, err := parsePlaintext(plaintext, "test")
+	if err != nil || key != `my"key` || string(secret) != "my-secret" {
+		t.Errorf("got %q/%q (%v), expected my\"key/my-secret", key, secret, err)
+	}
+
+	if _, err = marshalPlaintext("my-key", []byte(`my"secret`)); err == nil {
+		t.Error("expected an error for a secret needing escapes")
+	}
+}
+
+func TestFilePermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	if err := os.WriteFile(path, []byte(`{"key": "k", "secret": "s"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := (File{Path: path}).Credentials(); err == nil {
+		t.Error("expected an error for a world-readable file")
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, secret, err := (File{Path: path}).Credentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "k" || string(secret) != "s" {
+		t.Errorf("got %q/%q, expected k/s", key, secret)
+	}
+}
+
+func TestChainSkipsMissing(t *testing.T) {
+	chain := Chain{
+		Env{KeyVariable: "POLONIEX_TEST_UNSET_KEY", SecretVariable: "POLONIEX_TEST_UNSET_SECRET"},
+		File{Path: filepath.Join(t.TempDir(), "missing.json")},
+	}
+
+	if _, _, err := chain.Credentials(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
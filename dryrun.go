@@ -0,0 +1,122 @@
+package poloniexapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+/*
+In dry-run mode (PoloniexApi.DryRun), the commands below are checked and signed
+like any other private call, then logged instead of being sent. A synthetic
+response in the format of the exchange is returned, so that callers get a
+well-formed Order, CancelOrder, etc. Every other command goes through.
+*/
+var stateChangingCommands = map[string][]string{
+	CMD_PRIVATE_BUY:               {"currencyPair", "rate", "amount"},
+	CMD_PRIVATE_SELL:              {"currencyPair", "rate", "amount"},
+	CMD_PRIVATE_CANCEL_ORDER:      {"orderNumber"},
+	CMD_PRIVATE_MOVE_ORDER:        {"orderNumber", "rate"},
+	CMD_PRIVATE_WITHDRAW:          {"currency", "address", "amount"},
+	CMD_PRIVATE_TRANSFER_BALANCES: {"currency", "amount", "fromAccount", "toAccount"},
+	CMD_PRIVATE_NEW_ADDRESS:       {"currency"},
+
+	CMD_PRIVATE_CLOSE_MARGIN_POSITION: {"currencyPair"},
+}
+
+// Synthetic order numbers, increasing from the start time so that they do not
+// look like small test values.
+var dryRunOrderNumber = time.Now().UnixNano() / 1000
+
+func isStateChanging(command string) bool {
+	_, ok := stateChangingCommands[command]
+	return ok
+}
+
+func validateDryRun(params url.Values) error {
+	command := params.Get("command")
+
+	for _, name := range stateChangingCommands[command] {
+		if params.Get(name) == "" {
+			return fmt.Errorf("Dry-run %s: missing %s", command, name)
+		}
+	}
+
+	for _, name := range []string{"rate", "amount", "orderNumber"} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f <= 0 {
+			return fmt.Errorf("Dry-run %s: invalid %s %q", command, name, value)
+		}
+	}
+
+	return nil
+}
+
+func dryRunQuery(params url.Values) ([]byte, error) {
+	if err := validateDryRun(params); err != nil {
+		return nil, err
+	}
+
+	command := params.Get("command")
+	log.Printf("dry-run: %s %s", command, params.Encode())
+
+	var response interface{}
+
+	switch command {
+	case CMD_PRIVATE_BUY, CMD_PRIVATE_SELL:
+		response = map[string]interface{}{
+			"orderNumber":     strconv.FormatInt(atomic.AddInt64(&dryRunOrderNumber, 1), 10),
+			"resultingTrades": map[string][]Trade{},
+		}
+
+	case CMD_PRIVATE_MOVE_ORDER:
+		response = map[string]interface{}{
+			"success":         1,
+			"orderNumber":     strconv.FormatInt(atomic.AddInt64(&dryRunOrderNumber, 1), 10),
+			"resultingTrades": map[string][]Trade{},
+		}
+
+	case CMD_PRIVATE_CANCEL_ORDER:
+		response = map[string]interface{}{
+			"success": 1,
+			"amount":  "0.00000000",
+			"message": fmt.Sprintf("Dry-run: order #%s not canceled.", params.Get("orderNumber")),
+		}
+
+	case CMD_PRIVATE_WITHDRAW:
+		response = map[string]interface{}{
+			"response": fmt.Sprintf("Dry-run: did not withdraw %s %s.", params.Get("amount"), params.Get("currency")),
+		}
+
+	case CMD_PRIVATE_TRANSFER_BALANCES:
+		response = map[string]interface{}{
+			"success": 1,
+			"message": fmt.Sprintf("Dry-run: did not transfer %s %s from %s to %s account.",
+				params.Get("amount"), params.Get("currency"), params.Get("fromAccount"), params.Get("toAccount")),
+		}
+
+	case CMD_PRIVATE_NEW_ADDRESS:
+		response = map[string]interface{}{
+			"success":  1,
+			"response": "DRY-RUN-" + params.Get("currency") + "-ADDRESS",
+		}
+
+	case CMD_PRIVATE_CLOSE_MARGIN_POSITION:
+		response = map[string]interface{}{
+			"success":         1,
+			"message":         "Dry-run: margin position not closed.",
+			"resultingTrades": map[string][]Trade{},
+		}
+	}
+
+	return json.Marshal(response)
+}
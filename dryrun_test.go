@@ -0,0 +1,83 @@
+package poloniexapi
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDryRunQuery(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	cases := []struct {
+		params   string
+		err      string
+		contains string
+	}{
+		{"command=buy&currencyPair=BTC_XMR&rate=0.0125&amount=1", "", `"orderNumber":"`},
+		{"command=sell&currencyPair=BTC_XMR&rate=0.0125&amount=1", "", `"resultingTrades":{}`},
+		{"command=buy&currencyPair=BTC_XMR&rate=0.0125", "missing amount", ""},
+		{"command=sell&currencyPair=BTC_XMR&rate=-1&amount=1", "invalid rate", ""},
+		{"command=moveOrder&orderNumber=12&rate=0.013", "", `"success":1`},
+		{"command=moveOrder&orderNumber=abc&rate=0.013", "invalid orderNumber", ""},
+		{"command=cancelOrder&orderNumber=12", "", "order #12 not canceled"},
+		{"command=cancelOrder", "missing orderNumber", ""},
+		{"command=withdraw&currency=BTC&address=1address&amount=0.5", "", "did not withdraw 0.5 BTC"},
+		{"command=withdraw&currency=BTC&amount=0.5", "missing address", ""},
+	}
+
+	for _, c := range cases {
+		params, _ := url.ParseQuery(c.params)
+
+		body, err := dryRunQuery(params)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%s: got error %v, want %s", c.params, err, c.err)
+			}
+			continue
+		}
+
+		if err != nil || !strings.Contains(string(body), c.contains) || !json.Valid(body) {
+			t.Errorf("%s: got %s %v, want %s", c.params, body, err, c.contains)
+		}
+	}
+}
+
+func TestDryRunOffline(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	api := testApi(func(command string, params url.Values) string {
+		t.Errorf("%s reached the network", command)
+		return `{"error":"sent"}`
+	})
+	api.DryRun = true
+
+	buy, err := api.ApiPrivateBuy("BTC_XMR", 0.0125, 1, nil)
+	if err != nil || buy.OrderNumber == 0 {
+		t.Errorf("buy: got %+v %v", buy, err)
+	}
+
+	sell, err := api.ApiPrivateSell("BTC_XMR", 0.0125, 1, nil)
+	if err != nil || sell.OrderNumber == buy.OrderNumber {
+		t.Errorf("sell: got %+v %v", sell, err)
+	}
+
+	move, err := api.ApiPrivateMoveOrder(buy.OrderNumber, 0.013, 0, nil)
+	if err != nil || move.Success != 1 {
+		t.Errorf("move: got %+v %v", move, err)
+	}
+
+	if ok, _, err := api.ApiPrivateCancel(buy.OrderNumber); err != nil || !ok {
+		t.Errorf("cancel: got %v %v", ok, err)
+	}
+
+	if _, err = api.ApiPrivateWithdraw("BTC", "1address", 0.5, ""); err != nil {
+		t.Errorf("withdraw: %v", err)
+	}
+}
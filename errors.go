@@ -0,0 +1,82 @@
+package poloniexapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ApiError is an error reported by the exchange, e.g. {"error":"Not enough
+// BTC."}: the request was received and refused.
+type ApiError struct {
+	Command string
+	Message string
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Command, e.Message)
+}
+
+// RequestError is returned when no usable answer was received from the
+// exchange (network failure, timeout, unparsable response): the request may
+// or may not have been processed.
+type RequestError struct {
+	Command string
+	Err     error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// IsAmbiguous tells if a failed request may nevertheless have been executed
+// by the exchange, and must be checked before being sent again.
+func IsAmbiguous(err error) bool {
+	var requestError *RequestError
+	return errors.As(err, &requestError)
+}
+
+// IsNonceError tells if the exchange refused a request because its nonce was
+// not greater than the last one, e.g. when two clients share a key.
+func IsNonceError(err error) bool {
+	var apiError *ApiError
+	return errors.As(err, &apiError) && strings.Contains(strings.ToLower(apiError.Message), "nonce")
+}
+
+// IsRateLimitError tells if the exchange refused a request because too many
+// were sent, e.g. "Please do not make more than 6 API calls per second.".
+func IsRateLimitError(err error) bool {
+	var apiError *ApiError
+	if !errors.As(err, &apiError) {
+		return false
+	}
+
+	message := strings.ToLower(apiError.Message)
+
+	return strings.Contains(message, "calls per second") ||
+		strings.Contains(message, "too many requests") ||
+		strings.Contains(message, "rate limit")
+}
+
+func checkApiError(command string, body []byte) error {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return nil
+	}
+
+	var out struct {
+		Error string `json:"error"`
+	}
+
+	if json.Unmarshal(body, &out) != nil || out.Error == "" {
+		return nil
+	}
+
+	return &ApiError{Command: command, Message: out.Error}
+}
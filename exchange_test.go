@@ -0,0 +1,39 @@
+package poloniexapi
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// exchange answers the requests of a test client by command.
+type exchange func(command string, params url.Values) string
+
+func (e exchange) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := r.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+	if r.Method == "POST" {
+		r.ParseForm()
+		params = r.PostForm
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(e(params.Get("command"), params))),
+	}, nil
+}
+
+// testApi returns a client without limiter nor validator talking to e.
+func testApi(e exchange) *PoloniexApi {
+	api := New("key", "secret")
+	api.Limiter = nil
+	api.Validator = nil
+	api.Client = &http.Client{Transport: e}
+
+	return api
+}
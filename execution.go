@@ -0,0 +1,483 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrInvalidExecution = errors.New("invalid execution parameters")
+
+/*
+ExecutionParams are the parameters common to the execution algorithms.
+
+The parent order of Amount is split into child limit orders, one at a time. A
+child rests at the best price of its side of the book (the highest bid for a
+buy), and is moved with ApiPrivateMoveOrder whenever the book moves away from
+it. LimitPrice, when set, is the worst rate accepted: children are never
+priced above it for a buy, or below it for a sell.
+
+Fills are polled every Interval, 10 seconds by default. The requests are made
+with the ctx of the execution, so that cancelling it stops the execution right
+away; the last child order is then cancelled regardless.
+*/
+type ExecutionParams struct {
+	Side       OrderSide
+	Pair       string
+	Amount     float64
+	LimitPrice float64
+	Interval   time.Duration
+}
+
+func (p *ExecutionParams) validate() error {
+	if p.Side != SideBuy && p.Side != SideSell {
+		return fmt.Errorf("%w: unknown side %q", ErrInvalidExecution, p.Side)
+	}
+
+	if p.Pair == "" || p.Amount <= 0 || p.LimitPrice < 0 || p.Interval < 0 {
+		return fmt.Errorf("%w: a pair and a positive amount are required", ErrInvalidExecution)
+	}
+
+	if p.Interval == 0 {
+		p.Interval = 10 * time.Second
+	}
+
+	return nil
+}
+
+/*
+ExecutionProgress reports the state of an execution. ArrivalPrice is the middle
+of the book when the execution started, and Slippage is the difference between
+AveragePrice and ArrivalPrice as a fraction of ArrivalPrice, positive when the
+execution did worse than the arrival price (paid more for a buy, got less for
+a sell).
+*/
+type ExecutionProgress struct {
+	Time         time.Time
+	Side         OrderSide
+	Pair         string
+	Amount       float64
+	Filled       float64
+	AveragePrice float64
+	ArrivalPrice float64
+	Slippage     float64
+	Fees         float64
+	Orders       int
+	Done         bool
+}
+
+/*
+TWAP executes the order evenly over duration: it is split into slices of equal
+amount, and at the start of each slice the child order is resized so that the
+filled amount catches up with the schedule. Once duration is over, the
+remaining amount is priced to cross the book (within LimitPrice) for a few
+more intervals, then cancelled.
+
+progress, which may be nil, is called after each poll. The returned progress
+is the final state, also when ctx is cancelled (the child order is then
+cancelled as well).
+*/
+func (api *PoloniexApi) TWAP(ctx context.Context, p ExecutionParams, duration time.Duration, slices int, progress func(ExecutionProgress)) (ExecutionProgress, error) {
+	if slices < 1 || duration <= 0 {
+		return ExecutionProgress{}, fmt.Errorf("%w: TWAP needs a duration and at least one slice", ErrInvalidExecution)
+	}
+
+	weights := make([]float64, slices)
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	return api.executeSchedule(ctx, p, duration, weights, progress)
+}
+
+/*
+VWAP executes the order over duration following the usual volume of the
+market: the duration is split into five-minute slices, and each slice gets a
+share of the amount proportional to the volume traded at the same time of day
+over the last lookbackDays days, from ApiChartData. It then behaves as TWAP.
+*/
+func (api *PoloniexApi) VWAP(ctx context.Context, p ExecutionParams, duration time.Duration, lookbackDays int, progress func(ExecutionProgress)) (ExecutionProgress, error) {
+	if duration <= 0 || lookbackDays < 1 {
+		return ExecutionProgress{}, fmt.Errorf("%w: VWAP needs a duration and at least one day of history", ErrInvalidExecution)
+	}
+
+	const period = 300
+
+	now := time.Now()
+	chart, err := api.ApiChartDataContext(ctx, p.Pair, now.AddDate(0, 0, -lookbackDays).Unix(), now.Unix(), period)
+	if err != nil {
+		return ExecutionProgress{}, err
+	}
+
+	slices := int(math.Ceil(duration.Seconds() / period))
+
+	return api.executeSchedule(ctx, p, duration, volumeProfile(chart, now, duration, slices), progress)
+}
+
+/*
+volumeProfile returns the volume traded during each slice of the window
+starting at start, at the same time of day, summed over the history in chart.
+The weights are uniform when there is no volume at all.
+*/
+func volumeProfile(chart []ChartEntry, start time.Time, duration time.Duration, slices int) []float64 {
+	const day = 24 * 60 * 60
+
+	weights := make([]float64, slices)
+	sliceLength := duration.Seconds() / float64(slices)
+	offset := float64(start.Unix() % day)
+
+	total := 0.0
+	for _, c := range chart {
+		// Position of the candle in the window, in seconds, for any day.
+		position := math.Mod(float64(c.Date%day)-offset+day, day)
+		if position >= duration.Seconds() {
+			continue
+		}
+
+		i := int(position / sliceLength)
+		if i >= slices {
+			i = slices - 1
+		}
+
+		weights[i] += c.Volume
+		total += c.Volume
+	}
+
+	if total == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+
+	return weights
+}
+
+/*
+Iceberg executes the order showing at most visible on the book: a child order
+of the visible amount is placed, and a new one is placed each time it is
+filled, until the whole amount is filled or ctx is done. Children are pegged
+to the best price of their side, within LimitPrice; with a LimitPrice below
+the best bid for a buy (above the best ask for a sell), they rest at
+LimitPrice.
+*/
+func (api *PoloniexApi) Iceberg(ctx context.Context, p ExecutionParams, visible float64, progress func(ExecutionProgress)) (ExecutionProgress, error) {
+	if visible <= 0 {
+		return ExecutionProgress{}, fmt.Errorf("%w: iceberg needs a positive visible amount", ErrInvalidExecution)
+	}
+
+	x, err := api.newExecutor(ctx, p)
+	if err != nil {
+		return ExecutionProgress{}, err
+	}
+
+	err = x.run(ctx, progress, func(now time.Time, filled float64) (float64, bool, bool) {
+		// A partially filled tip is only re-priced, not topped up.
+		if child, ok := x.manager.Get(x.child); ok && !child.State.Done() {
+			return filled + child.Remaining(), false, false
+		}
+
+		return math.Min(filled+visible, x.params.Amount), false, false
+	})
+
+	return x.finish(ctx, err)
+}
+
+// executeSchedule runs the scheduled algorithms, with the amount of each slice
+// proportional to its weight.
+func (api *PoloniexApi) executeSchedule(ctx context.Context, p ExecutionParams, duration time.Duration, weights []float64, progress func(ExecutionProgress)) (ExecutionProgress, error) {
+	x, err := api.newExecutor(ctx, p)
+	if err != nil {
+		return ExecutionProgress{}, err
+	}
+
+	cumulative := cumulativeShares(weights)
+	end := x.start.Add(duration)
+	giveUp := end.Add(3 * x.params.Interval)
+
+	err = x.run(ctx, progress, func(now time.Time, filled float64) (float64, bool, bool) {
+		if !now.Before(giveUp) {
+			return x.params.Amount, true, true
+		}
+
+		if !now.Before(end) {
+			return x.params.Amount, true, false
+		}
+
+		return x.params.Amount * cumulative[sliceAt(now.Sub(x.start), duration, len(cumulative))], false, false
+	})
+
+	return x.finish(ctx, err)
+}
+
+// cumulativeShares returns the share of the amount to be filled at the end of
+// each slice, the last one being 1.
+func cumulativeShares(weights []float64) []float64 {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	cumulative := make([]float64, len(weights))
+	sum := 0.0
+	for i, w := range weights {
+		sum += w
+		cumulative[i] = sum / total
+	}
+
+	return cumulative
+}
+
+// sliceAt returns the slice running elapsed after the start of duration split
+// in slices, the last one once duration is over.
+func sliceAt(elapsed, duration time.Duration, slices int) int {
+	i := int(elapsed / (duration / time.Duration(slices)))
+	if i >= slices {
+		i = slices - 1
+	}
+
+	return i
+}
+
+// executor places and re-prices the child orders of an execution.
+type executor struct {
+	api     *PoloniexApi
+	manager *OrderManager
+	params  ExecutionParams
+	start   time.Time
+	arrival float64
+	child   int64
+	last    ExecutionProgress
+}
+
+func (api *PoloniexApi) newExecutor(ctx context.Context, p ExecutionParams) (*executor, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	x := &executor{
+		api:     api,
+		manager: NewOrderManager(api),
+		params:  p,
+		start:   time.Now(),
+	}
+
+	bid, ask, err := x.touch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	x.arrival = (bid + ask) / 2
+
+	x.last = x.progress()
+
+	return x, nil
+}
+
+/*
+run polls and adjusts the child order every interval. target returns the
+amount that should be filled or working at now, whether the child should cross
+the book, and whether the execution should stop.
+*/
+func (x *executor) run(ctx context.Context, progress func(ExecutionProgress), target func(now time.Time, filled float64) (float64, bool, bool)) error {
+	ticker := time.NewTicker(x.params.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := x.manager.PollContext(ctx); err != nil {
+			return stopped(ctx, err)
+		}
+
+		x.last = x.progress()
+		if progress != nil {
+			progress(x.last)
+		}
+
+		if x.params.Amount-x.last.Filled < amountEpsilon {
+			return nil
+		}
+
+		amount, aggressive, stop := target(time.Now(), x.last.Filled)
+		if stop {
+			return nil
+		}
+
+		if err := x.adjust(ctx, amount-x.last.Filled, aggressive); err != nil {
+			return stopped(ctx, err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// stopped returns the error of ctx instead of err when the request failed
+// because the execution was cancelled.
+func stopped(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// minTotal returns the minimum total of an order on the market, from the
+// MinTotals of the validator of the api, or of NewOrderValidator when there is
+// none.
+func (x *executor) minTotal() float64 {
+	v := x.api.Validator
+	if v == nil {
+		v = NewOrderValidator()
+	}
+
+	base, _, _ := splitPair(x.params.Pair)
+
+	return v.MinTotals[base]
+}
+
+// adjust resizes the working child order to amount, at the current price.
+func (x *executor) adjust(ctx context.Context, amount float64, aggressive bool) error {
+	bid, ask, err := x.touch(ctx)
+	if err != nil {
+		return err
+	}
+
+	rate := bid
+	if x.params.Side == SideSell {
+		rate = ask
+	}
+	if aggressive {
+		rate = ask
+		if x.params.Side == SideSell {
+			rate = bid
+		}
+	}
+
+	if x.params.LimitPrice != 0 {
+		if x.params.Side == SideBuy {
+			rate = math.Min(rate, x.params.LimitPrice)
+		} else {
+			rate = math.Max(rate, x.params.LimitPrice)
+		}
+	}
+
+	amount = math.Floor(amount*1e8) / 1e8
+
+	child, working := x.manager.Get(x.child)
+	if working && child.State == OrderCancelling {
+		// Its last fills may not be known yet.
+		return nil
+	}
+	working = working && child.State.Working()
+
+	if working && child.Rate == rate && math.Abs(child.Remaining()-amount) < amountEpsilon {
+		return nil
+	}
+
+	if amount*rate < x.minTotal()-amountEpsilon || amount < amountEpsilon {
+		if working && amount < amountEpsilon {
+			_, err = x.manager.CancelContext(ctx, x.child)
+		}
+		return err
+	}
+
+	if working {
+		_, err = x.manager.MoveContext(ctx, x.child, rate, amount, nil)
+		return err
+	}
+
+	order, err := x.manager.PlaceContext(ctx, x.params.Side, x.params.Pair, rate, amount, nil)
+	if err != nil {
+		return err
+	}
+	x.child = order.ID
+
+	return nil
+}
+
+// touch returns the best bid and ask.
+func (x *executor) touch(ctx context.Context) (float64, float64, error) {
+	books, err := x.api.ApiPublicOrderBookContext(ctx, x.params.Pair, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	book := books[x.params.Pair]
+	if book.IsFrozen != 0 || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return 0, 0, fmt.Errorf("Market %s is frozen or its book is empty", x.params.Pair)
+	}
+
+	return book.Bids[0][0], book.Asks[0][0], nil
+}
+
+func (x *executor) progress() ExecutionProgress {
+	out := ExecutionProgress{
+		Time:         time.Now(),
+		Side:         x.params.Side,
+		Pair:         x.params.Pair,
+		Amount:       x.params.Amount,
+		ArrivalPrice: x.arrival,
+	}
+
+	total := 0.0
+	for _, o := range x.manager.Orders() {
+		out.Filled += o.Filled
+		out.Fees += o.Fees
+		total += o.Filled * o.AveragePrice
+		out.Orders++
+	}
+
+	if out.Filled != 0 {
+		out.AveragePrice = total / out.Filled
+
+		out.Slippage = (out.AveragePrice - x.arrival) / x.arrival
+		if x.params.Side == SideSell {
+			out.Slippage = -out.Slippage
+		}
+	}
+
+	return out
+}
+
+// settle polls until the last child has its trades, for at most twice the
+// MissingGrace of the manager.
+func (x *executor) settle(ctx context.Context) error {
+	deadline := time.Now().Add(2 * x.manager.MissingGrace)
+
+	for {
+		if child, ok := x.manager.Get(x.child); !ok || child.State.Done() || time.Now().After(deadline) {
+			return nil
+		}
+
+		if err := sleepContext(ctx, x.params.Interval); err != nil {
+			return err
+		}
+
+		if err := x.manager.PollContext(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// finish cancels the working child and returns the final progress.
+func (x *executor) finish(ctx context.Context, err error) (ExecutionProgress, error) {
+	if child, ok := x.manager.Get(x.child); ok && !child.State.Done() {
+		// The child must be cancelled even when the execution was.
+		if child.State.Working() {
+			if _, cancelErr := x.manager.CancelContext(context.WithoutCancel(ctx), x.child); cancelErr != nil && err == nil {
+				err = cancelErr
+			}
+		}
+		if settleErr := x.settle(context.WithoutCancel(ctx)); settleErr != nil && err == nil {
+			err = settleErr
+		}
+		x.last = x.progress()
+	}
+
+	x.last.Done = true
+
+	return x.last, err
+}
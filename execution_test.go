@@ -0,0 +1,147 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVolumeProfile(t *testing.T) {
+	start := time.Date(2018, 3, 10, 23, 0, 0, 0, time.UTC)
+	at := func(days int, hour, min int) int64 {
+		return time.Date(2018, 3, 10-days, hour, min, 0, 0, time.UTC).Unix()
+	}
+
+	cases := []struct {
+		name  string
+		chart []ChartEntry
+		want  []float64
+	}{
+		{"no history", nil, []float64{1, 1, 1, 1}},
+		{"no volume", []ChartEntry{{Date: at(1, 23, 0)}}, []float64{1, 1, 1, 1}},
+		{
+			"same time of day over several days",
+			[]ChartEntry{
+				{Date: at(1, 23, 0), Volume: 1},
+				{Date: at(2, 23, 10), Volume: 2},
+				{Date: at(1, 23, 35), Volume: 3},
+				{Date: at(3, 23, 55), Volume: 4},
+			},
+			[]float64{3, 7, 0, 0},
+		},
+		{
+			"window over midnight",
+			[]ChartEntry{
+				{Date: at(1, 0, 20), Volume: 5},  // 1:20 into the window
+				{Date: at(1, 22, 59), Volume: 7}, // before the window
+				{Date: at(1, 12, 0), Volume: 9},  // after the window
+			},
+			[]float64{0, 0, 5, 0},
+		},
+	}
+
+	for _, c := range cases {
+		// Two hours in slices of half an hour.
+		got := volumeProfile(c.chart, start, 2*time.Hour, 4)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	if got, want := cumulativeShares([]float64{1, 1, 1, 1}), []float64{0.25, 0.5, 0.75, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("even shares: got %v, want %v", got, want)
+	}
+
+	if got, want := cumulativeShares([]float64{3, 0, 1}), []float64{0.75, 0.75, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("weighted shares: got %v, want %v", got, want)
+	}
+
+	cases := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{0, 0},
+		{29 * time.Minute, 0},
+		{30 * time.Minute, 1},
+		{119 * time.Minute, 3},
+		{3 * time.Hour, 3},
+	}
+
+	for _, c := range cases {
+		if got := sliceAt(c.elapsed, 2*time.Hour, 4); got != c.want {
+			t.Errorf("slice after %s: got %d, want %d", c.elapsed, got, c.want)
+		}
+	}
+}
+
+func TestExecutionMinTotal(t *testing.T) {
+	x := &executor{api: testApi(nil), params: ExecutionParams{Pair: "BTC_XMR"}}
+	if got := x.minTotal(); got != 0.0001 {
+		t.Errorf("without validator: got %v, want 0.0001", got)
+	}
+
+	x.api.Validator = &OrderValidator{MinTotals: map[string]float64{"BTC": 0.001, "ETH": 0.01}}
+	if got := x.minTotal(); got != 0.001 {
+		t.Errorf("with validator: got %v, want 0.001", got)
+	}
+
+	x.params.Pair = "USDT_BTC"
+	if got := x.minTotal(); got != 0 {
+		t.Errorf("unknown base: got %v, want 0", got)
+	}
+}
+
+func TestExecutionCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var commands []string
+
+	api := testApi(func(command string, params url.Values) string {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, command)
+
+		switch command {
+		case CMD_PUBLIC_ORDER_BOOK:
+			return `{"asks":[["0.0126",1]],"bids":[["0.0124",1]],"isFrozen":"0","seq":1}`
+		case CMD_PRIVATE_BUY:
+			return `{"orderNumber":"10","resultingTrades":{}}`
+		case CMD_PRIVATE_OPEN_ORDERS:
+			return `{"BTC_XMR":[{"orderNumber":"10","type":"buy","rate":"0.0124","amount":"0.5","total":"0","date":"2018-03-01 12:00:00","margin":0}]}`
+		case CMD_PRIVATE_CANCEL_ORDER:
+			return `{"success":1,"amount":"0.5","message":"Order #10 canceled."}`
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	polls := 0
+	p := ExecutionParams{Side: SideBuy, Pair: "BTC_XMR", Amount: 1, Interval: time.Millisecond}
+
+	// Cancelled after the first child is placed: the next requests fail with
+	// the context, and the child is cancelled anyway.
+	progress, err := api.TWAP(ctx, p, time.Hour, 2, func(ExecutionProgress) {
+		if polls++; polls == 2 {
+			cancel()
+		}
+	})
+
+	if !errors.Is(err, context.Canceled) || !progress.Done || progress.Orders != 1 {
+		t.Errorf("got %+v %v, want one order and context.Canceled", progress, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := []string{CMD_PUBLIC_ORDER_BOOK, CMD_PRIVATE_OPEN_ORDERS, CMD_PUBLIC_ORDER_BOOK, CMD_PRIVATE_BUY, CMD_PRIVATE_OPEN_ORDERS, CMD_PRIVATE_CANCEL_ORDER, CMD_PRIVATE_ORDER_TRADES}
+	if !reflect.DeepEqual(commands, want) {
+		t.Errorf("got commands %v, want %v", commands, want)
+	}
+}
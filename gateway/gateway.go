@@ -0,0 +1,212 @@
+/*
+Package gateway serves a PoloniexApi over HTTP, so that tools written in other
+languages share a single rate-limited process holding the credentials.
+
+Every request carries a token, "Authorization: Bearer <token>", whose scope is
+either read (market data and open orders) or trade (also placing and
+cancelling orders). Rates and amounts are decimal strings, in requests and
+answers.
+
+	GET    /ticker[?pair=BTC_XMR]
+	GET    /book/{pair}[?depth=20]
+	GET    /orders[?pair=BTC_XMR]
+	POST   /orders        {"pair":"BTC_XMR","side":"buy","rate":"0.01","amount":"1.5"}
+	DELETE /orders/{id}
+
+Errors are answered as {"error":"..."}: 400 for an invalid request, 401 for a
+missing or invalid token, 403 for a token without the scope of the endpoint,
+422 when the exchange refused the request and 502 when it did not answer.
+*/
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Scope of a token.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeTrade Scope = "trade"
+)
+
+// Allows tells if a token of scope s may call an endpoint needing scope.
+func (s Scope) Allows(scope Scope) bool {
+	return s == scope || s == ScopeTrade
+}
+
+// Token grants a client access to the gateway.
+type Token struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	Scope Scope  `json:"scope"`
+}
+
+/*
+LoadTokens reads the tokens of a JSON file:
+
+	[{"name":"grafana","token":"...","scope":"read"},
+	 {"name":"rebalancer","token":"...","scope":"trade"}]
+*/
+func LoadTokens(path string) ([]Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]Token, 0)
+	if err = json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("Could not read tokens from %s (%s)", path, err.Error())
+	}
+
+	return tokens, nil
+}
+
+// Server is an http.Handler serving the gateway endpoints.
+type Server struct {
+	api    *poloniexapi.PoloniexApi
+	tokens []Token
+	mux    *http.ServeMux
+}
+
+// CheckTokens checks that tokens are long enough, unique and of a known
+// scope.
+func CheckTokens(tokens []Token) error {
+	seen := make(map[string]bool)
+
+	for _, t := range tokens {
+		if len(t.Token) < 16 {
+			return fmt.Errorf("Token of %s is too short, 16 characters at least", t.Name)
+		}
+
+		if t.Scope != ScopeRead && t.Scope != ScopeTrade {
+			return fmt.Errorf("Unknown scope %q for %s", t.Scope, t.Name)
+		}
+
+		if seen[t.Token] {
+			return fmt.Errorf("Token of %s is used twice", t.Name)
+		}
+		seen[t.Token] = true
+	}
+
+	return nil
+}
+
+// NewServer checks tokens and returns a server making its requests with api.
+func NewServer(api *poloniexapi.PoloniexApi, tokens []Token) (*Server, error) {
+	if err := CheckTokens(tokens); err != nil {
+		return nil, err
+	}
+
+	s := &Server{api: api, tokens: tokens, mux: http.NewServeMux()}
+
+	s.handle("GET /ticker", ScopeRead, s.ticker)
+	s.handle("GET /book/{pair}", ScopeRead, s.book)
+	s.handle("GET /orders", ScopeRead, s.openOrders)
+	s.handle("POST /orders", ScopeTrade, s.placeOrder)
+	s.handle("DELETE /orders/{id}", ScopeTrade, s.cancelOrder)
+
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handler answers a request with api, making its requests with the context
+// of r.
+type handler func(api *poloniexapi.PoloniexApi, r *http.Request) (int, interface{}, error)
+
+func (s *Server) handle(pattern string, scope Scope, h handler) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		token, ok := s.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, errorBody{"missing or invalid token"})
+			return
+		}
+
+		if !token.Scope.Allows(scope) {
+			writeJSON(w, http.StatusForbidden, errorBody{fmt.Sprintf("token of %s lacks the %s scope", token.Name, scope)})
+			return
+		}
+
+		status, body, err := h(s.api, r)
+		if err != nil {
+			writeJSON(w, errorStatus(err), errorBody{err.Error()})
+			return
+		}
+
+		writeJSON(w, status, body)
+	})
+}
+
+// authenticate finds the token of a request.
+func (s *Server) authenticate(r *http.Request) (Token, bool) {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return Token{}, false
+	}
+
+	return FindToken(s.tokens, given)
+}
+
+// FindToken returns the token of tokens equal to given, comparing it with
+// every token in constant time.
+func FindToken(tokens []Token, given string) (Token, bool) {
+	if given == "" {
+		return Token{}, false
+	}
+
+	var found Token
+	match := 0
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(given), []byte(t.Token)) == 1 {
+			found, match = t, 1
+		}
+	}
+
+	return found, match == 1
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// errBadRequest marks errors in the request itself.
+var errBadRequest = errors.New("bad request")
+
+func badRequest(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errBadRequest, fmt.Sprintf(format, args...))
+}
+
+func errorStatus(err error) int {
+	var apiError *poloniexapi.ApiError
+	var validationError *poloniexapi.OrderValidationError
+
+	switch {
+	case errors.Is(err, errBadRequest), errors.Is(err, poloniexapi.ErrInvalidOrderOptions), errors.As(err, &validationError):
+		return http.StatusBadRequest
+	case errors.As(err, &apiError):
+		return http.StatusUnprocessableEntity
+	case poloniexapi.IsAmbiguous(err):
+		return http.StatusBadGateway
+	}
+
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mycroft/poloniex-api/internal/polotest"
+)
+
+func testServer(t *testing.T) *Server {
+	api := polotest.Api(polotest.Answers(map[string]string{
+		"returnTicker": `{"BTC_XMR":{"id":114,"last":"0.0125","lowestAsk":"0.0126","highestBid":"0.0124",
+			"percentChange":"0.01","baseVolume":"10","quoteVolume":"800","isFrozen":"0","high24hr":"0.013","low24hr":"0.012"}}`,
+		"buy":         `{"orderNumber":"31226040","resultingTrades":[{"amount":"1","date":"2014-10-18 23:03:21","rate":"0.0125","total":"0.0125","tradeID":"16164","type":"buy"}]}`,
+		"cancelOrder": `{"error":"Invalid order number, or you are not the person who placed the order."}`,
+	}))
+
+	server, err := NewServer(api, []Token{
+		{Name: "reader", Token: "read-token-0123456789", Scope: ScopeRead},
+		{Name: "trader", Token: "trade-token-0123456789", Scope: ScopeTrade},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server
+}
+
+func TestGateway(t *testing.T) {
+	server := testServer(t)
+
+	cases := []struct {
+		method, path, token, body string
+		status                    int
+		contains                  string
+	}{
+		{"GET", "/ticker", "", "", 401, "invalid token"},
+		{"GET", "/ticker", "wrong-token-0123456789", "", 401, "invalid token"},
+		{"GET", "/ticker?pair=BTC_XMR", "read-token-0123456789", "", 200, `"last":"0.01250000"`},
+		{"POST", "/orders", "read-token-0123456789", `{}`, 403, "trade scope"},
+		{"POST", "/orders", "trade-token-0123456789", `{"pair":"BTC_XMR","side":"buy","rate":"1e-2","amount":"1"}`, 400, "decimal string"},
+		{"POST", "/orders", "trade-token-0123456789", `{"pair":"BTC_XMR","side":"buy","rate":"0.0125","amount":"1"}`, 201, `"orderNumber":"31226040"`},
+		{"DELETE", "/orders/12", "trade-token-0123456789", "", 422, "Invalid order number"},
+		{"DELETE", "/orders/abc", "trade-token-0123456789", "", 400, "invalid order number"},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		if rec.Code != c.status || !strings.Contains(rec.Body.String(), c.contains) {
+			t.Errorf("%s %s: got %d %s, want %d with %s", c.method, c.path, rec.Code, rec.Body.String(), c.status, c.contains)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Rates and amounts are given with the 8 decimals the exchange uses.
+func decimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', 8, 64)
+}
+
+var decimalPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// parseDecimal reads a positive decimal string.
+func parseDecimal(name, value string) (float64, error) {
+	if !decimalPattern.MatchString(value) {
+		return 0, badRequest("%s must be a decimal string, got %q", name, value)
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f <= 0 {
+		return 0, badRequest("%s must be positive, got %q", name, value)
+	}
+
+	return f, nil
+}
+
+type ticker struct {
+	Last          string `json:"last"`
+	LowestAsk     string `json:"lowestAsk"`
+	HighestBid    string `json:"highestBid"`
+	PercentChange string `json:"percentChange"`
+	BaseVolume    string `json:"baseVolume"`
+	QuoteVolume   string `json:"quoteVolume"`
+	High24hr      string `json:"high24hr"`
+	Low24hr       string `json:"low24hr"`
+	IsFrozen      bool   `json:"isFrozen"`
+}
+
+func (s *Server) ticker(api *poloniexapi.PoloniexApi, r *http.Request) (int, interface{}, error) {
+	tickers, err := api.ApiPublicTickerContext(r.Context())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	pair := r.URL.Query().Get("pair")
+	if pair != "" {
+		if _, ok := tickers[pair]; !ok {
+			return 0, nil, badRequest("unknown market %s", pair)
+		}
+	}
+
+	out := make(map[string]ticker)
+	for p, t := range tickers {
+		if pair != "" && p != pair {
+			continue
+		}
+
+		out[p] = ticker{
+			Last:          decimal(t.Last),
+			LowestAsk:     decimal(t.LowestAsk),
+			HighestBid:    decimal(t.HighestBid),
+			PercentChange: decimal(t.PercentChange),
+			BaseVolume:    decimal(t.BaseVolume),
+			QuoteVolume:   decimal(t.QuoteVolume),
+			High24hr:      decimal(t.High24hr),
+			Low24hr:       decimal(t.Low24hr),
+			IsFrozen:      t.IsFrozen != 0,
+		}
+	}
+
+	return http.StatusOK, out, nil
+}
+
+type level struct {
+	Rate   string `json:"rate"`
+	Amount string `json:"amount"`
+}
+
+type book struct {
+	Pair     string  `json:"pair"`
+	Seq      int64   `json:"seq"`
+	IsFrozen bool    `json:"isFrozen"`
+	Asks     []level `json:"asks"`
+	Bids     []level `json:"bids"`
+}
+
+func levels(in [][2]float64) []level {
+	out := make([]level, 0, len(in))
+	for _, l := range in {
+		out = append(out, level{Rate: decimal(l[0]), Amount: decimal(l[1])})
+	}
+	return out
+}
+
+func (s *Server) book(api *poloniexapi.PoloniexApi, r *http.Request) (int, interface{}, error) {
+	pair := r.PathValue("pair")
+	if pair == "all" {
+		return 0, nil, badRequest("the book of a single market may be asked for")
+	}
+
+	depth := 20
+	if value := r.URL.Query().Get("depth"); value != "" {
+		var err error
+		if depth, err = strconv.Atoi(value); err != nil || depth < 1 {
+			return 0, nil, badRequest("depth must be a positive integer, got %q", value)
+		}
+	}
+
+	books, err := api.ApiPublicOrderBookContext(r.Context(), pair, depth)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	b := books[pair]
+
+	return http.StatusOK, book{
+		Pair:     pair,
+		Seq:      int64(b.Seq),
+		IsFrozen: b.IsFrozen != 0,
+		Asks:     levels(b.Asks),
+		Bids:     levels(b.Bids),
+	}, nil
+}
+
+type openOrder struct {
+	OrderNumber    string `json:"orderNumber"`
+	ClientOrderID  int64  `json:"clientOrderId,omitempty"`
+	Pair           string `json:"pair"`
+	Side           string `json:"side"`
+	Rate           string `json:"rate"`
+	Amount         string `json:"amount"`
+	StartingAmount string `json:"startingAmount"`
+	Total          string `json:"total"`
+	Date           string `json:"date"`
+	Margin         bool   `json:"margin"`
+}
+
+func (s *Server) openOrders(api *poloniexapi.PoloniexApi, r *http.Request) (int, interface{}, error) {
+	pair := r.URL.Query().Get("pair")
+	if pair == "" {
+		pair = "all"
+	}
+
+	orders, err := api.ApiPrivateOpenOrdersContext(r.Context(), pair)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	out := make([]openOrder, 0)
+	for p, list := range orders {
+		for _, o := range list {
+			out = append(out, openOrder{
+				OrderNumber:    o.OrderNumber,
+				ClientOrderID:  o.ClientOrderId,
+				Pair:           p,
+				Side:           o.Type,
+				Rate:           decimal(o.Rate),
+				Amount:         decimal(o.Amount),
+				StartingAmount: decimal(o.StartingAmount),
+				Total:          decimal(o.Total),
+				Date:           o.Date,
+				Margin:         o.Margin != 0,
+			})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Pair != out[j].Pair {
+			return out[i].Pair < out[j].Pair
+		}
+		return out[i].OrderNumber < out[j].OrderNumber
+	})
+
+	return http.StatusOK, out, nil
+}
+
+type orderRequest struct {
+	Pair          string `json:"pair"`
+	Side          string `json:"side"`
+	Rate          string `json:"rate"`
+	Amount        string `json:"amount"`
+	TimeInForce   string `json:"timeInForce"` // GTC (default), FOK or IOC
+	PostOnly      bool   `json:"postOnly"`
+	ClientOrderID int64  `json:"clientOrderId"`
+}
+
+type trade struct {
+	TradeID int64  `json:"tradeId"`
+	Date    string `json:"date"`
+	Side    string `json:"side"`
+	Rate    string `json:"rate"`
+	Amount  string `json:"amount"`
+	Total   string `json:"total"`
+}
+
+type placedOrder struct {
+	OrderNumber   string  `json:"orderNumber"`
+	ClientOrderID int64   `json:"clientOrderId,omitempty"`
+	Pair          string  `json:"pair"`
+	Trades        []trade `json:"trades"`
+}
+
+func (s *Server) placeOrder(api *poloniexapi.PoloniexApi, r *http.Request) (int, interface{}, error) {
+	var req orderRequest
+
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return 0, nil, badRequest("invalid order: %s", err.Error())
+	}
+
+	side := poloniexapi.OrderSide(req.Side)
+	if side != poloniexapi.SideBuy && side != poloniexapi.SideSell {
+		return 0, nil, badRequest("side must be buy or sell, got %q", req.Side)
+	}
+
+	rate, err := parseDecimal("rate", req.Rate)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	amount, err := parseDecimal("amount", req.Amount)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	opts := &poloniexapi.OrderOptions{PostOnly: req.PostOnly, ClientOrderID: req.ClientOrderID}
+	switch strings.ToUpper(req.TimeInForce) {
+	case "", "GTC":
+	case "FOK":
+		opts.TimeInForce = poloniexapi.TimeInForceFOK
+	case "IOC":
+		opts.TimeInForce = poloniexapi.TimeInForceIOC
+	default:
+		return 0, nil, badRequest("timeInForce must be GTC, FOK or IOC, got %q", req.TimeInForce)
+	}
+
+	order, err := api.PlaceOrderContext(r.Context(), side, req.Pair, rate, amount, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	out := placedOrder{
+		OrderNumber:   strconv.FormatInt(order.OrderNumber, 10),
+		ClientOrderID: order.ClientOrderId,
+		Pair:          req.Pair,
+		Trades:        make([]trade, 0),
+	}
+
+	for _, t := range order.ResultingTrades[req.Pair] {
+		out.Trades = append(out.Trades, trade{
+			TradeID: t.TradeID,
+			Date:    t.Date,
+			Side:    t.Type,
+			Rate:    decimal(t.Rate),
+			Amount:  decimal(t.Amount),
+			Total:   decimal(t.Total),
+		})
+	}
+
+	return http.StatusCreated, out, nil
+}
+
+type cancelledOrder struct {
+	OrderNumber string `json:"orderNumber"`
+	Cancelled   bool   `json:"cancelled"`
+	Amount      string `json:"amount"` // left unfilled
+}
+
+func (s *Server) cancelOrder(api *poloniexapi.PoloniexApi, r *http.Request) (int, interface{}, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, nil, badRequest("invalid order number %q", r.PathValue("id"))
+	}
+
+	ok, cancel, err := api.ApiPrivateCancelContext(r.Context(), id)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, cancelledOrder{
+		OrderNumber: strconv.FormatInt(id, 10),
+		Cancelled:   ok,
+		Amount:      decimal(cancel.Amount),
+	}, nil
+}
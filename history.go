@@ -0,0 +1,138 @@
+package poloniexapi
+
+import (
+	"fmt"
+	"time"
+)
+
+// Number of rows asked for at once to returnTradeHistory and
+// returnLendingHistory, the most they return, and longest range asked at once
+// by LoadTradeHistory and LoadLendingHistory.
+var (
+	HistoryLimit  = 10000
+	HistoryWindow = 7 * 24 * time.Hour
+)
+
+/*
+LoadTradeHistory returns your trades on currencyPair, or "all", between start
+and end, by market like ApiPrivateTradeHistory, however many there are: the
+range is asked in windows of HistoryWindow with an explicit limit, and windows
+that come back full are split in two and asked again. It fails when a single
+second has HistoryLimit trades or more.
+*/
+func (api *PoloniexApi) LoadTradeHistory(currencyPair string, start, end time.Time) (map[string][]Trade, error) {
+	out := make(map[string][]Trade)
+	seen := make(map[string]map[int64]bool)
+
+	err := historyWindows(start.Unix(), end.Unix(), func(from, to int64) (bool, error) {
+		history, err := api.ApiPrivateTradeHistoryLimit(currencyPair, int(from), int(to), HistoryLimit)
+		if err != nil {
+			return false, err
+		}
+
+		count := 0
+		for _, trades := range history {
+			count += len(trades)
+		}
+		if count >= HistoryLimit {
+			return true, nil
+		}
+
+		for pair, trades := range history {
+			if seen[pair] == nil {
+				seen[pair] = make(map[int64]bool)
+			}
+
+			for _, t := range trades {
+				if !seen[pair][t.TradeID] {
+					seen[pair][t.TradeID] = true
+					out[pair] = append(out[pair], t)
+				}
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// LoadLendingHistory returns your loans closed between start and end,
+// however many there are, asked as in LoadTradeHistory.
+func (api *PoloniexApi) LoadLendingHistory(start, end time.Time) ([]LendingHistoryEntry, error) {
+	out := make([]LendingHistoryEntry, 0)
+	seen := make(map[int64]bool)
+
+	err := historyWindows(start.Unix(), end.Unix(), func(from, to int64) (bool, error) {
+		loans, err := api.ApiPrivateLendingHistory(from, to, HistoryLimit)
+		if err != nil {
+			return false, err
+		}
+
+		if len(loans) >= HistoryLimit {
+			return true, nil
+		}
+
+		for _, l := range loans {
+			if !seen[l.Id] {
+				seen[l.Id] = true
+				out = append(out, l)
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+/*
+historyWindows calls fetch on consecutive windows of HistoryWindow covering
+start to end, in order. fetch tells when a window came back full, without
+keeping its rows: it is then split in two and each half is fetched again.
+Windows share their bounds, since the exchange may include both, so fetch
+must drop the rows it already has.
+*/
+func historyWindows(start, end int64, fetch func(from, to int64) (bool, error)) error {
+	window := int64(HistoryWindow / time.Second)
+	if window < 1 {
+		window = 1
+	}
+
+	for from := start; from < end; from += window {
+		to := from + window
+		if to > end {
+			to = end
+		}
+
+		if err := historyWindow(from, to, fetch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func historyWindow(from, to int64, fetch func(from, to int64) (bool, error)) error {
+	full, err := fetch(from, to)
+	if err != nil || !full {
+		return err
+	}
+
+	if to-from <= 1 {
+		return fmt.Errorf("Could not load history: %d rows or more at %s", HistoryLimit, time.Unix(from, 0).UTC().Format(TradeDateLayout))
+	}
+
+	middle := from + (to-from)/2
+	if err = historyWindow(from, middle, fetch); err != nil {
+		return err
+	}
+
+	return historyWindow(middle, to, fetch)
+}
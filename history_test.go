@@ -0,0 +1,118 @@
+package poloniexapi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTradeHistory(t *testing.T) {
+	limit, window := HistoryLimit, HistoryWindow
+	HistoryLimit, HistoryWindow = 4, 24*time.Hour
+	defer func() { HistoryLimit, HistoryWindow = limit, window }()
+
+	start := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// 10 trades within an hour on the first day, one on the third, and one
+	// on the bound of the first two days.
+	var times []time.Time
+	for i := 0; i < 10; i++ {
+		times = append(times, start.Add(time.Duration(i)*6*time.Minute))
+	}
+	times = append(times, start.Add(24*time.Hour), start.Add(50*time.Hour))
+
+	calls := 0
+	api := testApi(func(command string, params url.Values) string {
+		if command != CMD_PRIVATE_TRADE_HISTORY {
+			return `{"error":"unexpected command"}`
+		}
+		calls++
+
+		if params.Get("limit") != strconv.Itoa(HistoryLimit) {
+			return `{"error":"no limit"}`
+		}
+
+		from, _ := strconv.ParseInt(params.Get("start"), 10, 64)
+		to, _ := strconv.ParseInt(params.Get("end"), 10, 64)
+
+		var trades []string
+		for i, at := range times {
+			if at.Unix() >= from && at.Unix() <= to && len(trades) < HistoryLimit {
+				trades = append(trades, fmt.Sprintf(`{"tradeID":%d,"date":%q,"type":"buy","rate":"0.01","amount":"1","total":"0.01","fee":"0"}`, i, at.Format(TradeDateLayout)))
+			}
+		}
+		if len(trades) == 0 {
+			return `[]`
+		}
+		return `{"BTC_XMR":[` + strings.Join(trades, ",") + `]}`
+	})
+
+	history, err := api.LoadTradeHistory("all", start, start.Add(72*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history["BTC_XMR"]) != len(times) {
+		t.Fatalf("got %d trades, want %d", len(history["BTC_XMR"]), len(times))
+	}
+
+	seen := make(map[int64]bool)
+	for _, trade := range history["BTC_XMR"] {
+		if seen[trade.TradeID] {
+			t.Errorf("trade %d twice", trade.TradeID)
+		}
+		seen[trade.TradeID] = true
+	}
+
+	if calls <= 3 {
+		t.Errorf("full windows not split: %d calls", calls)
+	}
+
+	// As many trades in one second as the limit.
+	times = times[:0]
+	for i := 0; i < HistoryLimit; i++ {
+		times = append(times, start)
+	}
+
+	if _, err = api.LoadTradeHistory("all", start, start.Add(time.Hour)); err == nil {
+		t.Error("no error on a full second")
+	}
+}
+
+func TestLoadLendingHistory(t *testing.T) {
+	limit := HistoryLimit
+	HistoryLimit = 2
+	defer func() { HistoryLimit = limit }()
+
+	start := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	closes := []time.Time{start.Add(time.Hour), start.Add(2 * time.Hour), start.Add(3 * time.Hour)}
+
+	api := testApi(func(command string, params url.Values) string {
+		if command != CMD_PRIVATE_LENDING_HISTORY {
+			return `{"error":"unexpected command"}`
+		}
+
+		from, _ := strconv.ParseInt(params.Get("start"), 10, 64)
+		to, _ := strconv.ParseInt(params.Get("end"), 10, 64)
+
+		var loans []string
+		for i, at := range closes {
+			if at.Unix() >= from && at.Unix() <= to && len(loans) < HistoryLimit {
+				loans = append(loans, fmt.Sprintf(`{"id":%d,"currency":"BTC","amount":"1","close":%q}`, i, at.Format(TradeDateLayout)))
+			}
+		}
+		return `[` + strings.Join(loans, ",") + `]`
+	})
+
+	loans, err := api.LoadLendingHistory(start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loans) != len(closes) {
+		t.Errorf("got %d loans, want %d", len(loans), len(closes))
+	}
+}
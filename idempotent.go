@@ -0,0 +1,210 @@
+package poloniexapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// How long to wait between a failed attempt and looking for the order, then
+// before sending it again. Doubled after every attempt.
+var ReconcileDelay = 2 * time.Second
+
+// How far back, from the first attempt, the trade history is searched for an
+// order that may have been placed. It covers long outages and clock skew; the
+// client order id keeps older orders from matching.
+var ReconcileLookback = 24 * time.Hour
+
+/*
+NewClientOrderID returns a positive id suitable for OrderOptions.ClientOrderID,
+made of the current time in milliseconds and a random part, so that ids from
+several processes do not collide. Within a process, ids always increase. It
+panics if no random bytes can be read, rather than returning ids that would
+collide.
+*/
+func NewClientOrderID() int64 {
+	var buf [2]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		panic(fmt.Sprintf("Could not generate a client order id (%s)", err.Error()))
+	}
+
+	millis := time.Now().UnixNano() / int64(time.Millisecond)
+	id := millis<<16 | int64(binary.BigEndian.Uint16(buf[:]))
+
+	for {
+		last := atomic.LoadInt64(&lastClientOrderID)
+		next := id
+		if next <= last {
+			next = last + 1
+		}
+
+		if atomic.CompareAndSwapInt64(&lastClientOrderID, last, next) {
+			return next
+		}
+	}
+}
+
+var lastClientOrderID int64
+
+/*
+PlaceOrderIdempotent places an order such that it is never placed twice, even
+when the call fails in a way that leaves its outcome unknown (timeout,
+connection reset...).
+
+A client order id is set when opts does not have one. When an attempt fails
+ambiguously (see IsAmbiguous), the open orders and the trade history of the
+market are searched for that client order id: if the order is found, it is
+returned, otherwise it is sent again, up to attempts times. Errors reported by
+the exchange are returned right away, unless an earlier attempt was ambiguous,
+in which case the order is looked up first since the exchange may be refusing
+a duplicate.
+*/
+func (api *PoloniexApi) PlaceOrderIdempotent(side OrderSide, currencyPair string, rate, amount float64, opts *OrderOptions, attempts int) (*Order, error) {
+	return api.PlaceOrderIdempotentContext(context.Background(), side, currencyPair, rate, amount, opts, attempts)
+}
+
+// PlaceOrderIdempotentContext is PlaceOrderIdempotent with the context of the
+// requests. It also stops waiting between attempts once ctx is done.
+func (api *PoloniexApi) PlaceOrderIdempotentContext(ctx context.Context, side OrderSide, currencyPair string, rate, amount float64, opts *OrderOptions, attempts int) (*Order, error) {
+	options := OrderOptions{}
+	if opts != nil {
+		options = *opts
+	}
+
+	if options.ClientOrderID == 0 {
+		options.ClientOrderID = NewClientOrderID()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	since := time.Now().Add(-ReconcileLookback)
+	delay := ReconcileDelay
+	ambiguous := false
+
+	var err error
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		var order *Order
+
+		if attempt > 0 {
+			api.observeRetry(string(side))
+		}
+
+		order, err = api.PlaceOrderContext(withAttempt(ctx, attempt), side, currencyPair, rate, amount, &options)
+		if err == nil {
+			return order, nil
+		}
+
+		if !IsAmbiguous(err) && !ambiguous {
+			return nil, err
+		}
+		ambiguous = ambiguous || IsAmbiguous(err)
+
+		if waitErr := sleepContext(ctx, delay); waitErr != nil {
+			return nil, fmt.Errorf("Order with client id %d may have been placed, could not check: %w (after: %s)",
+				options.ClientOrderID, waitErr, err.Error())
+		}
+		delay *= 2
+
+		found, findErr := api.FindOrderByClientIDContext(ctx, currencyPair, options.ClientOrderID, since)
+		if findErr != nil {
+			return nil, fmt.Errorf("Order with client id %d may have been placed, could not check: %s (after: %s)",
+				options.ClientOrderID, findErr.Error(), err.Error())
+		}
+
+		if found != nil {
+			return found, nil
+		}
+
+		if !IsAmbiguous(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+/*
+FindOrderByClientID looks for an order placed with the given client order id,
+first in the open orders of the market, then in its trade history since the
+given time, for orders that were filled right away. It returns nil when the
+order is found in neither.
+*/
+func (api *PoloniexApi) FindOrderByClientID(currencyPair string, clientOrderID int64, since time.Time) (*Order, error) {
+	return api.FindOrderByClientIDContext(context.Background(), currencyPair, clientOrderID, since)
+}
+
+// FindOrderByClientIDContext is FindOrderByClientID with the context of the
+// requests.
+func (api *PoloniexApi) FindOrderByClientIDContext(ctx context.Context, currencyPair string, clientOrderID int64, since time.Time) (*Order, error) {
+	open, err := api.ApiPrivateOpenOrdersContext(ctx, currencyPair)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range open[currencyPair] {
+		if o.ClientOrderId != clientOrderID {
+			continue
+		}
+
+		orderNumber, err := strconv.ParseInt(o.OrderNumber, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Order{
+			Success:         1,
+			OrderNumber:     orderNumber,
+			ClientOrderId:   clientOrderID,
+			ResultingTrades: map[string][]Trade{},
+		}, nil
+	}
+
+	history, err := api.ApiPrivateTradeHistoryContext(ctx, currencyPair, int(since.Unix()), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *Order
+
+	for _, t := range history[currencyPair] {
+		if t.ClientOrderId != clientOrderID {
+			continue
+		}
+
+		if order == nil {
+			order = &Order{
+				Success:         1,
+				OrderNumber:     t.OrderNumber,
+				ClientOrderId:   clientOrderID,
+				ResultingTrades: map[string][]Trade{},
+			}
+		}
+		order.ResultingTrades[currencyPair] = append(order.ResultingTrades[currencyPair], t)
+	}
+
+	return order, nil
+}
+
+// sleepContext waits for d, or until ctx is done and returns its error.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
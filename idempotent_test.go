@@ -0,0 +1,109 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewClientOrderID(t *testing.T) {
+	seen := make(map[int64]bool)
+
+	for i := 0; i < 10000; i++ {
+		id := NewClientOrderID()
+		if id <= 0 || seen[id] {
+			t.Fatalf("got %d, want positive unique ids", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestPlaceOrderIdempotent(t *testing.T) {
+	delay := ReconcileDelay
+	ReconcileDelay = time.Millisecond
+	defer func() { ReconcileDelay = delay }()
+
+	const garbage = "<html>502 Bad Gateway</html>"
+
+	cases := []struct {
+		name    string
+		buys    []string // answers to the successive buys
+		placed  bool     // the first ambiguous buy reached the book
+		wantErr bool
+		sent    int
+	}{
+		{"accepted", []string{`{"orderNumber":"10"}`}, false, false, 1},
+		{"refused", []string{`{"error":"Not enough BTC."}`}, false, true, 1},
+		{"placed despite the error", []string{garbage}, true, false, 1},
+		{"lost then sent again", []string{garbage, `{"orderNumber":"10"}`}, false, false, 2},
+		{"always failing", []string{garbage, garbage, garbage}, false, true, 3},
+	}
+
+	for _, c := range cases {
+		var clientID int64
+		var start int64
+		sent := 0
+
+		api := testApi(func(command string, params url.Values) string {
+			switch command {
+			case CMD_PRIVATE_BUY:
+				clientID, _ = strconv.ParseInt(params.Get("clientOrderId"), 10, 64)
+				sent++
+				return c.buys[sent-1]
+			case CMD_PRIVATE_OPEN_ORDERS:
+				if c.placed {
+					return fmt.Sprintf(`[{"orderNumber":"10","type":"buy","rate":"0.0125","startingAmount":"1","amount":"1","total":"0.0125","date":"2018-03-01 12:00:00","margin":0,"clientOrderId":"%d"}]`, clientID)
+				}
+				return `[]`
+			case CMD_PRIVATE_TRADE_HISTORY:
+				start, _ = strconv.ParseInt(params.Get("start"), 10, 64)
+				return `[]`
+			}
+			return `{"error":"unexpected command"}`
+		})
+
+		order, err := api.PlaceOrderIdempotent(SideBuy, "BTC_XMR", 0.0125, 1, nil, 3)
+		if (err != nil) != c.wantErr || sent != c.sent {
+			t.Errorf("%s: got %+v %v after %d buys, want error %v after %d", c.name, order, err, sent, c.wantErr, c.sent)
+			continue
+		}
+
+		if err == nil && (order.OrderNumber != 10 || c.placed && order.ClientOrderId != clientID) {
+			t.Errorf("%s: got order %+v", c.name, order)
+		}
+
+		if start != 0 && time.Since(time.Unix(start, 0)) < ReconcileLookback {
+			t.Errorf("%s: history searched from %s, want %s back", c.name, time.Unix(start, 0), ReconcileLookback)
+		}
+	}
+}
+
+func TestPlaceOrderIdempotentContext(t *testing.T) {
+	delay := ReconcileDelay
+	ReconcileDelay = time.Hour
+	defer func() { ReconcileDelay = delay }()
+
+	api := testApi(func(command string, params url.Values) string {
+		if command == CMD_PRIVATE_BUY {
+			return "<html>502 Bad Gateway</html>"
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := api.PlaceOrderIdempotentContext(ctx, SideBuy, "BTC_XMR", 0.0125, 1, nil, 3)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %s, want as soon as the context is done", elapsed)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Package polotest fakes the exchange for the tests of the packages built on
+poloniexapi: a client made by Api sends its requests to an Exchange instead of
+the network.
+
+	api := polotest.Api(polotest.Answers(map[string]string{
+		poloniexapi.CMD_PUBLIC_TICKER: `{"BTC_XMR":{"last":"0.0125"}}`,
+	}))
+*/
+package polotest
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Exchange answers the requests of a test client by command, with the
+// parameters of the URL query or of the POST form.
+type Exchange func(command string, params url.Values) string
+
+func (e Exchange) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := r.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+	if r.Method == "POST" {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		params = r.PostForm
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(e(params.Get("command"), params))),
+	}, nil
+}
+
+// Answers returns an Exchange answering each command with a fixed body, and
+// other commands with an error.
+func Answers(answers map[string]string) Exchange {
+	return func(command string, params url.Values) string {
+		if answer, ok := answers[command]; ok {
+			return answer
+		}
+		return `{"error":"unexpected command ` + command + `"}`
+	}
+}
+
+// Api returns a client without rate limiter nor validator talking to e.
+func Api(e Exchange) *poloniexapi.PoloniexApi {
+	api := poloniexapi.New("key", "secret")
+	api.Limiter = nil
+	api.Validator = nil
+	api.Client = &http.Client{Transport: e}
+
+	return api
+}
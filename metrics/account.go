@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+/*
+Account exports the state of an account as gauges, refreshed by Update:
+balances by currency from ApiPrivateCompleteBalances and the last price of
+every market from ApiPublicTicker, plus open orders and the margin account
+when enabled. Currencies and markets that disappear are dropped.
+*/
+type Account struct {
+	// Currency the total value of the account is exported in, through the
+	// best path of markets. Only the BTC value is exported when empty.
+	Quote string
+
+	OpenOrders bool // Export the number of open orders by market
+	Margin     bool // Export the margin account summary
+
+	api *poloniexapi.PoloniexApi
+
+	mu         sync.Mutex
+	balances   *prometheus.GaugeVec
+	btcValues  *prometheus.GaugeVec
+	total      *prometheus.GaugeVec
+	prices     *prometheus.GaugeVec
+	orders     *prometheus.GaugeVec
+	margin     *prometheus.GaugeVec
+	lastUpdate prometheus.Gauge
+	failures   prometheus.Counter
+}
+
+func NewAccount(api *poloniexapi.PoloniexApi) *Account {
+	return &Account{
+		api: api,
+		balances: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "balance",
+			Help:      "Exchange account balance, by currency and state: available or on_orders.",
+		}, []string{"currency", "state"}),
+		btcValues: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "balance_btc_value",
+			Help:      "Value in BTC of the balances of a currency, as estimated by the exchange.",
+		}, []string{"currency"}),
+		total: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "account_value",
+			Help:      "Total value of the balances, by quote currency.",
+		}, []string{"quote"}),
+		prices: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "ticker_last",
+			Help:      "Last trade price, by market.",
+		}, []string{"pair"}),
+		orders: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "open_orders",
+			Help:      "Number of open orders, by market.",
+		}, []string{"pair"}),
+		margin: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "margin_account",
+			Help:      "Margin account summary, by field: total_value, net_value, pl, lending_fees, total_borrowed_value, current_margin.",
+		}, []string{"field"}),
+		lastUpdate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "account_last_update_timestamp_seconds",
+			Help:      "Time of the last successful update of the account gauges.",
+		}),
+		failures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "account_update_errors_total",
+			Help:      "Failed updates of the account gauges.",
+		}),
+	}
+}
+
+/*
+Update fetches the account state and sets the gauges. The gauges are left
+unchanged when a request fails.
+*/
+func (a *Account) Update() error {
+	balances, err := a.api.ApiPrivateCompleteBalances(false)
+	if err != nil {
+		a.failures.Inc()
+		return err
+	}
+
+	tickers, err := a.api.ApiPublicTicker()
+	if err != nil {
+		a.failures.Inc()
+		return err
+	}
+
+	var open map[string][]poloniexapi.OpenOrder
+	if a.OpenOrders {
+		if open, err = a.api.ApiPrivateOpenOrders("all"); err != nil {
+			a.failures.Inc()
+			return err
+		}
+	}
+
+	var summary *poloniexapi.MarginAccountSummary
+	if a.Margin {
+		if summary, err = a.api.ApiPrivateMarginAccountSummary(); err != nil {
+			a.failures.Inc()
+			return err
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.balances.Reset()
+	a.btcValues.Reset()
+	a.total.Reset()
+
+	btc := 0.0
+	for currency, b := range balances {
+		if b.Available == 0 && b.OnOrders == 0 {
+			continue
+		}
+
+		a.balances.WithLabelValues(currency, "available").Set(b.Available)
+		a.balances.WithLabelValues(currency, "on_orders").Set(b.OnOrders)
+		a.btcValues.WithLabelValues(currency).Set(b.BtcValue)
+		btc += b.BtcValue
+	}
+
+	a.total.WithLabelValues("BTC").Set(btc)
+	if a.Quote != "" && a.Quote != "BTC" {
+		if rate, ok := poloniexapi.NewConversions(tickers).Rate("BTC", a.Quote); ok {
+			a.total.WithLabelValues(a.Quote).Set(btc * rate)
+		}
+	}
+
+	a.prices.Reset()
+	for pair, ticker := range tickers {
+		a.prices.WithLabelValues(pair).Set(ticker.Last)
+	}
+
+	if a.OpenOrders {
+		a.orders.Reset()
+		for pair, orders := range open {
+			if len(orders) > 0 {
+				a.orders.WithLabelValues(pair).Set(float64(len(orders)))
+			}
+		}
+	}
+
+	if a.Margin {
+		a.margin.WithLabelValues("total_value").Set(summary.TotalValue)
+		a.margin.WithLabelValues("net_value").Set(summary.NetValue)
+		a.margin.WithLabelValues("pl").Set(summary.PL)
+		a.margin.WithLabelValues("lending_fees").Set(summary.LendingFees)
+		a.margin.WithLabelValues("total_borrowed_value").Set(summary.TotalBorrowedValue)
+		a.margin.WithLabelValues("current_margin").Set(summary.CurrentMargin)
+	}
+
+	a.lastUpdate.SetToCurrentTime()
+
+	return nil
+}
+
+// Run calls Update right away then every interval, until ctx is done.
+func (a *Account) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Update(); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *Account) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range a.collectors() {
+		c.Describe(ch)
+	}
+}
+
+func (a *Account) Collect(ch chan<- prometheus.Metric) {
+	// Scrapes see the gauges of a single update.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, c := range a.collectors() {
+		c.Collect(ch)
+	}
+}
+
+func (a *Account) collectors() []prometheus.Collector {
+	return []prometheus.Collector{a.balances, a.btcValues, a.total, a.prices, a.orders, a.margin, a.lastUpdate, a.failures}
+}
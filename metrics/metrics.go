@@ -0,0 +1,124 @@
+/*
+Package metrics exports Prometheus metrics on a PoloniexApi client: requests,
+latencies and errors by command, rate limiter waits, retries and nonce errors,
+and optionally the state of the account (balances, open orders, margin).
+
+	client := metrics.NewClient()
+	api.Observer = client
+	prometheus.MustRegister(client)
+
+	account := metrics.NewAccount(api)
+	prometheus.MustRegister(account)
+	go account.Run(ctx, time.Minute, func(err error) { log.Print(err) })
+
+	http.Handle("/metrics", promhttp.Handler())
+*/
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "poloniex"
+
+/*
+Client records the requests of the PoloniexApi it observes. It implements both
+poloniexapi.Observer and prometheus.Collector; a single Client may observe
+several PoloniexApi.
+*/
+type Client struct {
+	requests  *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	waits     *prometheus.HistogramVec
+	retries   *prometheus.CounterVec
+	nonceErrs prometheus.Counter
+}
+
+func NewClient() *Client {
+	return &Client{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Requests sent to the exchange, by command.",
+		}, []string{"command"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_errors_total",
+			Help:      "Failed requests, by command and kind: api when refused by the exchange, request when no usable answer was received.",
+		}, []string{"command", "kind"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Time from sending a request to receiving its answer, by command.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+		}, []string{"command"}),
+		waits: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "ratelimiter_wait_seconds",
+			Help:      "Time requests waited for the rate limiter, by command.",
+			Buckets:   []float64{0, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"command"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Requests sent again after a failure, by command.",
+		}, []string{"command"}),
+		nonceErrs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "nonce_errors_total",
+			Help:      "Requests refused because of their nonce, e.g. when clients share a key.",
+		}),
+	}
+}
+
+func (c *Client) RequestDone(command string, duration time.Duration, err error) {
+	c.requests.WithLabelValues(command).Inc()
+	c.latency.WithLabelValues(command).Observe(duration.Seconds())
+
+	if err == nil {
+		return
+	}
+
+	var apiError *poloniexapi.ApiError
+	switch {
+	case errors.As(err, &apiError):
+		c.errors.WithLabelValues(command, "api").Inc()
+	case poloniexapi.IsAmbiguous(err):
+		c.errors.WithLabelValues(command, "request").Inc()
+	default:
+		c.errors.WithLabelValues(command, "other").Inc()
+	}
+
+	if poloniexapi.IsNonceError(err) {
+		c.nonceErrs.Inc()
+	}
+}
+
+func (c *Client) LimiterWaited(command string, wait time.Duration) {
+	c.waits.WithLabelValues(command).Observe(wait.Seconds())
+}
+
+func (c *Client) Retried(command string) {
+	c.retries.WithLabelValues(command).Inc()
+}
+
+func (c *Client) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range c.collectors() {
+		m.Describe(ch)
+	}
+}
+
+func (c *Client) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c.collectors() {
+		m.Collect(ch)
+	}
+}
+
+func (c *Client) collectors() []prometheus.Collector {
+	return []prometheus.Collector{c.requests, c.errors, c.latency, c.waits, c.retries, c.nonceErrs}
+}
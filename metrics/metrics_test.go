@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestClientErrors(t *testing.T) {
+	c := NewClient()
+
+	nonce := &poloniexapi.ApiError{Command: "returnBalances", Message: "Nonce must be greater than 1500000000000000. You provided 1400000000000000."}
+	lost := &poloniexapi.RequestError{Command: "buy", Err: errors.New("timeout")}
+
+	c.RequestDone("returnBalances", 100*time.Millisecond, nil)
+	c.RequestDone("returnBalances", 100*time.Millisecond, nonce)
+	c.RequestDone("buy", time.Second, lost)
+	c.Retried("buy")
+
+	if n := testutil.ToFloat64(c.requests.WithLabelValues("returnBalances")); n != 2 {
+		t.Errorf("expected 2 returnBalances requests, got %g", n)
+	}
+
+	if n := testutil.ToFloat64(c.errors.WithLabelValues("returnBalances", "api")); n != 1 {
+		t.Errorf("expected 1 api error, got %g", n)
+	}
+
+	if n := testutil.ToFloat64(c.errors.WithLabelValues("buy", "request")); n != 1 {
+		t.Errorf("expected 1 request error, got %g", n)
+	}
+
+	if n := testutil.ToFloat64(c.nonceErrs); n != 1 {
+		t.Errorf("expected 1 nonce error, got %g", n)
+	}
+
+	if n := testutil.ToFloat64(c.retries.WithLabelValues("buy")); n != 1 {
+		t.Errorf("expected 1 retry, got %g", n)
+	}
+
+	// Requests and latencies of 2 commands, 2 errors, a retry, nonce errors.
+	if n := testutil.CollectAndCount(c); n != 8 {
+		t.Errorf("expected 8 series, got %d", n)
+	}
+}
@@ -0,0 +1,33 @@
+package poloniexapi
+
+import "time"
+
+/*
+Observer is notified of the requests made by a PoloniexApi, e.g. to export
+metrics. Its methods are called synchronously from the requesting goroutine,
+possibly concurrently, and must return quickly.
+*/
+type Observer interface {
+	// RequestDone is called after each request sent to the exchange, with
+	// its duration and error, nil on success. Dry-run calls are not reported.
+	RequestDone(command string, duration time.Duration, err error)
+
+	// LimiterWaited is called with the time a request waited for the rate
+	// limiter, zero included.
+	LimiterWaited(command string, wait time.Duration)
+
+	// Retried is called when a request is sent again after a failure.
+	Retried(command string)
+}
+
+func (api *PoloniexApi) observeRequest(command string, start time.Time, err error) {
+	if api.Observer != nil {
+		api.Observer.RequestDone(command, time.Since(start), err)
+	}
+}
+
+func (api *PoloniexApi) observeRetry(command string) {
+	if api.Observer != nil {
+		api.Observer.Retried(command)
+	}
+}
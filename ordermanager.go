@@ -0,0 +1,758 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type OrderState int
+
+const (
+	OrderOpen OrderState = iota
+	OrderPartiallyFilled
+	OrderFilled
+	OrderCancelled
+	OrderCancelling // cancelled, its last trades may not be fetched yet
+)
+
+func (s OrderState) String() string {
+	switch s {
+	case OrderOpen:
+		return "open"
+	case OrderPartiallyFilled:
+		return "partially filled"
+	case OrderFilled:
+		return "filled"
+	case OrderCancelled:
+		return "cancelled"
+	case OrderCancelling:
+		return "cancelling"
+	}
+
+	return fmt.Sprintf("OrderState(%d)", int(s))
+}
+
+// Done tells if the order cannot change anymore.
+func (s OrderState) Done() bool {
+	return s == OrderFilled || s == OrderCancelled
+}
+
+// Working tells if the order is on the book.
+func (s OrderState) Working() bool {
+	return s == OrderOpen || s == OrderPartiallyFilled
+}
+
+var ErrUnknownOrder = errors.New("order is not tracked by this manager")
+
+/*
+ManagedOrder is the state of an order tracked by an OrderManager.
+
+ID is given by the manager and stays the same for the life of the order, while
+OrderNumber changes each time the order is moved. Amount is the total amount
+asked for, including what is already filled.
+
+Fees are in the currency received: the bought currency for buys, the base
+currency for sells.
+*/
+type ManagedOrder struct {
+	ID              int64
+	OrderNumber     int64
+	PreviousNumbers []int64
+	ClientOrderId   int64
+	Pair            string
+	Side            OrderSide
+	Rate            float64
+	Amount          float64
+	State           OrderState
+	Filled          float64
+	AveragePrice    float64
+	Fees            float64
+	Trades          []Trade
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	legs         []orderLeg      // one per order number, the current one last
+	unsettled    []settlingOrder // previous numbers whose last trades may not be fetched yet
+	missingSince time.Time       // when the order was first seen off the book
+	lastTradeAt  time.Time       // when trades were last added
+	cancelFilled float64         // filled amount reported by the cancel
+}
+
+// orderLeg is an order number and the amount it was placed or moved with, 0
+// when moved keeping the remaining amount.
+type orderLeg struct {
+	number int64
+	amount float64
+}
+
+// settlingOrder is a previous order number, and when it was moved or last
+// got trades.
+type settlingOrder struct {
+	number int64
+	since  time.Time
+}
+
+// Remaining is the amount still on the book.
+func (o *ManagedOrder) Remaining() float64 {
+	if !o.State.Working() {
+		return 0
+	}
+
+	return o.Amount - o.Filled
+}
+
+// OrderTransition is passed to the OnTransition callbacks when the state of an
+// order changes, or when it gets a new order number after a move (in which
+// case PreviousOrderNumber is set).
+type OrderTransition struct {
+	Order               ManagedOrder
+	From                OrderState
+	To                  OrderState
+	PreviousOrderNumber int64
+}
+
+/*
+OrderManager tracks orders from their placement to their completion or
+cancellation. Orders are placed, moved and cancelled through the manager, and
+their fills are picked up by Poll (or Run, which polls periodically) from the
+open orders and ApiPrivateOrderTrades.
+
+Trades show up in the history a little after the order leaves the book: an
+order that is gone without being filled is only marked cancelled once no
+trades showed for MissingGrace, so that its last trades are not lost. The same
+goes for the previous numbers of a moved order, and for cancelled orders, which
+stay OrderCancelling until their trades add up to the filled amount reported
+by the exchange, or until MissingGrace passed without new trades.
+*/
+type OrderManager struct {
+	MissingGrace time.Duration
+
+	api *PoloniexApi
+
+	mu        sync.Mutex
+	nextID    int64
+	orders    map[int64]*ManagedOrder
+	byNumber  map[int64]int64
+	callbacks []func(OrderTransition)
+}
+
+func NewOrderManager(api *PoloniexApi) *OrderManager {
+	return &OrderManager{
+		MissingGrace: 10 * time.Second,
+		api:          api,
+		orders:       make(map[int64]*ManagedOrder),
+		byNumber:     make(map[int64]int64),
+	}
+}
+
+// OnTransition registers a callback. Callbacks are called synchronously,
+// without the manager lock held, in the order of the transitions.
+func (m *OrderManager) OnTransition(fn func(OrderTransition)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callbacks = append(m.callbacks, fn)
+}
+
+// Place places a limit order and starts tracking it.
+func (m *OrderManager) Place(side OrderSide, currencyPair string, rate, amount float64, opts *OrderOptions) (ManagedOrder, error) {
+	return m.PlaceContext(context.Background(), side, currencyPair, rate, amount, opts)
+}
+
+// PlaceContext is Place with the context of the requests.
+func (m *OrderManager) PlaceContext(ctx context.Context, side OrderSide, currencyPair string, rate, amount float64, opts *OrderOptions) (ManagedOrder, error) {
+	order, err := m.api.PlaceOrderContext(ctx, side, currencyPair, rate, amount, opts)
+	if err != nil {
+		return ManagedOrder{}, err
+	}
+
+	clientOrderId := order.ClientOrderId
+	if clientOrderId == 0 && opts != nil {
+		clientOrderId = opts.ClientOrderID
+	}
+
+	now := time.Now()
+
+	m.mu.Lock()
+	m.nextID++
+	o := &ManagedOrder{
+		ID:            m.nextID,
+		OrderNumber:   order.OrderNumber,
+		ClientOrderId: clientOrderId,
+		Pair:          currencyPair,
+		Side:          side,
+		Rate:          rate,
+		Amount:        amount,
+		State:         OrderOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		legs:          []orderLeg{{number: order.OrderNumber, amount: amount}},
+	}
+	m.orders[o.ID] = o
+	m.byNumber[o.OrderNumber] = o.ID
+
+	// Immediate fills do not carry the order number.
+	trades := order.ResultingTrades[currencyPair]
+	for i := range trades {
+		trades[i].OrderNumber = order.OrderNumber
+	}
+	transitions := m.applyTrades(o, trades, false)
+	snapshot := o.snapshot()
+	m.mu.Unlock()
+
+	m.notify(transitions)
+
+	return snapshot, nil
+}
+
+// Track starts tracking an order placed elsewhere, e.g. found in the open
+// orders at startup.
+func (m *OrderManager) Track(currencyPair string, open OpenOrder) (ManagedOrder, error) {
+	orderNumber, err := strconv.ParseInt(open.OrderNumber, 10, 64)
+	if err != nil {
+		return ManagedOrder{}, err
+	}
+
+	now := time.Now()
+
+	m.mu.Lock()
+	if id, ok := m.byNumber[orderNumber]; ok {
+		snapshot := m.orders[id].snapshot()
+		m.mu.Unlock()
+		return snapshot, nil
+	}
+
+	m.nextID++
+	o := &ManagedOrder{
+		ID:            m.nextID,
+		OrderNumber:   orderNumber,
+		ClientOrderId: open.ClientOrderId,
+		Pair:          currencyPair,
+		Side:          OrderSide(open.Type),
+		Rate:          open.Rate,
+		Amount:        open.StartingAmount,
+		State:         OrderOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if o.Amount == 0 {
+		o.Amount = open.Amount
+	}
+	o.legs = []orderLeg{{number: orderNumber, amount: o.Amount}}
+	m.orders[o.ID] = o
+	m.byNumber[orderNumber] = o.ID
+	m.mu.Unlock()
+
+	// Pick up fills that happened before tracking started.
+	if o.Amount > open.Amount {
+		if err = m.refreshTrades(context.Background(), o.ID); err != nil {
+			return ManagedOrder{}, err
+		}
+	}
+
+	order, _ := m.Get(o.ID)
+	return order, nil
+}
+
+/*
+Move moves an order to a new rate. A zero amount keeps the remaining amount,
+otherwise amount is the new remaining amount.
+
+The previous order number may trade until the move: its trades are fetched
+right after, then by Poll until none showed for MissingGrace.
+*/
+func (m *OrderManager) Move(id int64, rate, amount float64, opts *OrderOptions) (ManagedOrder, error) {
+	return m.MoveContext(context.Background(), id, rate, amount, opts)
+}
+
+// MoveContext is Move with the context of the requests.
+func (m *OrderManager) MoveContext(ctx context.Context, id int64, rate, amount float64, opts *OrderOptions) (ManagedOrder, error) {
+	m.mu.Lock()
+	o, ok := m.orders[id]
+	if !ok {
+		m.mu.Unlock()
+		return ManagedOrder{}, ErrUnknownOrder
+	}
+	orderNumber := o.OrderNumber
+	pair := o.Pair
+	m.mu.Unlock()
+
+	order, err := m.api.ApiPrivateMoveOrderContext(ctx, orderNumber, rate, amount, opts)
+	if err != nil {
+		return ManagedOrder{}, err
+	}
+
+	m.mu.Lock()
+	from := o.State
+	o.PreviousNumbers = append(o.PreviousNumbers, o.OrderNumber)
+	o.OrderNumber = order.OrderNumber
+	o.Rate = rate
+	o.legs = append(o.legs, orderLeg{number: order.OrderNumber, amount: amount})
+	o.UpdatedAt = time.Now()
+	o.unsettled = append(o.unsettled, settlingOrder{number: orderNumber, since: o.UpdatedAt})
+	m.byNumber[o.OrderNumber] = o.ID
+
+	transitions := []OrderTransition{{Order: o.snapshot(), From: from, To: o.State, PreviousOrderNumber: orderNumber}}
+
+	trades := order.ResultingTrades[pair]
+	for i := range trades {
+		trades[i].OrderNumber = order.OrderNumber
+	}
+	transitions = append(transitions, m.applyTrades(o, trades, false)...)
+	m.mu.Unlock()
+
+	m.notify(transitions)
+
+	// The previous order number is off the book now.
+	if err = m.refreshTrades(ctx, id); err != nil {
+		return ManagedOrder{}, fmt.Errorf("Order %d moved to %d, could not fetch its last trades (%s)", orderNumber, order.OrderNumber, err.Error())
+	}
+
+	snapshot, _ := m.Get(id)
+
+	return snapshot, nil
+}
+
+/*
+Cancel cancels an order, and collects its last fills. The order is
+OrderCancelling until its trades add up to what the exchange reports as filled,
+then OrderCancelled (or OrderFilled); Poll finishes it after MissingGrace
+without new trades otherwise.
+*/
+func (m *OrderManager) Cancel(id int64) (ManagedOrder, error) {
+	return m.CancelContext(context.Background(), id)
+}
+
+// CancelContext is Cancel with the context of the requests.
+func (m *OrderManager) CancelContext(ctx context.Context, id int64) (ManagedOrder, error) {
+	m.mu.Lock()
+	o, ok := m.orders[id]
+	if !ok {
+		m.mu.Unlock()
+		return ManagedOrder{}, ErrUnknownOrder
+	}
+	orderNumber := o.OrderNumber
+	m.mu.Unlock()
+
+	_, cancelled, err := m.api.ApiPrivateCancelContext(ctx, orderNumber)
+	if err != nil {
+		return ManagedOrder{}, err
+	}
+
+	m.mu.Lock()
+	var transitions []OrderTransition
+	if o.State.Working() {
+		from := o.State
+		o.State = OrderCancelling
+		o.cancelFilled = o.Amount - cancelled.Amount
+		o.missingSince = time.Now()
+		o.UpdatedAt = o.missingSince
+		transitions = []OrderTransition{{Order: o.snapshot(), From: from, To: o.State}}
+	}
+	m.mu.Unlock()
+
+	m.notify(transitions)
+
+	if err = m.refreshTrades(ctx, id); err != nil {
+		return ManagedOrder{}, err
+	}
+
+	m.mu.Lock()
+	transitions = nil
+	if m.settled(o, time.Now()) {
+		transitions = m.finish(o)
+	}
+	snapshot := o.snapshot()
+	m.mu.Unlock()
+
+	m.notify(transitions)
+
+	return snapshot, nil
+}
+
+// Get returns a copy of the current state of an order.
+func (m *OrderManager) Get(id int64) (ManagedOrder, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.orders[id]
+	if !ok {
+		return ManagedOrder{}, false
+	}
+
+	return o.snapshot(), true
+}
+
+// GetByNumber finds an order by its current or any previous order number.
+func (m *OrderManager) GetByNumber(orderNumber int64) (ManagedOrder, bool) {
+	m.mu.Lock()
+	id, ok := m.byNumber[orderNumber]
+	m.mu.Unlock()
+
+	if !ok {
+		return ManagedOrder{}, false
+	}
+
+	return m.Get(id)
+}
+
+// Orders returns a copy of every tracked order.
+func (m *OrderManager) Orders() []ManagedOrder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]ManagedOrder, 0, len(m.orders))
+	for _, o := range m.orders {
+		out = append(out, o.snapshot())
+	}
+
+	return out
+}
+
+// Forget stops tracking orders that are done.
+func (m *OrderManager) Forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, o := range m.orders {
+		if !o.State.Done() {
+			continue
+		}
+
+		delete(m.orders, id)
+		delete(m.byNumber, o.OrderNumber)
+		for _, n := range o.PreviousNumbers {
+			delete(m.byNumber, n)
+		}
+	}
+}
+
+/*
+Poll synchronizes the tracked orders with the exchange: orders whose remaining
+amount went down, that were moved or cancelled, get their trades fetched, and
+orders that left the book are marked filled once their trades cover them, or
+cancelled after MissingGrace without new trades.
+*/
+func (m *OrderManager) Poll() error {
+	return m.PollContext(context.Background())
+}
+
+// PollContext is Poll with the context of the requests.
+func (m *OrderManager) PollContext(ctx context.Context) error {
+	open, err := m.api.ApiPrivateOpenOrdersContext(ctx, "all")
+	if err != nil {
+		return err
+	}
+
+	remaining := make(map[int64]float64)
+	for _, orders := range open {
+		for _, o := range orders {
+			orderNumber, err := strconv.ParseInt(o.OrderNumber, 10, 64)
+			if err != nil {
+				return err
+			}
+			remaining[orderNumber] = o.Amount
+		}
+	}
+
+	m.mu.Lock()
+	active := make([]*ManagedOrder, 0)
+	for _, o := range m.orders {
+		if !o.State.Done() {
+			active = append(active, o)
+		}
+	}
+	m.mu.Unlock()
+
+	now := time.Now()
+
+	for _, o := range active {
+		m.mu.Lock()
+		orderNumber, expected, unsettled := o.OrderNumber, o.Amount-o.Filled, len(o.unsettled)
+		m.mu.Unlock()
+
+		left, onBook := remaining[orderNumber]
+		onBook = onBook && o.State != OrderCancelling
+		if onBook && left >= expected-amountEpsilon && unsettled == 0 {
+			continue
+		}
+
+		if err = m.refreshTrades(ctx, o.ID); err != nil {
+			return err
+		}
+
+		m.mu.Lock()
+		var transitions []OrderTransition
+		switch {
+		case onBook || o.State.Done():
+			o.missingSince = time.Time{}
+		default:
+			if o.missingSince.IsZero() {
+				o.missingSince = now
+			}
+			if m.settled(o, now) {
+				transitions = m.finish(o)
+			}
+		}
+		m.mu.Unlock()
+
+		m.notify(transitions)
+	}
+
+	return nil
+}
+
+// Run calls Poll every interval until ctx is done. Poll errors are passed to
+// onError, which may be nil.
+func (m *OrderManager) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.PollContext(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+/*
+refreshTrades fetches the trades of the current order number, and of the
+previous ones not settled yet. Previous numbers are settled once no trades
+showed for MissingGrace after the move.
+*/
+func (m *OrderManager) refreshTrades(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	o, ok := m.orders[id]
+	if !ok {
+		m.mu.Unlock()
+		return ErrUnknownOrder
+	}
+	numbers := []int64{o.OrderNumber}
+	for _, u := range o.unsettled {
+		numbers = append(numbers, u.number)
+	}
+	m.mu.Unlock()
+
+	for _, orderNumber := range numbers {
+		trades, err := m.api.ApiPrivateOrderTradesContext(ctx, strconv.FormatInt(orderNumber, 10))
+		if err != nil {
+			// The exchange reports orders without trades as an error.
+			var apiError *ApiError
+			if !errors.As(err, &apiError) {
+				return err
+			}
+			trades = nil
+		}
+
+		for i := range trades {
+			trades[i].OrderNumber = orderNumber
+		}
+
+		m.mu.Lock()
+		transitions := m.applyTrades(o, trades, true)
+		o.unsettled = m.settle(o.unsettled, time.Now())
+		m.mu.Unlock()
+
+		m.notify(transitions)
+	}
+
+	return nil
+}
+
+// settle drops the previous numbers without trades for MissingGrace.
+func (m *OrderManager) settle(unsettled []settlingOrder, now time.Time) []settlingOrder {
+	out := unsettled[:0]
+	for _, u := range unsettled {
+		if now.Sub(u.since) < m.MissingGrace {
+			out = append(out, u)
+		}
+	}
+
+	return out
+}
+
+/*
+settled tells if an order off the book can be finished: its previous numbers
+are settled, and either its trades add up to the filled amount reported by
+the cancel, or no trades showed for MissingGrace. Must be called with the lock
+held.
+*/
+func (m *OrderManager) settled(o *ManagedOrder, now time.Time) bool {
+	if len(o.unsettled) != 0 {
+		return false
+	}
+
+	if o.State == OrderCancelling && o.Filled >= o.cancelFilled-amountEpsilon {
+		return true
+	}
+
+	quiet := o.missingSince
+	if o.lastTradeAt.After(quiet) {
+		quiet = o.lastTradeAt
+	}
+
+	return now.Sub(quiet) >= m.MissingGrace
+}
+
+/*
+amount returns the total amount of an order from its legs: the trades of the
+previous order numbers plus the amount of the current one. A leg moved
+keeping the remaining amount got what its predecessor did not fill.
+*/
+func (o *ManagedOrder) amount() float64 {
+	if len(o.legs) == 0 {
+		return o.Amount
+	}
+
+	filled := make(map[int64]float64)
+	for _, t := range o.Trades {
+		filled[t.OrderNumber] += t.Amount
+	}
+
+	var previous, legAmount float64
+	for i, leg := range o.legs {
+		if i > 0 {
+			previous += filled[o.legs[i-1].number]
+			if leg.amount == 0 {
+				legAmount -= filled[o.legs[i-1].number]
+				continue
+			}
+		}
+		legAmount = leg.amount
+	}
+
+	return previous + legAmount
+}
+
+// applyTrades adds the trades not seen yet and recomputes fills. Must be
+// called with the lock held.
+func (m *OrderManager) applyTrades(o *ManagedOrder, trades []Trade, fromHistory bool) []OrderTransition {
+	seen := make(map[int64]bool)
+	for _, t := range o.Trades {
+		seen[t.TradeID] = true
+	}
+
+	added := false
+	for _, t := range trades {
+		// Immediate fills from buy/sell are replaced by the same trades from
+		// the history, which have a fee.
+		if seen[t.TradeID] && !fromHistory {
+			continue
+		}
+		if seen[t.TradeID] {
+			for i := range o.Trades {
+				if o.Trades[i].TradeID == t.TradeID {
+					o.Trades[i] = t
+				}
+			}
+			continue
+		}
+
+		o.Trades = append(o.Trades, t)
+		seen[t.TradeID] = true
+		added = true
+
+		for i := range o.unsettled {
+			if o.unsettled[i].number == t.OrderNumber {
+				o.unsettled[i].since = time.Now()
+			}
+		}
+	}
+
+	var filled, total, fees float64
+	for _, t := range o.Trades {
+		filled += t.Amount
+		total += t.Total
+
+		if o.Side == SideBuy {
+			fees += t.Amount * t.Fee
+		} else {
+			fees += t.Total * t.Fee
+		}
+	}
+
+	o.Filled = filled
+	o.Fees = fees
+	if filled != 0 {
+		o.AveragePrice = total / filled
+	}
+	o.Amount = o.amount()
+
+	if !added {
+		return nil
+	}
+
+	o.UpdatedAt = time.Now()
+	o.lastTradeAt = o.UpdatedAt
+
+	from := o.State
+	switch {
+	case o.Filled >= o.Amount-amountEpsilon:
+		o.State = OrderFilled
+	case o.Filled > 0 && o.State.Working():
+		o.State = OrderPartiallyFilled
+	}
+
+	if from == o.State {
+		return nil
+	}
+
+	return []OrderTransition{{Order: o.snapshot(), From: from, To: o.State}}
+}
+
+// finish marks an order that left the book. Must be called with the lock
+// held.
+func (m *OrderManager) finish(o *ManagedOrder) []OrderTransition {
+	if o.State.Done() {
+		return nil
+	}
+
+	from := o.State
+	if o.Filled >= o.Amount-amountEpsilon {
+		o.State = OrderFilled
+	} else {
+		o.State = OrderCancelled
+	}
+	o.UpdatedAt = time.Now()
+
+	return []OrderTransition{{Order: o.snapshot(), From: from, To: o.State}}
+}
+
+func (m *OrderManager) notify(transitions []OrderTransition) {
+	if len(transitions) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	callbacks := append([]func(OrderTransition){}, m.callbacks...)
+	m.mu.Unlock()
+
+	for _, t := range transitions {
+		for _, fn := range callbacks {
+			fn(t)
+		}
+	}
+}
+
+// Amounts have 8 decimals on Poloniex.
+const amountEpsilon = 1e-9
+
+func (o *ManagedOrder) snapshot() ManagedOrder {
+	out := *o
+	out.PreviousNumbers = append([]int64(nil), o.PreviousNumbers...)
+	out.Trades = append([]Trade(nil), o.Trades...)
+	out.legs = append([]orderLeg(nil), o.legs...)
+	out.unsettled = append([]settlingOrder(nil), o.unsettled...)
+
+	return out
+}
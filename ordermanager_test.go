@@ -0,0 +1,247 @@
+package poloniexapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bookExchange keeps open orders and their trades, which the test changes
+// between calls.
+type bookExchange struct {
+	mu     sync.Mutex
+	open   map[string]float64 // remaining amount by order number
+	trades map[string][]string
+	onMove func()
+}
+
+func trade(id int, amount float64) string {
+	return fmt.Sprintf(`{"globalTradeID":%d,"tradeID":%d,"date":"2018-03-01 12:00:00","type":"buy","rate":"0.0125","amount":"%f","total":"%f","fee":"0.002"}`, id, id, amount, amount*0.0125)
+}
+
+func (e *bookExchange) answer(command string, params url.Values) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch command {
+	case CMD_PRIVATE_BUY:
+		e.open["10"] = 1
+		return `{"orderNumber":"10","resultingTrades":{}}`
+
+	case CMD_PRIVATE_MOVE_ORDER:
+		delete(e.open, params.Get("orderNumber"))
+		e.open["11"] = 0
+		if e.onMove != nil {
+			e.onMove()
+		}
+		return `{"success":1,"orderNumber":"11","resultingTrades":{}}`
+
+	case CMD_PRIVATE_CANCEL_ORDER:
+		number := params.Get("orderNumber")
+		amount := e.open[number]
+		delete(e.open, number)
+		return fmt.Sprintf(`{"success":1,"amount":"%f","message":"Order #%s canceled."}`, amount, number)
+
+	case CMD_PRIVATE_OPEN_ORDERS:
+		var orders []string
+		for number, amount := range e.open {
+			orders = append(orders, fmt.Sprintf(`{"orderNumber":%q,"type":"buy","rate":"0.0125","amount":"%f","total":"0","date":"2018-03-01 12:00:00","margin":0}`, number, amount))
+		}
+		return `{"BTC_XMR":[` + strings.Join(orders, ",") + `]}`
+
+	case CMD_PRIVATE_ORDER_TRADES:
+		trades, ok := e.trades[params.Get("orderNumber")]
+		if !ok {
+			return `{"error":"Order not found, or you are not the person who placed it."}`
+		}
+		return `[` + strings.Join(trades, ",") + `]`
+	}
+
+	return `{"error":"unexpected command"}`
+}
+
+func TestOrderManagerMove(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount float64 // of the move
+		want   float64 // total amount of the order
+	}{
+		{"new amount", 0.5, 0.8},
+		{"remaining amount", 0, 1},
+	}
+
+	for _, c := range cases {
+		e := &bookExchange{open: map[string]float64{}, trades: map[string][]string{}}
+		m := NewOrderManager(testApi(e.answer))
+
+		placed, err := m.Place(SideBuy, "BTC_XMR", 0.0125, 1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The old order fills a little right before being moved.
+		e.onMove = func() { e.trades["10"] = []string{trade(1, 0.3)} }
+
+		moved, err := m.Move(placed.ID, 0.013, c.amount, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if moved.OrderNumber != 11 || len(moved.PreviousNumbers) != 1 || moved.Filled != 0.3 || moved.Amount != c.want {
+			t.Errorf("%s: got %+v, want %v filled of %v", c.name, moved, 0.3, c.want)
+		}
+
+		// The new order fills the rest.
+		e.mu.Lock()
+		delete(e.open, "11")
+		e.trades["11"] = []string{trade(2, c.want-0.3)}
+		e.mu.Unlock()
+
+		if err = m.Poll(); err != nil {
+			t.Fatal(err)
+		}
+
+		if o, _ := m.Get(placed.ID); o.State != OrderFilled {
+			t.Errorf("%s: got %+v, want filled", c.name, o)
+		}
+	}
+}
+
+func TestOrderManagerPoll(t *testing.T) {
+	e := &bookExchange{open: map[string]float64{}, trades: map[string][]string{}}
+	m := NewOrderManager(testApi(e.answer))
+	m.MissingGrace = time.Hour
+
+	var transitions []OrderTransition
+	m.OnTransition(func(tr OrderTransition) { transitions = append(transitions, tr) })
+
+	placed, err := m.Place(SideBuy, "BTC_XMR", 0.0125, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Filled, but the trades do not show yet.
+	delete(e.open, "10")
+
+	if err = m.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, _ := m.Get(placed.ID); o.State != OrderOpen {
+		t.Errorf("got %s before the trades show, want open", o.State)
+	}
+
+	e.trades["10"] = []string{trade(1, 0.4), trade(2, 0.6)}
+
+	if err = m.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, _ := m.Get(placed.ID); o.State != OrderFilled || o.Filled != 1 {
+		t.Errorf("got %+v, want filled", o)
+	}
+
+	if len(transitions) != 1 || transitions[0].To != OrderFilled {
+		t.Errorf("got transitions %+v, want a single one to filled", transitions)
+	}
+
+	// Without trades after the grace period, the order was cancelled.
+	e.trades = map[string][]string{}
+	placed, err = m.Place(SideBuy, "BTC_XMR", 0.0125, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(e.open, "10")
+	m.MissingGrace = 0
+
+	if err = m.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, _ := m.Get(placed.ID); o.State != OrderCancelled {
+		t.Errorf("got %s, want cancelled", o.State)
+	}
+}
+
+func TestOrderManagerCancel(t *testing.T) {
+	e := &bookExchange{open: map[string]float64{}, trades: map[string][]string{}}
+	m := NewOrderManager(testApi(e.answer))
+	m.MissingGrace = time.Hour
+
+	placed, err := m.Place(SideBuy, "BTC_XMR", 0.0125, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0.4 filled right before the cancel, its trades do not show yet.
+	e.open["10"] = 0.6
+
+	cancelled, err := m.Cancel(placed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cancelled.State != OrderCancelling || cancelled.Remaining() != 0 {
+		t.Errorf("got %+v, want cancelling", cancelled)
+	}
+
+	if err = m.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, _ := m.Get(placed.ID); o.State != OrderCancelling {
+		t.Errorf("got %s before the trades show, want cancelling", o.State)
+	}
+
+	// The trades add up to the filled amount reported by the cancel.
+	e.trades["10"] = []string{trade(1, 0.4)}
+
+	if err = m.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, _ := m.Get(placed.ID); o.State != OrderCancelled || o.Filled != 0.4 {
+		t.Errorf("got %+v, want cancelled with 0.4 filled", o)
+	}
+}
+
+func TestOrderManagerMoveLateTrades(t *testing.T) {
+	e := &bookExchange{open: map[string]float64{}, trades: map[string][]string{}}
+	m := NewOrderManager(testApi(e.answer))
+	m.MissingGrace = time.Hour
+
+	placed, err := m.Place(SideBuy, "BTC_XMR", 0.0125, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = m.Move(placed.ID, 0.013, 0.5, nil); err != nil {
+		t.Fatal(err)
+	}
+	e.open["11"] = 0.5
+
+	// The trades of the previous number show after the move.
+	e.trades["10"] = []string{trade(1, 0.3)}
+
+	if err = m.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, _ := m.Get(placed.ID); o.Filled != 0.3 || o.Amount != 0.8 || len(o.unsettled) != 1 {
+		t.Errorf("got %+v, want 0.3 filled of 0.8 and the previous number unsettled", o)
+	}
+
+	// Settled once no trades showed for MissingGrace.
+	m.MissingGrace = 0
+
+	if err = m.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, _ := m.Get(placed.ID); len(o.unsettled) != 0 || o.State != OrderPartiallyFilled {
+		t.Errorf("got %+v, want the previous number settled", o)
+	}
+}
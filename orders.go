@@ -0,0 +1,165 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type OrderSide string
+
+const (
+	SideBuy  OrderSide = "buy"
+	SideSell OrderSide = "sell"
+)
+
+// TimeInForce says how long an order stays on the book.
+type TimeInForce int
+
+const (
+	TimeInForceGTC TimeInForce = iota // good till cancelled, the default
+	TimeInForceFOK                    // fill or kill
+	TimeInForceIOC                    // immediate or cancel
+)
+
+func (t TimeInForce) String() string {
+	switch t {
+	case TimeInForceGTC:
+		return "GTC"
+	case TimeInForceFOK:
+		return "FOK"
+	case TimeInForceIOC:
+		return "IOC"
+	}
+
+	return fmt.Sprintf("TimeInForce(%d)", int(t))
+}
+
+var ErrInvalidOrderOptions = errors.New("invalid order options")
+
+/*
+OrderOptions are the optional parameters of buy, sell and moveOrder. A nil
+*OrderOptions is valid and means a plain good-till-cancelled order.
+
+PostOnly only makes sense for orders resting on the book, so it cannot be
+combined with FOK or IOC. ClientOrderID, when not zero, is sent as
+"clientOrderId" and must be positive.
+*/
+type OrderOptions struct {
+	TimeInForce   TimeInForce
+	PostOnly      bool
+	ClientOrderID int64
+}
+
+func (o *OrderOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	switch o.TimeInForce {
+	case TimeInForceGTC, TimeInForceFOK, TimeInForceIOC:
+	default:
+		return fmt.Errorf("%w: unknown time in force %s", ErrInvalidOrderOptions, o.TimeInForce)
+	}
+
+	if o.PostOnly && o.TimeInForce != TimeInForceGTC {
+		return fmt.Errorf("%w: post-only cannot be combined with %s", ErrInvalidOrderOptions, o.TimeInForce)
+	}
+
+	if o.ClientOrderID < 0 {
+		return fmt.Errorf("%w: negative client order id %d", ErrInvalidOrderOptions, o.ClientOrderID)
+	}
+
+	return nil
+}
+
+// moveOrder accepts the same options as buy and sell, except fill-or-kill.
+func (o *OrderOptions) validateMove() error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	if o != nil && o.TimeInForce == TimeInForceFOK {
+		return fmt.Errorf("%w: moveOrder does not support %s", ErrInvalidOrderOptions, o.TimeInForce)
+	}
+
+	return nil
+}
+
+func (o *OrderOptions) apply(params url.Values) {
+	if o == nil {
+		return
+	}
+
+	switch o.TimeInForce {
+	case TimeInForceFOK:
+		params.Set("fillOrKill", "1")
+	case TimeInForceIOC:
+		params.Set("immediateOrCancel", "1")
+	}
+
+	if o.PostOnly {
+		params.Set("postOnly", "1")
+	}
+
+	if o.ClientOrderID != 0 {
+		params.Set("clientOrderId", strconv.FormatInt(o.ClientOrderID, 10))
+	}
+}
+
+/*
+PlaceOrder places a limit order on either side of a market. ApiPrivateBuy and
+ApiPrivateSell are shortcuts for it. Options, and the order itself when the
+api has a Validator, are validated before anything is sent.
+*/
+func (api *PoloniexApi) PlaceOrder(side OrderSide, currencyPair string, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	return api.PlaceOrderContext(context.Background(), side, currencyPair, rate, amount, opts)
+}
+
+// PlaceOrderContext is PlaceOrder with the context of the requests.
+func (api *PoloniexApi) PlaceOrderContext(ctx context.Context, side OrderSide, currencyPair string, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	var command string
+
+	switch side {
+	case SideBuy:
+		command = CMD_PRIVATE_BUY
+	case SideSell:
+		command = CMD_PRIVATE_SELL
+	default:
+		return nil, fmt.Errorf("Unknown order side %q", side)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := api.validateOrder(ctx, side, currencyPair, rate, amount); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("command", command)
+	params.Set("currencyPair", currencyPair)
+	params.Set("rate", strconv.FormatFloat(rate, 'f', -1, 64))
+	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+
+	opts.apply(params)
+
+	out := new(Order)
+
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	api.orderSent()
+
+	if trades, ok := out.ResultingTrades[""]; ok {
+		delete(out.ResultingTrades, "")
+		out.ResultingTrades[currencyPair] = trades
+	}
+
+	return out, nil
+}
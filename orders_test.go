@@ -0,0 +1,98 @@
+package poloniexapi
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestOrderOptionsValidate(t *testing.T) {
+	cases := []struct {
+		opts    *OrderOptions
+		invalid bool
+		move    bool // invalid for moveOrder
+	}{
+		{nil, false, false},
+		{&OrderOptions{}, false, false},
+		{&OrderOptions{PostOnly: true}, false, false},
+		{&OrderOptions{TimeInForce: TimeInForceFOK}, false, true},
+		{&OrderOptions{TimeInForce: TimeInForceIOC}, false, false},
+		{&OrderOptions{TimeInForce: TimeInForceFOK, PostOnly: true}, true, true},
+		{&OrderOptions{TimeInForce: TimeInForceIOC, PostOnly: true}, true, true},
+		{&OrderOptions{TimeInForce: TimeInForce(3)}, true, true},
+		{&OrderOptions{ClientOrderID: 42}, false, false},
+		{&OrderOptions{ClientOrderID: -1}, true, true},
+	}
+
+	for i, c := range cases {
+		if err := c.opts.Validate(); (err != nil) != c.invalid || err != nil && !errors.Is(err, ErrInvalidOrderOptions) {
+			t.Errorf("case %d: Validate of %+v returned %v", i, c.opts, err)
+		}
+
+		if err := c.opts.validateMove(); (err != nil) != c.move || err != nil && !errors.Is(err, ErrInvalidOrderOptions) {
+			t.Errorf("case %d: validateMove of %+v returned %v", i, c.opts, err)
+		}
+	}
+}
+
+func TestOrderOptionsApply(t *testing.T) {
+	cases := []struct {
+		opts   *OrderOptions
+		params url.Values
+	}{
+		{nil, url.Values{}},
+		{&OrderOptions{}, url.Values{}},
+		{&OrderOptions{TimeInForce: TimeInForceGTC, PostOnly: false}, url.Values{}},
+		{&OrderOptions{PostOnly: true}, url.Values{"postOnly": {"1"}}},
+		{&OrderOptions{TimeInForce: TimeInForceFOK}, url.Values{"fillOrKill": {"1"}}},
+		{&OrderOptions{TimeInForce: TimeInForceIOC}, url.Values{"immediateOrCancel": {"1"}}},
+		{&OrderOptions{ClientOrderID: 42}, url.Values{"clientOrderId": {"42"}}},
+		{
+			&OrderOptions{TimeInForce: TimeInForceIOC, ClientOrderID: 7},
+			url.Values{"immediateOrCancel": {"1"}, "clientOrderId": {"7"}},
+		},
+	}
+
+	for i, c := range cases {
+		params := url.Values{}
+		c.opts.apply(params)
+
+		if !reflect.DeepEqual(params, c.params) {
+			t.Errorf("case %d: %+v applied %v, want %v", i, c.opts, params, c.params)
+		}
+	}
+}
+
+func TestPlaceOrderOptions(t *testing.T) {
+	var sent url.Values
+	api := testApi(func(command string, params url.Values) string {
+		sent = params
+		return `{"orderNumber":"10"}`
+	})
+
+	// Invalid options are refused before anything is sent.
+	if _, err := api.PlaceOrder(SideBuy, "BTC_XMR", 0.01, 1, &OrderOptions{TimeInForce: TimeInForceFOK, PostOnly: true}); !errors.Is(err, ErrInvalidOrderOptions) || sent != nil {
+		t.Errorf("got %v and sent %v, want %v before sending", err, sent, ErrInvalidOrderOptions)
+	}
+
+	if _, err := api.ApiPrivateMoveOrder(10, 0.01, 0, &OrderOptions{TimeInForce: TimeInForceFOK}); !errors.Is(err, ErrInvalidOrderOptions) || sent != nil {
+		t.Errorf("got %v and sent %v, want %v before sending", err, sent, ErrInvalidOrderOptions)
+	}
+
+	if _, err := api.PlaceOrder(SideSell, "BTC_XMR", 0.01, 1, &OrderOptions{PostOnly: true, ClientOrderID: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"command": CMD_PRIVATE_SELL, "currencyPair": "BTC_XMR", "rate": "0.01", "amount": "1", "postOnly": "1", "clientOrderId": "5"}
+	for k, v := range want {
+		if sent.Get(k) != v {
+			t.Errorf("sent %s=%q, want %q", k, sent.Get(k), v)
+		}
+	}
+	for _, k := range []string{"fillOrKill", "immediateOrCancel"} {
+		if _, ok := sent[k]; ok {
+			t.Errorf("sent %s", k)
+		}
+	}
+}
@@ -0,0 +1,612 @@
+package poloniexapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Layout of the dates of trades.
+const TradeDateLayout = "2006-01-02 15:04:05"
+
+type CostMethod int
+
+const (
+	FIFO CostMethod = iota
+	LIFO
+	AverageCost
+)
+
+func (m CostMethod) String() string {
+	switch m {
+	case FIFO:
+		return "FIFO"
+	case LIFO:
+		return "LIFO"
+	case AverageCost:
+		return "average cost"
+	}
+
+	return fmt.Sprintf("CostMethod(%d)", int(m))
+}
+
+/*
+Lot is an amount of a currency acquired at once, and its Cost, fees included,
+in the Quote of the engine. Pair is the market it was bought on, empty for
+deposits. Lots from deposits, or bought with coins whose cost is unknown, have
+an unknown cost.
+*/
+type Lot struct {
+	Pair      string
+	Currency  string
+	Amount    float64
+	Cost      float64
+	CostKnown bool
+	Time      time.Time
+}
+
+/*
+Disposal is the sale of (part of) a lot, or its exchange for another currency,
+on Pair. Proceeds are net of the fee, and Realized is Proceeds minus Cost, both
+in the Quote of the engine. Sales of coins from deposits, or of more than the
+history holds, have an unknown cost: CostKnown is false, and Cost and Realized
+are zero. Carried disposals are exchanges that could not be valued, whose
+Proceeds are their Cost (see PnLEngine).
+*/
+type Disposal struct {
+	Time      time.Time
+	Acquired  time.Time
+	Pair      string
+	Currency  string
+	Amount    float64
+	Proceeds  float64
+	Cost      float64
+	CostKnown bool
+	Realized  float64
+	Carried   bool
+	TradeID   int64
+}
+
+// Transfer is a deposit (positive Amount) or a withdrawal (negative Amount).
+type Transfer struct {
+	Time     time.Time
+	Currency string
+	Amount   float64
+}
+
+type Period int
+
+const (
+	PeriodAll Period = iota
+	PeriodDay
+	PeriodMonth
+	PeriodYear
+)
+
+func (p Period) key(t time.Time) string {
+	switch p {
+	case PeriodDay:
+		return t.Format("2006-01-02")
+	case PeriodMonth:
+		return t.Format("2006-01")
+	case PeriodYear:
+		return t.Format("2006")
+	}
+
+	return ""
+}
+
+/*
+PnLRow sums the activity of a market during a period. Bought and Sold are in
+the quote currency of Pair, Fees in its base currency. Realized and
+UnknownCost, in the Quote of the engine, are for the disposals of both legs of
+the trades: Realized only counts those of known cost, UnknownCost holds the
+proceeds of the others.
+*/
+type PnLRow struct {
+	Pair        string
+	Period      string
+	Trades      int
+	Bought      float64
+	Sold        float64
+	Fees        float64
+	Realized    float64
+	UnknownCost float64
+}
+
+/*
+Position is what is left of the lots of a currency, marked to the last prices
+of the tickers, in the Quote of the engine. UnknownAmount is the part whose
+cost is unknown: Cost, AverageCost and Unrealized are for the rest.
+*/
+type Position struct {
+	Currency      string
+	Amount        float64
+	UnknownAmount float64
+	Cost          float64
+	AverageCost   float64
+	Last          float64
+	Value         float64
+	Unrealized    float64
+}
+
+type TransferRow struct {
+	Currency  string
+	Period    string
+	Deposited float64
+	Withdrawn float64
+	Count     int
+}
+
+type PnLReport struct {
+	Method    CostMethod
+	Rows      []PnLRow
+	Positions []Position
+	Transfers []TransferRow
+	Disposals []Disposal
+}
+
+type pnlEvent struct {
+	time     time.Time
+	pair     string
+	trade    *Trade
+	transfer *Transfer
+}
+
+/*
+PnLEngine computes cost basis and profit and loss from the trade history, in
+a single currency, Quote, e.g. "USDT" or "BTC".
+
+Lots are kept by currency, whatever the market they were bought on, and every
+trade has two legs: buying ETH on BTC_ETH disposes of the BTC spent and opens
+a lot of ETH, selling it on USDT_ETH disposes of that lot. Quote itself is
+the unit of account: spending it is not a disposal, and it has no lots.
+
+Trades are valued in Quote from their own amounts when one of their
+currencies is Quote, or else from Prices, the price of a currency in Quote at
+a time. Trades that cannot be valued carry the cost of the coins spent over to
+the coins received, without realizing anything (see Disposal.Carried).
+
+Fees are included: a buy costs its total and yields its amount minus the fee,
+a sell yields its total minus the fee. Deposits and withdrawals are transfers,
+not trades: deposits add lots of unknown cost, used by disposals once the lots
+of known cost are exhausted, and withdrawals remove lots without realizing
+anything. Margin and lending trades are ignored.
+
+Events may be added in any order; they are replayed by date in Report.
+*/
+type PnLEngine struct {
+	Method CostMethod
+	Quote  string
+	Prices PriceFunc // optional
+
+	events []pnlEvent
+}
+
+// PriceFunc returns the price of a currency at a time, in some quote currency,
+// and false when it is not known.
+type PriceFunc func(currency string, at time.Time) (float64, bool)
+
+func NewPnLEngine(method CostMethod, quote string) *PnLEngine {
+	return &PnLEngine{Method: method, Quote: quote}
+}
+
+// AddTrades adds trades of a market, as returned by ApiPrivateTradeHistory.
+func (e *PnLEngine) AddTrades(pair string, trades []Trade) error {
+	for i := range trades {
+		t := trades[i]
+		if t.Category != "" && t.Category != "exchange" {
+			continue
+		}
+
+		date, err := time.Parse(TradeDateLayout, t.Date)
+		if err != nil {
+			return fmt.Errorf("Could not parse date of trade %d (%s)", t.TradeID, err.Error())
+		}
+
+		e.events = append(e.events, pnlEvent{time: date, pair: pair, trade: &t})
+	}
+
+	return nil
+}
+
+// AddTransfers adds deposits and withdrawals, as returned by
+// ApiPrivateDepositWithdrawals. Only completed ones are counted.
+func (e *PnLEngine) AddTransfers(transfers *DepositWithdrawal) {
+	for _, d := range transfers.Deposits {
+		if d.Status != "COMPLETE" {
+			continue
+		}
+		e.events = append(e.events, pnlEvent{
+			time:     time.Unix(d.Timestamp, 0).UTC(),
+			transfer: &Transfer{Time: time.Unix(d.Timestamp, 0).UTC(), Currency: d.Currency, Amount: d.Amount},
+		})
+	}
+
+	for _, w := range transfers.Withdrawals {
+		// e.g. "COMPLETE: <txid>"
+		if !strings.HasPrefix(w.Status, "COMPLETE") {
+			continue
+		}
+		e.events = append(e.events, pnlEvent{
+			time:     time.Unix(w.Timestamp, 0).UTC(),
+			transfer: &Transfer{Time: time.Unix(w.Timestamp, 0).UTC(), Currency: w.Currency, Amount: -w.Amount},
+		})
+	}
+}
+
+/*
+LoadPnL fetches the trades of all markets and the deposits and withdrawals
+between start and end, and returns an engine with them.
+*/
+func (api *PoloniexApi) LoadPnL(method CostMethod, quote string, start, end time.Time) (*PnLEngine, error) {
+	history, err := api.LoadTradeHistory("all", start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := api.ApiPrivateDepositWithdrawals(start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewPnLEngine(method, quote)
+	for pair, trades := range history {
+		if err = e.AddTrades(pair, trades); err != nil {
+			return nil, err
+		}
+	}
+	e.AddTransfers(transfers)
+
+	return e, nil
+}
+
+/*
+Report replays the events and groups them by market and period. Open
+positions are marked to the Last price of tickers, through the best path of
+markets to Quote (see Conversions); tickers may be nil to skip unrealized P&L.
+*/
+func (e *PnLEngine) Report(tickers map[string]Ticker, period Period) PnLReport {
+	events := append([]pnlEvent(nil), e.events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].time.Equal(events[j].time) {
+			return events[i].time.Before(events[j].time)
+		}
+		// Trades of the same second in the order they were made.
+		if events[i].trade != nil && events[j].trade != nil {
+			return events[i].trade.TradeID < events[j].trade.TradeID
+		}
+		return false
+	})
+
+	b := &lotBook{method: e.Method, lots: make(map[string][]*Lot), unknown: make(map[string][]*Lot)}
+	rows := make(map[[2]string]*PnLRow)
+	transfers := make(map[[2]string]*TransferRow)
+	report := PnLReport{Method: e.Method}
+
+	for _, ev := range events {
+		if ev.transfer != nil {
+			t := ev.transfer
+			key := [2]string{t.Currency, period.key(ev.time)}
+			row := transfers[key]
+			if row == nil {
+				row = &TransferRow{Currency: key[0], Period: key[1]}
+				transfers[key] = row
+			}
+			row.Count++
+
+			if t.Amount > 0 {
+				row.Deposited += t.Amount
+				// Quote has no lots.
+				if t.Currency != e.Quote {
+					b.add(&Lot{Currency: t.Currency, Amount: t.Amount, Time: ev.time})
+				}
+			} else {
+				row.Withdrawn -= t.Amount
+				b.withdraw(t.Currency, -t.Amount)
+			}
+			continue
+		}
+
+		t := ev.trade
+		base, quote, ok := splitPair(ev.pair)
+		if !ok {
+			continue
+		}
+
+		key := [2]string{ev.pair, period.key(ev.time)}
+		row := rows[key]
+		if row == nil {
+			row = &PnLRow{Pair: key[0], Period: key[1]}
+			rows[key] = row
+		}
+		row.Trades++
+		row.Fees += t.Total * t.Fee
+
+		// A buy spends base for quote, valued at the base spent, a sell the
+		// other way around, valued at the base received.
+		spent, spentAmount, got, gotAmount := base, t.Total, quote, t.Amount*(1-t.Fee)
+		valuedAmount := t.Total
+		if t.Type == "buy" {
+			row.Bought += t.Amount
+		} else {
+			row.Sold += t.Amount
+			spent, spentAmount, got, gotAmount = quote, t.Amount, base, t.Total*(1-t.Fee)
+			valuedAmount = gotAmount
+		}
+
+		value, valued := e.value(base, quote, t, valuedAmount, ev.time)
+
+		var disposals []Disposal
+		if spent != e.Quote {
+			disposals = b.dispose(ev.pair, spent, spentAmount, value, !valued, ev.time)
+		}
+
+		for _, d := range disposals {
+			d.TradeID = t.TradeID
+			if d.CostKnown {
+				row.Realized += d.Realized
+			} else {
+				row.UnknownCost += d.Proceeds
+			}
+			report.Disposals = append(report.Disposals, d)
+		}
+
+		if got == e.Quote {
+			continue
+		}
+
+		if valued {
+			b.add(&Lot{Pair: ev.pair, Currency: got, Amount: gotAmount, Cost: value, CostKnown: true, Time: ev.time})
+			continue
+		}
+
+		// Carried over: the coins received cost what the coins spent did, and
+		// those received for coins of unknown cost have an unknown cost too.
+		var cost, known float64
+		for _, d := range disposals {
+			if d.CostKnown {
+				cost += d.Cost
+				known += d.Amount
+			}
+		}
+
+		knownAmount := gotAmount * known / spentAmount
+		if knownAmount > amountEpsilon {
+			b.add(&Lot{Pair: ev.pair, Currency: got, Amount: knownAmount, Cost: cost, CostKnown: true, Time: ev.time})
+		}
+		if gotAmount-knownAmount > amountEpsilon {
+			b.add(&Lot{Pair: ev.pair, Currency: got, Amount: gotAmount - knownAmount, Time: ev.time})
+		}
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].Pair != report.Rows[j].Pair {
+			return report.Rows[i].Pair < report.Rows[j].Pair
+		}
+		return report.Rows[i].Period < report.Rows[j].Period
+	})
+
+	for _, row := range transfers {
+		report.Transfers = append(report.Transfers, *row)
+	}
+	sort.Slice(report.Transfers, func(i, j int) bool {
+		if report.Transfers[i].Currency != report.Transfers[j].Currency {
+			return report.Transfers[i].Currency < report.Transfers[j].Currency
+		}
+		return report.Transfers[i].Period < report.Transfers[j].Period
+	})
+
+	report.Positions = b.positions(marks(tickers, e.Quote))
+
+	return report
+}
+
+/*
+value returns the value in Quote of an amount of the base currency of a
+trade: as is when base is Quote, at the rate of the trade when its quote is,
+and from Prices otherwise.
+*/
+func (e *PnLEngine) value(base, quote string, t *Trade, amount float64, at time.Time) (float64, bool) {
+	if base == e.Quote {
+		return amount, true
+	}
+
+	if t.Total == 0 {
+		return 0, false
+	}
+
+	// The amount of quote currency it traded for.
+	quoteAmount := amount * t.Amount / t.Total
+
+	if quote == e.Quote {
+		return quoteAmount, true
+	}
+
+	if e.Prices == nil {
+		return 0, false
+	}
+
+	if price, ok := e.Prices(base, at); ok {
+		return amount * price, true
+	}
+
+	if price, ok := e.Prices(quote, at); ok {
+		return quoteAmount * price, true
+	}
+
+	return 0, false
+}
+
+// marks returns the last price in quote of every currency with a path of
+// markets to it.
+func marks(tickers map[string]Ticker, quote string) map[string]float64 {
+	last := make(map[string]Ticker, len(tickers))
+	for pair, ticker := range tickers {
+		last[pair] = Ticker{HighestBid: ticker.Last, LowestAsk: ticker.Last, IsFrozen: ticker.IsFrozen}
+	}
+
+	return NewConversions(last).Rates(quote)
+}
+
+/*
+lotBook holds the open lots by currency: those of known cost, and those of
+unknown cost (deposits, or coins bought with coins of unknown cost), used once
+the first are exhausted.
+*/
+type lotBook struct {
+	method  CostMethod
+	lots    map[string][]*Lot
+	unknown map[string][]*Lot
+}
+
+func (b *lotBook) add(lot *Lot) {
+	if !lot.CostKnown {
+		b.unknown[lot.Currency] = append(b.unknown[lot.Currency], lot)
+		return
+	}
+
+	if b.method == AverageCost && len(b.lots[lot.Currency]) != 0 {
+		merged := b.lots[lot.Currency][0]
+		merged.Amount += lot.Amount
+		merged.Cost += lot.Cost
+		return
+	}
+
+	b.lots[lot.Currency] = append(b.lots[lot.Currency], lot)
+}
+
+// take removes amount from the lots of currency in book, and returns the
+// parts taken and what is left to take.
+func (b *lotBook) take(book map[string][]*Lot, currency string, amount float64) ([]Lot, float64) {
+	taken := make([]Lot, 0)
+
+	for amount > amountEpsilon && len(book[currency]) != 0 {
+		lots := book[currency]
+
+		i := 0
+		if b.method == LIFO {
+			i = len(lots) - 1
+		}
+		lot := lots[i]
+
+		part := *lot
+		if lot.Amount > amount {
+			part.Amount = amount
+			part.Cost = lot.Cost * amount / lot.Amount
+			lot.Cost -= part.Cost
+			lot.Amount -= amount
+		} else {
+			book[currency] = append(lots[:i], lots[i+1:]...)
+		}
+
+		amount -= part.Amount
+		taken = append(taken, part)
+	}
+
+	return taken, amount
+}
+
+/*
+dispose takes amount of currency from the lots, for proceeds. Carried
+disposals get their cost as proceeds.
+*/
+func (b *lotBook) dispose(pair, currency string, amount, proceeds float64, carried bool, at time.Time) []Disposal {
+	taken, left := b.take(b.lots, currency, amount)
+
+	unknown, left := b.take(b.unknown, currency, left)
+	taken = append(taken, unknown...)
+
+	// Disposed of more than known: coins from before the history.
+	if left > amountEpsilon {
+		taken = append(taken, Lot{Pair: pair, Currency: currency, Amount: left, Time: at})
+	}
+
+	out := make([]Disposal, 0, len(taken))
+	for _, lot := range taken {
+		d := Disposal{
+			Time:      at,
+			Acquired:  lot.Time,
+			Pair:      pair,
+			Currency:  currency,
+			Amount:    lot.Amount,
+			Proceeds:  proceeds * lot.Amount / amount,
+			Cost:      lot.Cost,
+			CostKnown: lot.CostKnown,
+			Carried:   carried,
+		}
+		if carried {
+			d.Proceeds = d.Cost
+		}
+		if d.CostKnown {
+			d.Realized = d.Proceeds - d.Cost
+		}
+		out = append(out, d)
+	}
+
+	return out
+}
+
+// withdraw removes lots of currency, of unknown cost first.
+func (b *lotBook) withdraw(currency string, amount float64) {
+	_, amount = b.take(b.unknown, currency, amount)
+	b.take(b.lots, currency, amount)
+}
+
+func (b *lotBook) positions(marks map[string]float64) []Position {
+	byCurrency := make(map[string]*Position)
+	position := func(currency string) *Position {
+		p := byCurrency[currency]
+		if p == nil {
+			p = &Position{Currency: currency}
+			byCurrency[currency] = p
+		}
+		return p
+	}
+
+	for currency, lots := range b.lots {
+		for _, lot := range lots {
+			p := position(currency)
+			p.Amount += lot.Amount
+			p.Cost += lot.Cost
+		}
+	}
+
+	for currency, lots := range b.unknown {
+		for _, lot := range lots {
+			p := position(currency)
+			p.Amount += lot.Amount
+			p.UnknownAmount += lot.Amount
+		}
+	}
+
+	out := make([]Position, 0, len(byCurrency))
+	for _, p := range byCurrency {
+		if p.Amount < amountEpsilon {
+			continue
+		}
+
+		known := p.Amount - p.UnknownAmount
+		if known > amountEpsilon {
+			p.AverageCost = p.Cost / known
+		}
+
+		if last, ok := marks[p.Currency]; ok {
+			p.Last = last
+			p.Value = p.Amount * last
+			if known > amountEpsilon {
+				p.Unrealized = known*last - p.Cost
+			}
+		}
+
+		out = append(out, *p)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Currency < out[j].Currency })
+
+	return out
+}
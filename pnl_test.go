@@ -0,0 +1,258 @@
+package poloniexapi
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPnLCostMethods(t *testing.T) {
+	// Two buys of 1 XMR at 0.01 then 0.02 BTC, then a sale of 1 XMR at 0.03.
+	trades := []Trade{
+		{TradeID: 1, Date: "2018-03-01 12:00:00", Type: "buy", Amount: 1, Total: 0.01},
+		{TradeID: 2, Date: "2018-03-02 12:00:00", Type: "buy", Amount: 1, Total: 0.02},
+		{TradeID: 3, Date: "2018-03-03 12:00:00", Type: "sell", Amount: 1, Total: 0.03},
+	}
+
+	cases := []struct {
+		method     CostMethod
+		realized   float64
+		cost       float64 // of the position left
+		unrealized float64 // at 0.04
+	}{
+		{FIFO, 0.02, 0.02, 0.02},
+		{LIFO, 0.01, 0.01, 0.03},
+		{AverageCost, 0.015, 0.015, 0.025},
+	}
+
+	for _, c := range cases {
+		e := NewPnLEngine(c.method, "BTC")
+		if err := e.AddTrades("BTC_XMR", trades); err != nil {
+			t.Fatal(err)
+		}
+
+		report := e.Report(map[string]Ticker{"BTC_XMR": {Last: 0.04}}, PeriodAll)
+
+		if len(report.Rows) != 1 || !near(report.Rows[0].Realized, c.realized) || report.Rows[0].UnknownCost != 0 {
+			t.Errorf("%s: got rows %+v, want %g realized", c.method, report.Rows, c.realized)
+		}
+
+		if len(report.Positions) != 1 || !near(report.Positions[0].Amount, 1) || !near(report.Positions[0].Cost, c.cost) || !near(report.Positions[0].Unrealized, c.unrealized) {
+			t.Errorf("%s: got positions %+v, want a cost of %g and %g unrealized", c.method, report.Positions, c.cost, c.unrealized)
+		}
+	}
+}
+
+func TestPnLFees(t *testing.T) {
+	e := NewPnLEngine(FIFO, "BTC")
+	err := e.AddTrades("BTC_XMR", []Trade{
+		{TradeID: 1, Date: "2018-03-01 12:00:00", Type: "buy", Amount: 1, Total: 0.01, Fee: 0.002},
+		{TradeID: 2, Date: "2018-03-02 12:00:00", Type: "sell", Amount: 0.998, Total: 0.02, Fee: 0.002},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := e.Report(nil, PeriodAll)
+
+	// Proceeds 0.02*0.998, cost 0.01 for the whole 0.998 received.
+	if row := report.Rows[0]; !near(row.Realized, 0.01996-0.01) || !near(row.Fees, 0.00006) {
+		t.Errorf("got %+v", row)
+	}
+
+	if len(report.Positions) != 0 {
+		t.Errorf("got positions %+v, want none", report.Positions)
+	}
+}
+
+func TestPnLUnknownCost(t *testing.T) {
+	cases := []struct {
+		name      string
+		deposit   float64
+		trades    []Trade
+		realized  float64
+		unknown   float64
+		disposals []bool // CostKnown of each
+	}{
+		{
+			name: "oversold",
+			trades: []Trade{
+				{TradeID: 1, Date: "2018-03-01 12:00:00", Type: "buy", Amount: 1, Total: 0.01},
+				{TradeID: 2, Date: "2018-03-02 12:00:00", Type: "sell", Amount: 2, Total: 0.04},
+			},
+			realized:  0.01,
+			unknown:   0.02,
+			disposals: []bool{true, false},
+		},
+		{
+			name:    "deposited",
+			deposit: 3,
+			trades: []Trade{
+				{TradeID: 1, Date: "2018-03-02 12:00:00", Type: "sell", Amount: 2, Total: 0.04},
+			},
+			unknown:   0.04,
+			disposals: []bool{false},
+		},
+		{
+			name:    "bought then deposited",
+			deposit: 1,
+			trades: []Trade{
+				{TradeID: 1, Date: "2018-03-01 12:00:00", Type: "buy", Amount: 1, Total: 0.01},
+				{TradeID: 2, Date: "2018-03-03 12:00:00", Type: "sell", Amount: 2, Total: 0.06},
+			},
+			realized:  0.02,
+			unknown:   0.03,
+			disposals: []bool{true, false},
+		},
+	}
+
+	for _, c := range cases {
+		e := NewPnLEngine(FIFO, "BTC")
+		if err := e.AddTrades("BTC_XMR", c.trades); err != nil {
+			t.Fatal(err)
+		}
+		if c.deposit != 0 {
+			e.AddTransfers(&DepositWithdrawal{Deposits: []Deposit{{
+				Currency:  "XMR",
+				Amount:    c.deposit,
+				Timestamp: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC).Unix(),
+				Status:    "COMPLETE",
+			}}})
+		}
+
+		report := e.Report(nil, PeriodAll)
+
+		row := report.Rows[0]
+		if !near(row.Realized, c.realized) || !near(row.UnknownCost, c.unknown) {
+			t.Errorf("%s: got %g realized and %g of unknown cost, want %g and %g", c.name, row.Realized, row.UnknownCost, c.realized, c.unknown)
+		}
+
+		if len(report.Disposals) != len(c.disposals) {
+			t.Fatalf("%s: got disposals %+v", c.name, report.Disposals)
+		}
+		for i, d := range report.Disposals {
+			if d.CostKnown != c.disposals[i] || (!d.CostKnown && (d.Cost != 0 || d.Realized != 0)) {
+				t.Errorf("%s: got disposal %+v", c.name, d)
+			}
+		}
+	}
+}
+
+func TestPnLWithdrawal(t *testing.T) {
+	e := NewPnLEngine(FIFO, "BTC")
+	err := e.AddTrades("BTC_XMR", []Trade{
+		{TradeID: 1, Date: "2018-03-01 12:00:00", Type: "buy", Amount: 1, Total: 0.01},
+		{TradeID: 2, Date: "2018-03-02 12:00:00", Type: "buy", Amount: 1, Total: 0.02},
+		{TradeID: 3, Date: "2018-03-04 12:00:00", Type: "sell", Amount: 1, Total: 0.03},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.AddTransfers(&DepositWithdrawal{Withdrawals: []Withdrawal{{
+		Currency:  "XMR",
+		Amount:    1,
+		Timestamp: time.Date(2018, 3, 3, 0, 0, 0, 0, time.UTC).Unix(),
+		Status:    "COMPLETE: 1234",
+	}}})
+
+	report := e.Report(nil, PeriodAll)
+
+	// The oldest lot is withdrawn, the sale takes the second.
+	if row := report.Rows[0]; !near(row.Realized, 0.01) || row.UnknownCost != 0 {
+		t.Errorf("got %+v", row)
+	}
+
+	if len(report.Transfers) != 1 || report.Transfers[0].Withdrawn != 1 {
+		t.Errorf("got transfers %+v", report.Transfers)
+	}
+}
+
+func TestPnLAcrossMarkets(t *testing.T) {
+	// BTC bought with USDT is spent on ETH, which is sold for USDT.
+	trades := map[string][]Trade{
+		"USDT_BTC": {{TradeID: 1, Date: "2018-03-01 12:00:00", Type: "buy", Amount: 1, Total: 8000}},
+		"BTC_ETH":  {{TradeID: 2, Date: "2018-03-02 12:00:00", Type: "buy", Amount: 10, Total: 0.5}},
+		"USDT_ETH": {{TradeID: 3, Date: "2018-03-03 12:00:00", Type: "sell", Amount: 10, Total: 6000}},
+	}
+
+	cases := []struct {
+		name     string
+		prices   func(string, time.Time) (float64, bool)
+		realized map[string]float64 // by market
+		carried  bool
+	}{
+		{
+			name:     "valued",
+			prices:   func(currency string, at time.Time) (float64, bool) { return 10000, currency == "BTC" },
+			realized: map[string]float64{"USDT_BTC": 0, "BTC_ETH": 1000, "USDT_ETH": 1000},
+		},
+		{
+			name:     "carried",
+			realized: map[string]float64{"USDT_BTC": 0, "BTC_ETH": 0, "USDT_ETH": 2000},
+			carried:  true,
+		},
+	}
+
+	for _, c := range cases {
+		e := NewPnLEngine(FIFO, "USDT")
+		e.Prices = c.prices
+		for pair, trades := range trades {
+			if err := e.AddTrades(pair, trades); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		report := e.Report(map[string]Ticker{"USDT_BTC": {Last: 9000}}, PeriodAll)
+
+		for _, row := range report.Rows {
+			if !near(row.Realized, c.realized[row.Pair]) || row.UnknownCost != 0 {
+				t.Errorf("%s: got row %+v, want %g realized", c.name, row, c.realized[row.Pair])
+			}
+		}
+
+		// Spending BTC is a disposal of it.
+		if len(report.Disposals) != 2 {
+			t.Fatalf("%s: got disposals %+v", c.name, report.Disposals)
+		}
+		if d := report.Disposals[0]; d.Currency != "BTC" || d.Pair != "BTC_ETH" || !near(d.Amount, 0.5) || !near(d.Cost, 4000) || d.Carried != c.carried {
+			t.Errorf("%s: got disposal %+v, want 0.5 BTC costing 4000", c.name, d)
+		}
+		if d := report.Disposals[1]; d.Currency != "ETH" || !near(d.Proceeds, 6000) || !d.CostKnown {
+			t.Errorf("%s: got disposal %+v, want ETH sold for 6000", c.name, d)
+		}
+
+		if len(report.Positions) != 1 || report.Positions[0].Currency != "BTC" || !near(report.Positions[0].Cost, 4000) || !near(report.Positions[0].Unrealized, 500) {
+			t.Errorf("%s: got positions %+v, want 0.5 BTC costing 4000", c.name, report.Positions)
+		}
+	}
+}
+
+func TestPnLUnknownCostCarried(t *testing.T) {
+	// Deposited BTC, of unknown cost, spent on ETH without a price.
+	e := NewPnLEngine(FIFO, "USDT")
+	e.AddTransfers(&DepositWithdrawal{Deposits: []Deposit{{
+		Currency:  "BTC",
+		Amount:    1,
+		Timestamp: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Status:    "COMPLETE",
+	}}})
+	err := e.AddTrades("BTC_ETH", []Trade{{TradeID: 1, Date: "2018-03-02 12:00:00", Type: "buy", Amount: 10, Total: 0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := e.Report(nil, PeriodAll)
+
+	if len(report.Positions) != 2 {
+		t.Fatalf("got positions %+v", report.Positions)
+	}
+	for _, p := range report.Positions {
+		if !near(p.UnknownAmount, p.Amount) || p.Cost != 0 {
+			t.Errorf("got position %+v, want an unknown cost", p)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
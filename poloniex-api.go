@@ -1,10 +1,13 @@
 package poloniexapi
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -36,32 +39,64 @@ const (
 	// Margin
 	CMD_PRIVATE_TRADABLE_BALANCES      = "returnTradableBalances"
 	CMD_PRIVATE_TRANSFER_BALANCES      = "transferBalance"
-	CMD_PRIVATE_MARGIN_ACCOUNT_SUMMARY = "returnMarginAccountSummary" // Todo
-	CMD_PRIVATE_MARGIN_BUY             = "marginBuy"                  // Todo
-	CMD_PRIVATE_MARGIN_SELL            = "marginSell"                 // Todo
-	CMD_PRIVATE_MARGIN_POSITION        = "getMarginPosition"          // Todo
-	CMD_PRIVATE_CLOSE_MARGIN_POSITION  = "closeMarginPosition"        // Todo
+	CMD_PRIVATE_MARGIN_ACCOUNT_SUMMARY = "returnMarginAccountSummary"
+	CMD_PRIVATE_MARGIN_BUY             = "marginBuy"  // Todo
+	CMD_PRIVATE_MARGIN_SELL            = "marginSell" // Todo
+	CMD_PRIVATE_MARGIN_POSITION        = "getMarginPosition"
+	CMD_PRIVATE_CLOSE_MARGIN_POSITION  = "closeMarginPosition"
 	// Loan
 	CMD_PRIVATE_CREATE_LOAD_OFFER = "createLoanOffer"      // Todo
 	CMD_PRIVATE_CANCEL_LOAD_OFFER = "cancelLoanOffer"      // Todo
-	CMD_PRIVATE_OPEN_LOAD_OFFER   = "returnOpenLoanOffers" // Todo
-	CMD_PRIVATE_ACTIVE_LOANS      = "returnActiveLoans"    // Todo
-	CMD_PRIVATE_LENDING_HISTORY   = "returnLendingHistory" // Todo
-	CMD_PRIVATE_TOGGLE_AUTO_RENEW = "toggleAutoRenew"      // Todo
+	CMD_PRIVATE_OPEN_LOAD_OFFER   = "returnOpenLoanOffers"
+	CMD_PRIVATE_ACTIVE_LOANS      = "returnActiveLoans"
+	CMD_PRIVATE_LENDING_HISTORY   = "returnLendingHistory"
+	CMD_PRIVATE_TOGGLE_AUTO_RENEW = "toggleAutoRenew" // Todo
 )
 
 type PoloniexApi struct {
-	Key       string
-	secret    string
-	UserAgent string
-	Client    *http.Client
+	Key         string
+	secret      string
+	credentials CredentialsProvider
+	UserAgent   string
+	Client      *http.Client
+	Debug       bool
+	DryRun      bool            // Validate, sign and log state-changing calls, but never send them
+	Validator   *OrderValidator // Checks orders before sending them, nil (the default) to disable
+	Limiter     *RateLimiter    // Spaces requests, nil to disable
+	Observer    Observer        // Notified of every request, e.g. for metrics
+	Tracer      trace.Tracer    // Traces every request, nil to disable
+	Cache       *Cache          // Keeps the responses of public commands, nil to disable
 }
 
 func New(key string, secret string) *PoloniexApi {
 	client := &http.Client{}
 	user_agent := "poloniex-api"
 
-	return &PoloniexApi{key, secret, user_agent, client}
+	return &PoloniexApi{
+		Key:       key,
+		secret:    secret,
+		UserAgent: user_agent,
+		Client:    client,
+		Limiter:   NewRateLimiter(DefaultRequestsPerSecond),
+	}
+}
+
+/*
+NewFromCredentials creates a client whose secret is never stored: it is asked
+to the provider for every signed request and wiped right after signing. The
+provider is called once here to make sure credentials are available.
+*/
+func NewFromCredentials(provider CredentialsProvider) (*PoloniexApi, error) {
+	key, secret, err := provider.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	wipe(secret)
+
+	api := New(key, "")
+	api.credentials = provider
+
+	return api, nil
 }
 
 /*
@@ -75,12 +110,17 @@ Returns the ticker for all markets. Sample output:
 Call: https://poloniex.com/public?command=returnTicker
 */
 func (api *PoloniexApi) ApiPublicTicker() (map[string]Ticker, error) {
+	return api.ApiPublicTickerContext(context.Background())
+}
+
+// ApiPublicTickerContext is ApiPublicTicker with the context of the request.
+func (api *PoloniexApi) ApiPublicTickerContext(ctx context.Context) (map[string]Ticker, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PUBLIC_TICKER)
 
 	out := make(map[string]Ticker)
 
-	_, err := api.queryparse(URL_PUBLIC, params, false, &out)
+	_, err := api.queryparse(ctx, URL_PUBLIC, params, false, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -98,10 +138,15 @@ Returns the 24-hour volume for all markets, plus totals for primary currencies.
 Call: https://poloniex.com/public?command=return24hVolume
 */
 func (api *PoloniexApi) ApiPublic24hVolume() (map[string]float64, map[string]map[string]float64, error) {
+	return api.ApiPublic24hVolumeContext(context.Background())
+}
+
+// ApiPublic24hVolumeContext is ApiPublic24hVolume with the context of the request.
+func (api *PoloniexApi) ApiPublic24hVolumeContext(ctx context.Context) (map[string]float64, map[string]map[string]float64, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PUBLIC_24HVOLUME)
 
-	content, err := api.queryparse(URL_PUBLIC, params, false, nil)
+	content, err := api.queryparse(ctx, URL_PUBLIC, params, false, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -196,6 +241,11 @@ Or, for all markets:
 Call: https://poloniex.com/public?command=returnOrderBook&currencyPair=BTC_NXT&depth=10
 */
 func (api *PoloniexApi) ApiPublicOrderBook(pair string, depth int) (map[string]OrderBookEntry, error) {
+	return api.ApiPublicOrderBookContext(context.Background(), pair, depth)
+}
+
+// ApiPublicOrderBookContext is ApiPublicOrderBook with the context of the request.
+func (api *PoloniexApi) ApiPublicOrderBookContext(ctx context.Context, pair string, depth int) (map[string]OrderBookEntry, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PUBLIC_ORDER_BOOK)
 	params.Set("currencyPair", pair)
@@ -204,7 +254,7 @@ func (api *PoloniexApi) ApiPublicOrderBook(pair string, depth int) (map[string]O
 		params.Set("depth", strconv.Itoa(depth))
 	}
 
-	resp, err := api.query(URL_PUBLIC, params, false)
+	resp, err := api.query(ctx, URL_PUBLIC, params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +296,11 @@ timestamps by the "start" and "end" GET parameters. Sample output:
 Call: https://poloniex.com/public?command=returnTradeHistory&currencyPair=BTC_NXT&start=1410158341&end=1410499372
 */
 func (api *PoloniexApi) ApiPublicTradeHistory(pair string, start, end int) ([]Trade, error) {
+	return api.ApiPublicTradeHistoryContext(context.Background(), pair, start, end)
+}
+
+// ApiPublicTradeHistoryContext is ApiPublicTradeHistory with the context of the request.
+func (api *PoloniexApi) ApiPublicTradeHistoryContext(ctx context.Context, pair string, start, end int) ([]Trade, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PUBLIC_TRADE_HISTORY)
 	params.Set("currencyPair", pair)
@@ -260,7 +315,7 @@ func (api *PoloniexApi) ApiPublicTradeHistory(pair string, start, end int) ([]Tr
 
 	out := make([]Trade, 0)
 
-	_, err := api.queryparse(URL_PUBLIC, params, false, &out)
+	_, err := api.queryparse(ctx, URL_PUBLIC, params, false, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -280,6 +335,11 @@ given in UNIX timestamp format and used to specify the date range for the data r
 Call: https://poloniex.com/public?command=returnChartData&currencyPair=BTC_XMR&start=1405699200&end=9999999999&period=14400
 */
 func (api *PoloniexApi) ApiChartData(pair string, start, end, period int64) ([]ChartEntry, error) {
+	return api.ApiChartDataContext(context.Background(), pair, start, end, period)
+}
+
+// ApiChartDataContext is ApiChartData with the context of the request.
+func (api *PoloniexApi) ApiChartDataContext(ctx context.Context, pair string, start, end, period int64) ([]ChartEntry, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PUBLIC_CHART_DATA)
 	params.Set("currencyPair", pair)
@@ -298,7 +358,7 @@ func (api *PoloniexApi) ApiChartData(pair string, start, end, period int64) ([]C
 
 	out := make([]ChartEntry, 0)
 
-	_, err := api.queryparse(URL_PUBLIC, params, false, &out)
+	_, err := api.queryparse(ctx, URL_PUBLIC, params, false, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -315,12 +375,17 @@ Returns information about currencies. Sample output:
 Call: https://poloniex.com/public?command=returnCurrencies
 */
 func (api *PoloniexApi) ApiCurrencies() (map[string]Currency, error) {
+	return api.ApiCurrenciesContext(context.Background())
+}
+
+// ApiCurrenciesContext is ApiCurrencies with the context of the request.
+func (api *PoloniexApi) ApiCurrenciesContext(ctx context.Context) (map[string]Currency, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PUBLIC_CURRENCIES)
 
 	out := make(map[string]Currency)
 
-	_, err := api.queryparse(URL_PUBLIC, params, false, &out)
+	_, err := api.queryparse(ctx, URL_PUBLIC, params, false, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -338,13 +403,18 @@ Returns the list of loan offers and demands for a given currency, specified by t
 Call: https://poloniex.com/public?command=returnLoanOrders&currency=BTC
 */
 func (api *PoloniexApi) ApiLoanOrders(currency string) (*LoanOrders, error) {
+	return api.ApiLoanOrdersContext(context.Background(), currency)
+}
+
+// ApiLoanOrdersContext is ApiLoanOrders with the context of the request.
+func (api *PoloniexApi) ApiLoanOrdersContext(ctx context.Context, currency string) (*LoanOrders, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PUBLIC_LOAN_ORDERS)
 	params.Set("currency", currency)
 
 	out := new(LoanOrders)
 
-	_, err := api.queryparse(URL_PUBLIC, params, false, &out)
+	_, err := api.queryparse(ctx, URL_PUBLIC, params, false, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -1,3 +1,5 @@
+//go:build live
+
 package poloniexapi
 
 import (
@@ -242,14 +244,14 @@ func TestApiPrivateOrderTrades(t *testing.T) {
 }
 
 func TestApiPrivateBuy(t *testing.T) {
-	out, err := api.ApiPrivateBuy("XMR_LTC", 0.001, 42, map[string]bool{})
+	out, err := api.ApiPrivateBuy("XMR_LTC", 0.001, 42, nil)
 	CheckErr(err)
 
 	log.Println(out)
 }
 
 func TestApiPrivateSell(t *testing.T) {
-	out, err := api.ApiPrivateSell("BTC_XMR", 4999, 0.001, map[string]bool{})
+	out, err := api.ApiPrivateSell("BTC_XMR", 4999, 0.001, nil)
 	CheckErr(err)
 
 	log.Println(out)
@@ -264,12 +266,12 @@ func TestApiPrivateCancel(t *testing.T) {
 }
 
 func TestApiPrivateMoveOrder(t *testing.T) {
-	out, err := api.ApiPrivateBuy("XMR_LTC", 0.001, 42, map[string]bool{})
+	out, err := api.ApiPrivateBuy("XMR_LTC", 0.001, 42, nil)
 	CheckErr(err)
 
 	log.Println(out)
 
-	out, err = api.ApiPrivateMoveOrder(out.OrderNumber, 0.0005, 42.5, map[string]bool{})
+	out, err = api.ApiPrivateMoveOrder(out.OrderNumber, 0.0005, 42.5, nil)
 	CheckErr(err)
 
 	log.Println(out)
@@ -0,0 +1,354 @@
+package poloniexapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Longest chain of conversions tried to value a currency, e.g. XMR -> BTC ->
+// USDT is two.
+const maxConversionHops = 3
+
+// Holding is the amount of a currency held in one account, and its value.
+type Holding struct {
+	Account  string
+	Currency string
+	Amount   float64
+	Price    float64 // in the quote currency of the snapshot, zero if unpriced
+	Value    float64
+}
+
+/*
+PortfolioSnapshot is the value of all accounts at a point in time, in Quote.
+Currencies that could not be priced have a zero value, and are listed in
+Unpriced.
+*/
+type PortfolioSnapshot struct {
+	Time     time.Time
+	Quote    string
+	Total    float64
+	Accounts map[string]float64
+	Holdings []Holding
+	Unpriced []string `json:",omitempty"`
+}
+
+/*
+Conversions gives the rate at which a currency converts into another through
+the markets of a ticker. Rates are what selling would actually get: the best
+bid when selling the quote currency of a pair for its base (XMR for BTC on
+BTC_XMR), one over the best ask when buying it. Frozen markets are ignored.
+*/
+type Conversions struct {
+	edges map[string]map[string]float64
+}
+
+func NewConversions(tickers map[string]Ticker) *Conversions {
+	c := &Conversions{edges: make(map[string]map[string]float64)}
+
+	add := func(from, to string, rate float64) {
+		if c.edges[from] == nil {
+			c.edges[from] = make(map[string]float64)
+		}
+		if rate > c.edges[from][to] {
+			c.edges[from][to] = rate
+		}
+	}
+
+	for pair, ticker := range tickers {
+		base, quote, ok := splitPair(pair)
+		if !ok || ticker.IsFrozen != 0 {
+			continue
+		}
+
+		if ticker.HighestBid > 0 {
+			add(quote, base, ticker.HighestBid)
+		}
+
+		if ticker.LowestAsk > 0 {
+			add(base, quote, 1/ticker.LowestAsk)
+		}
+	}
+
+	return c
+}
+
+/*
+Rates returns, for every currency that can be converted into quote, the best
+rate over paths of at most three conversions.
+*/
+func (c *Conversions) Rates(quote string) map[string]float64 {
+	best := map[string]float64{quote: 1}
+
+	for hop := 0; hop < maxConversionHops; hop++ {
+		next := make(map[string]float64, len(best))
+		for k, v := range best {
+			next[k] = v
+		}
+
+		for from, edges := range c.edges {
+			for to, rate := range edges {
+				if via, ok := best[to]; ok && from != quote && rate*via > next[from] {
+					next[from] = rate * via
+				}
+			}
+		}
+
+		best = next
+	}
+
+	return best
+}
+
+// Rate returns the best rate from one currency to another, and false when
+// there is no path between them.
+func (c *Conversions) Rate(from, to string) (float64, bool) {
+	rate, ok := c.Rates(to)[from]
+	return rate, ok
+}
+
+// SnapshotStore keeps portfolio snapshots.
+type SnapshotStore interface {
+	Append(PortfolioSnapshot) error
+	Load() ([]PortfolioSnapshot, error)
+}
+
+// FileSnapshotStore appends snapshots to a file, one JSON object per line.
+type FileSnapshotStore struct {
+	Path string
+}
+
+func (s FileSnapshotStore) Append(snapshot PortfolioSnapshot) error {
+	f, err := os.OpenFile(s.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err = json.NewEncoder(f).Encode(snapshot); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s FileSnapshotStore) Load() ([]PortfolioSnapshot, error) {
+	f, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	out := make([]PortfolioSnapshot, 0)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var snapshot PortfolioSnapshot
+		if err = json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, err
+		}
+		out = append(out, snapshot)
+	}
+
+	return out, scanner.Err()
+}
+
+/*
+Portfolio values the holdings of every account (exchange, margin, lending) in
+a single currency, e.g. "USDT", "BTC" or "ETH".
+
+The exchange account comes from ApiPrivateCompleteBalances, including amounts
+on orders. The lending account is its available balances plus the open loan
+offers and the loans provided. The margin account is valued as a whole, from
+the net value in BTC of ApiPrivateMarginAccountSummary: its balances alone
+miss the positions and what is borrowed. Each currency is valued through the
+best conversion path to Quote in the ticker (see Conversions); currencies
+without a path are valued from their btcValue when BTC converts to Quote.
+*/
+type Portfolio struct {
+	Quote string
+	Store SnapshotStore // optional
+
+	api *PoloniexApi
+
+	mu      sync.Mutex
+	history []PortfolioSnapshot
+}
+
+func NewPortfolio(api *PoloniexApi, quote string) *Portfolio {
+	return &Portfolio{Quote: quote, api: api}
+}
+
+// Snapshot values the portfolio now, and records the snapshot.
+func (p *Portfolio) Snapshot() (PortfolioSnapshot, error) {
+	complete, err := p.api.ApiPrivateCompleteBalances(false)
+	if err != nil {
+		return PortfolioSnapshot{}, err
+	}
+
+	accounts, err := p.api.ApiPrivateAvailableAccountBalances("")
+	if err != nil {
+		return PortfolioSnapshot{}, err
+	}
+
+	offers, err := p.api.ApiPrivateOpenLoanOffers()
+	if err != nil {
+		return PortfolioSnapshot{}, err
+	}
+
+	loans, err := p.api.ApiPrivateActiveLoans()
+	if err != nil {
+		return PortfolioSnapshot{}, err
+	}
+
+	margin, err := p.api.ApiPrivateMarginAccountSummary()
+	if err != nil {
+		return PortfolioSnapshot{}, err
+	}
+
+	tickers, err := p.api.ApiPublicTicker()
+	if err != nil {
+		return PortfolioSnapshot{}, err
+	}
+
+	snapshot := valuePortfolio(p.Quote, portfolioBalances{
+		complete: complete,
+		accounts: accounts,
+		offers:   offers,
+		loans:    loans,
+		margin:   margin,
+	}, tickers)
+
+	if p.Store != nil {
+		if err = p.Store.Append(snapshot); err != nil {
+			return snapshot, err
+		}
+	}
+
+	p.mu.Lock()
+	p.history = append(p.history, snapshot)
+	p.mu.Unlock()
+
+	return snapshot, nil
+}
+
+// History returns the snapshots taken by this Portfolio.
+func (p *Portfolio) History() []PortfolioSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]PortfolioSnapshot(nil), p.history...)
+}
+
+// Run takes a snapshot every interval until ctx is done. Errors are passed to
+// onError, which may be nil.
+func (p *Portfolio) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	poll(ctx, interval, func() bool {
+		if _, err := p.Snapshot(); err != nil && onError != nil {
+			onError(err)
+		}
+		return true
+	}, func() {})
+}
+
+// portfolioBalances is what a portfolio is valued from.
+type portfolioBalances struct {
+	complete map[string]Balance            // exchange account
+	accounts map[string]map[string]float64 // available balances by account
+	offers   map[string][]LoanOffer
+	loans    *ActiveLoans
+	margin   *MarginAccountSummary
+}
+
+func valuePortfolio(quote string, b portfolioBalances, tickers map[string]Ticker) PortfolioSnapshot {
+	snapshot := PortfolioSnapshot{
+		Time:     time.Now(),
+		Quote:    quote,
+		Accounts: make(map[string]float64),
+		Holdings: make([]Holding, 0),
+	}
+
+	rates := NewConversions(tickers).Rates(quote)
+	unpriced := make(map[string]bool)
+
+	add := func(account, currency string, amount, btcValue float64) {
+		if amount == 0 {
+			return
+		}
+
+		h := Holding{Account: account, Currency: currency, Amount: amount}
+
+		if rate, ok := rates[currency]; ok {
+			h.Price = rate
+		} else if btc, ok := rates["BTC"]; ok && btcValue != 0 {
+			h.Price = btcValue / amount * btc
+		} else {
+			unpriced[currency] = true
+		}
+
+		h.Value = h.Amount * h.Price
+		snapshot.Holdings = append(snapshot.Holdings, h)
+		snapshot.Accounts[account] += h.Value
+		snapshot.Total += h.Value
+	}
+
+	for currency, balance := range b.complete {
+		add("exchange", currency, balance.Available+balance.OnOrders, balance.BtcValue)
+	}
+
+	lending := make(map[string]float64)
+	for currency, amount := range b.accounts["lending"] {
+		lending[currency] += amount
+	}
+	for currency, offers := range b.offers {
+		for _, offer := range offers {
+			lending[currency] += offer.Amount
+		}
+	}
+	if b.loans != nil {
+		for _, loan := range b.loans.Provided {
+			lending[loan.Currency] += loan.Amount
+		}
+	}
+	for currency, amount := range lending {
+		add("lending", currency, amount, 0)
+	}
+
+	if b.margin != nil {
+		add("margin", "BTC", b.margin.NetValue, 0)
+	}
+
+	for account, balances := range b.accounts {
+		if account == "exchange" || account == "lending" || account == "margin" {
+			continue
+		}
+
+		for currency, amount := range balances {
+			add(account, currency, amount, 0)
+		}
+	}
+
+	sort.Slice(snapshot.Holdings, func(i, j int) bool {
+		a, b := snapshot.Holdings[i], snapshot.Holdings[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		return a.Currency < b.Currency
+	})
+
+	for currency := range unpriced {
+		snapshot.Unpriced = append(snapshot.Unpriced, currency)
+	}
+	sort.Strings(snapshot.Unpriced)
+
+	return snapshot
+}
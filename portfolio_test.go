@@ -0,0 +1,146 @@
+package poloniexapi
+
+import (
+	"math"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func testTickers() map[string]Ticker {
+	return map[string]Ticker{
+		"USDT_BTC": {HighestBid: 8000, LowestAsk: 8010},
+		"BTC_XMR":  {HighestBid: 0.02, LowestAsk: 0.021},
+		"USDT_ETH": {HighestBid: 400, LowestAsk: 410},
+		"BTC_ETH":  {HighestBid: 0.06, LowestAsk: 0.061, IsFrozen: 1}, // would beat USDT_ETH
+		"BTC_DOGE": {HighestBid: 0.0000003, LowestAsk: 0.0000004, IsFrozen: 1},
+		"BTCXMR":   {HighestBid: 1, LowestAsk: 1}, // not a pair
+	}
+}
+
+func TestConversionsRate(t *testing.T) {
+	conversions := NewConversions(testTickers())
+
+	cases := []struct {
+		name     string
+		from, to string
+		rate     float64
+		ok       bool
+	}{
+		{"same currency", "USDT", "USDT", 1, true},
+		{"direct", "BTC", "USDT", 8000, true},
+		{"inverse market", "USDT", "BTC", 1 / 8010., true},
+		{"multi-hop", "XMR", "USDT", 0.02 * 8000, true},
+		{"multi-hop inverse", "USDT", "XMR", 1 / 8010. / 0.021, true},
+		{"frozen market ignored", "ETH", "BTC", 400 / 8010., true},
+		{"three hops", "ETH", "XMR", 400 / 8010. / 0.021, true},
+		{"only frozen markets", "DOGE", "USDT", 0, false},
+		{"unknown currency", "FOO", "USDT", 0, false},
+	}
+
+	for _, c := range cases {
+		rate, ok := conversions.Rate(c.from, c.to)
+		if ok != c.ok || math.Abs(rate-c.rate) > 1e-12*math.Max(1, c.rate) {
+			t.Errorf("%s: %s to %s got %v %v, want %v %v", c.name, c.from, c.to, rate, ok, c.rate, c.ok)
+		}
+	}
+}
+
+func TestConversionsRates(t *testing.T) {
+	rates := NewConversions(testTickers()).Rates("USDT")
+
+	want := map[string]float64{"USDT": 1, "BTC": 8000, "XMR": 160, "ETH": 400}
+	if len(rates) != len(want) {
+		t.Errorf("got %v, want %v", rates, want)
+	}
+	for currency, rate := range want {
+		if math.Abs(rates[currency]-rate) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", currency, rates[currency], rate)
+		}
+	}
+}
+
+func TestValuePortfolio(t *testing.T) {
+	b := portfolioBalances{
+		complete: map[string]Balance{
+			"BTC": {Available: 1, OnOrders: 0.5, BtcValue: 1.5},
+			"XMR": {Available: 10, BtcValue: 0.2},
+			"FOO": {Available: 100, BtcValue: 0.01}, // no market, valued from btcValue
+			"BAR": {Available: 5},
+			"ETH": {},
+		},
+		accounts: map[string]map[string]float64{
+			"exchange": {"BTC": 1}, // from the complete balances
+			"margin":   {"BTC": 3}, // from the summary
+			"lending":  {"XMR": 1},
+		},
+		offers: map[string][]LoanOffer{"XMR": {{Amount: 2}}},
+		loans: &ActiveLoans{
+			Provided: []Loan{{Currency: "XMR", Amount: 3}},
+			Used:     []Loan{{Currency: "BTC", Amount: 0.5}},
+		},
+		margin: &MarginAccountSummary{TotalValue: 0.5, NetValue: 0.25},
+	}
+
+	snapshot := valuePortfolio("USDT", b, testTickers())
+
+	want := []Holding{
+		{Account: "exchange", Currency: "BAR", Amount: 5},
+		{Account: "exchange", Currency: "BTC", Amount: 1.5, Price: 8000, Value: 12000},
+		{Account: "exchange", Currency: "FOO", Amount: 100, Price: 0.8, Value: 80},
+		{Account: "exchange", Currency: "XMR", Amount: 10, Price: 160, Value: 1600},
+		{Account: "lending", Currency: "XMR", Amount: 6, Price: 160, Value: 960},
+		{Account: "margin", Currency: "BTC", Amount: 0.25, Price: 8000, Value: 2000},
+	}
+
+	if len(snapshot.Holdings) != len(want) {
+		t.Fatalf("got %+v, want %+v", snapshot.Holdings, want)
+	}
+	for i, h := range snapshot.Holdings {
+		w := want[i]
+		if h.Account != w.Account || h.Currency != w.Currency || math.Abs(h.Amount-w.Amount) > 1e-9 || math.Abs(h.Price-w.Price) > 1e-9 || math.Abs(h.Value-w.Value) > 1e-6 {
+			t.Errorf("holding %d: got %+v, want %+v", i, h, w)
+		}
+	}
+
+	accounts := map[string]float64{"exchange": 13680, "lending": 960, "margin": 2000}
+	for account, value := range accounts {
+		if math.Abs(snapshot.Accounts[account]-value) > 1e-6 {
+			t.Errorf("%s: got %v, want %v", account, snapshot.Accounts[account], value)
+		}
+	}
+
+	if math.Abs(snapshot.Total-16640) > 1e-6 || !reflect.DeepEqual(snapshot.Unpriced, []string{"BAR"}) {
+		t.Errorf("got total %v unpriced %v, want 16640 and BAR", snapshot.Total, snapshot.Unpriced)
+	}
+}
+
+func TestPortfolioSnapshot(t *testing.T) {
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_COMPLETE_BALANCES:
+			return `{"BTC":{"available":"1","onOrders":"0","btcValue":"1"}}`
+		case CMD_PRIVATE_AVAILABLE_ACCOUNT_BALANCES:
+			return `{"exchange":{"BTC":"1"},"lending":{"BTC":"0.5"}}`
+		case CMD_PRIVATE_OPEN_LOAD_OFFER:
+			return `[]`
+		case CMD_PRIVATE_ACTIVE_LOANS:
+			return `{"provided":[{"id":1,"currency":"BTC","rate":"0.0002","amount":"0.25","range":2,"autoRenew":0,"date":"2018-03-01 12:00:00","fees":"0"}],"used":[]}`
+		case CMD_PRIVATE_MARGIN_ACCOUNT_SUMMARY:
+			return `{"totalValue":"0.2","pl":"0","lendingFees":"0","netValue":"0.2","totalBorrowedValue":"0","currentMargin":"1"}`
+		case CMD_PUBLIC_TICKER:
+			return `{"USDT_BTC":{"id":121,"last":"8000","lowestAsk":"8010","highestBid":"8000","percentChange":"0","baseVolume":"0","quoteVolume":"0","isFrozen":"0","high24hr":"0","low24hr":"0"}}`
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	snapshot, err := NewPortfolio(api, "USDT").Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{"exchange": 8000, "lending": 6000, "margin": 1600}
+	if !reflect.DeepEqual(snapshot.Accounts, want) || snapshot.Total != 15600 {
+		t.Errorf("got %v total %v, want %v", snapshot.Accounts, snapshot.Total, want)
+	}
+}
@@ -1,8 +1,8 @@
 package poloniexapi
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/url"
 	"strconv"
 )
@@ -16,12 +16,17 @@ Returns all of your available balances. Sample output:
 {"BTC":"0.59098578","LTC":"3.31117268", ... }
 */
 func (api *PoloniexApi) ApiPrivateBalances() (map[string]float64, error) {
+	return api.ApiPrivateBalancesContext(context.Background())
+}
+
+// ApiPrivateBalancesContext is ApiPrivateBalances with the context of the request.
+func (api *PoloniexApi) ApiPrivateBalancesContext(ctx context.Context) (map[string]float64, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_BALANCES)
 
 	out_json := new(BalancesJson)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out_json)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out_json)
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +53,11 @@ your margin and lending accounts. Sample output:
 {"LTC":{"available":"5.015","onOrders":"1.0025","btcValue":"0.078"},"NXT:{...} ... }
 */
 func (api *PoloniexApi) ApiPrivateCompleteBalances(complete bool) (map[string]Balance, error) {
+	return api.ApiPrivateCompleteBalancesContext(context.Background(), complete)
+}
+
+// ApiPrivateCompleteBalancesContext is ApiPrivateCompleteBalances with the context of the request.
+func (api *PoloniexApi) ApiPrivateCompleteBalancesContext(ctx context.Context, complete bool) (map[string]Balance, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_COMPLETE_BALANCES)
 
@@ -57,7 +67,7 @@ func (api *PoloniexApi) ApiPrivateCompleteBalances(complete bool) (map[string]Ba
 
 	out := make(map[string]Balance)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -73,12 +83,17 @@ Returns all of your deposit addresses. Sample output:
  "ITC":"Press Generate.." ... }
 */
 func (api *PoloniexApi) ApiPrivateDepositAddresses() (map[string]string, error) {
+	return api.ApiPrivateDepositAddressesContext(context.Background())
+}
+
+// ApiPrivateDepositAddressesContext is ApiPrivateDepositAddresses with the context of the request.
+func (api *PoloniexApi) ApiPrivateDepositAddressesContext(ctx context.Context) (map[string]string, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_DEPOSIT_ADDRESSES)
 
 	out := make(map[string]string)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -97,13 +112,18 @@ Only one address per currency per day may be generated, and a new address may no
 before the previously-generated one has been used.
 */
 func (api *PoloniexApi) ApiPrivateGenerateNewAddress(currency string) (*GenerateAddressResponse, error) {
+	return api.ApiPrivateGenerateNewAddressContext(context.Background(), currency)
+}
+
+// ApiPrivateGenerateNewAddressContext is ApiPrivateGenerateNewAddress with the context of the request.
+func (api *PoloniexApi) ApiPrivateGenerateNewAddressContext(ctx context.Context, currency string) (*GenerateAddressResponse, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_NEW_ADDRESS)
 	params.Set("currency", currency)
 
 	out := new(GenerateAddressResponse)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +146,11 @@ both of which should be given as UNIX timestamps. Sample output:
 "timestamp":1399267904,"status":"COMPLETE: 36e483efa6aff9fd53a235177579d98451c4eb237c210e66cd2b9a2d4a988f8e","ipAddress":"..."}]}
 */
 func (api *PoloniexApi) ApiPrivateDepositWithdrawals(start, end int64) (*DepositWithdrawal, error) {
+	return api.ApiPrivateDepositWithdrawalsContext(context.Background(), start, end)
+}
+
+// ApiPrivateDepositWithdrawalsContext is ApiPrivateDepositWithdrawals with the context of the request.
+func (api *PoloniexApi) ApiPrivateDepositWithdrawalsContext(ctx context.Context, start, end int64) (*DepositWithdrawal, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_DEPOSIT_WITHDRAWALS)
 
@@ -133,7 +158,7 @@ func (api *PoloniexApi) ApiPrivateDepositWithdrawals(start, end int64) (*Deposit
 	params.Set("end", strconv.FormatInt(end, 10))
 
 	out := new(DepositWithdrawal)
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -150,19 +175,24 @@ Set "currencyPair" to "all" to return open orders for all markets. Sample output
  {"orderNumber":"120467","type":"sell","rate":"0.04","amount":"100","total":"4"}, ... ]
 */
 func (api *PoloniexApi) ApiPrivateOpenOrders(currencyPair string) (map[string][]OpenOrder, error) {
+	return api.ApiPrivateOpenOrdersContext(context.Background(), currencyPair)
+}
+
+// ApiPrivateOpenOrdersContext is ApiPrivateOpenOrders with the context of the request.
+func (api *PoloniexApi) ApiPrivateOpenOrdersContext(ctx context.Context, currencyPair string) (map[string][]OpenOrder, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_OPEN_ORDERS)
 	params.Set("currencyPair", currencyPair)
 
 	out := make(map[string][]OpenOrder)
 	if currencyPair == "all" {
-		_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+		_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		out_tmp := make([]OpenOrder, 0)
-		_, err := api.queryparse(URL_PRIVATE, params, true, &out_tmp)
+		_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out_tmp)
 		if err != nil {
 			return nil, err
 		}
@@ -188,6 +218,25 @@ range, it will be limited to one day. Sample output:
    "orderNumber": "34225195693", "type": "buy", "category": "exchange" }, ... ]
 */
 func (api *PoloniexApi) ApiPrivateTradeHistory(currencyPair string, start, end int) (map[string][]Trade, error) {
+	return api.ApiPrivateTradeHistoryContext(context.Background(), currencyPair, start, end)
+}
+
+// ApiPrivateTradeHistoryContext is ApiPrivateTradeHistory with the context of the request.
+func (api *PoloniexApi) ApiPrivateTradeHistoryContext(ctx context.Context, currencyPair string, start, end int) (map[string][]Trade, error) {
+	return api.ApiPrivateTradeHistoryLimitContext(ctx, currencyPair, start, end, 0)
+}
+
+/*
+ApiPrivateTradeHistoryLimit is ApiPrivateTradeHistory with a "limit" on the
+number of trades returned, up to HistoryLimit; without one the exchange
+returns at most 500. A zero limit is not sent.
+*/
+func (api *PoloniexApi) ApiPrivateTradeHistoryLimit(currencyPair string, start, end, limit int) (map[string][]Trade, error) {
+	return api.ApiPrivateTradeHistoryLimitContext(context.Background(), currencyPair, start, end, limit)
+}
+
+// ApiPrivateTradeHistoryLimitContext is ApiPrivateTradeHistoryLimit with the context of the request.
+func (api *PoloniexApi) ApiPrivateTradeHistoryLimitContext(ctx context.Context, currencyPair string, start, end, limit int) (map[string][]Trade, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_TRADE_HISTORY)
 	params.Set("currencyPair", currencyPair)
@@ -200,20 +249,31 @@ func (api *PoloniexApi) ApiPrivateTradeHistory(currencyPair string, start, end i
 		params.Set("end", strconv.Itoa(end))
 	}
 
+	if limit != 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
 	out := make(map[string][]Trade)
 
 	if currencyPair != "all" {
 		out_tmp := make([]Trade, 0)
-		_, err := api.queryparse(URL_PRIVATE, params, true, &out_tmp)
+		_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out_tmp)
 		if err != nil {
 			return nil, err
 		}
 		out[currencyPair] = out_tmp
 	} else {
-		_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+		// No trade at all in the range comes as an empty array.
+		var raw json.RawMessage
+		_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &raw)
 		if err != nil {
 			return nil, err
 		}
+		if string(raw) != "[]" {
+			if _, err = api.parse(raw, &out); err != nil {
+				return nil, &RequestError{Command: CMD_PRIVATE_TRADE_HISTORY, Err: err}
+			}
+		}
 	}
 
 	return out, nil
@@ -226,13 +286,18 @@ Returns all trades involving a given order, specified by the "orderNumber" POST
 [{"globalTradeID": 20825863, "tradeID": 147142, "currencyPair": "BTC_XVC", "type": "buy", "rate": "0.00018500", "amount": "455.34206390", "total": "0.08423828", "fee": "0.00200000", "date": "2016-03-14 01:04:36"}, ...]
 */
 func (api *PoloniexApi) ApiPrivateOrderTrades(orderNumber string) ([]Trade, error) {
+	return api.ApiPrivateOrderTradesContext(context.Background(), orderNumber)
+}
+
+// ApiPrivateOrderTradesContext is ApiPrivateOrderTrades with the context of the request.
+func (api *PoloniexApi) ApiPrivateOrderTradesContext(ctx context.Context, orderNumber string) ([]Trade, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_ORDER_TRADES)
 	params.Set("orderNumber", orderNumber)
 
 	out := make([]Trade, 0)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -257,63 +322,30 @@ portion of the order that cannot be filled immediately will be canceled rather
 than left on the order book. A post-only order will only be placed if no
 portion of it fills immediately; this guarantees you will never pay the taker
 fee on any part of the order that fills.
-*/
-func (api *PoloniexApi) ApiPrivateBuy(currencyPair string, rate, amount float64, opts map[string]bool) (*Order, error) {
-	params := url.Values{}
-	params.Set("command", CMD_PRIVATE_BUY)
-	params.Set("currencyPair", currencyPair)
-	params.Set("rate", strconv.FormatFloat(rate, 'f', -1, 64))
-	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
-
-	if _, ok := opts["fillOrKill"]; ok {
-		params.Set("fillOrKill", "1")
-	}
-	if _, ok := opts["immediateOrCancel"]; ok {
-		params.Set("immediateOrCancel", "1")
-	}
-	if _, ok := opts["postOnly"]; ok {
-		params.Set("postOnly", "1")
-	}
-
-	out := new(Order)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
-	if err != nil {
-		return nil, err
-	}
+These are set through OrderOptions; a nil opts places a good-till-cancelled
+order.
+*/
+func (api *PoloniexApi) ApiPrivateBuy(currencyPair string, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	return api.ApiPrivateBuyContext(context.Background(), currencyPair, rate, amount, opts)
+}
 
-	return out, nil
+// ApiPrivateBuyContext is ApiPrivateBuy with the context of the request.
+func (api *PoloniexApi) ApiPrivateBuyContext(ctx context.Context, currencyPair string, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	return api.PlaceOrderContext(ctx, SideBuy, currencyPair, rate, amount, opts)
 }
 
 /*
 sell
 Places a sell order in a given market. Parameters and output are the same as for the buy method.
 */
-func (api *PoloniexApi) ApiPrivateSell(currencyPair string, rate, amount float64, opts map[string]bool) (*Order, error) {
-	params := url.Values{}
-	params.Set("command", CMD_PRIVATE_SELL)
-	params.Set("currencyPair", currencyPair)
-	params.Set("rate", strconv.FormatFloat(rate, 'f', -1, 64))
-	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
-
-	if _, ok := opts["fillOrKill"]; ok {
-		params.Set("fillOrKill", "1")
-	}
-	if _, ok := opts["immediateOrCancel"]; ok {
-		params.Set("immediateOrCancel", "1")
-	}
-	if _, ok := opts["postOnly"]; ok {
-		params.Set("postOnly", "1")
-	}
-
-	out := new(Order)
-
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
-	if err != nil {
-		return nil, err
-	}
+func (api *PoloniexApi) ApiPrivateSell(currencyPair string, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	return api.ApiPrivateSellContext(context.Background(), currencyPair, rate, amount, opts)
+}
 
-	return out, nil
+// ApiPrivateSellContext is ApiPrivateSell with the context of the request.
+func (api *PoloniexApi) ApiPrivateSellContext(ctx context.Context, currencyPair string, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	return api.PlaceOrderContext(ctx, SideSell, currencyPair, rate, amount, opts)
 }
 
 /*
@@ -322,21 +354,28 @@ Cancels an order you have placed in a given market. Required POST parameter is "
 {"success":1}
 */
 func (api *PoloniexApi) ApiPrivateCancel(orderNumber int64) (bool, *CancelOrder, error) {
+	return api.ApiPrivateCancelContext(context.Background(), orderNumber)
+}
+
+// ApiPrivateCancelContext is ApiPrivateCancel with the context of the request.
+func (api *PoloniexApi) ApiPrivateCancelContext(ctx context.Context, orderNumber int64) (bool, *CancelOrder, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_CANCEL_ORDER)
 	params.Set("orderNumber", strconv.FormatInt(orderNumber, 10))
 
 	out := new(CancelOrder)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return false, nil, err
 	}
 
 	if out.Error != "" {
-		return false, nil, fmt.Errorf(out.Error)
+		return false, nil, &ApiError{Command: CMD_PRIVATE_CANCEL_ORDER, Message: out.Error}
 	}
 
+	api.orderSent()
+
 	return 1 == out.Success, out, nil
 }
 
@@ -347,11 +386,24 @@ transaction, meaning either both operations will succeed or both will fail.
 Required POST parameters are "orderNumber" and "rate"; you may optionally
 specify "amount" if you wish to change the amount of the new order. "postOnly"
 or "immediateOrCancel" may be specified for exchange orders, but will have no
-effect on margin orders. Sample output:
+effect on margin orders; fill-or-kill is refused. Sample output:
 
 {"success":1,"orderNumber":"239574176","resultingTrades":{"BTC_BTS":[]}}
 */
-func (api *PoloniexApi) ApiPrivateMoveOrder(orderNumber int64, rate, amount float64, opts map[string]bool) (*Order, error) {
+func (api *PoloniexApi) ApiPrivateMoveOrder(orderNumber int64, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	return api.ApiPrivateMoveOrderContext(context.Background(), orderNumber, rate, amount, opts)
+}
+
+// ApiPrivateMoveOrderContext is ApiPrivateMoveOrder with the context of the request.
+func (api *PoloniexApi) ApiPrivateMoveOrderContext(ctx context.Context, orderNumber int64, rate, amount float64, opts *OrderOptions) (*Order, error) {
+	if err := opts.validateMove(); err != nil {
+		return nil, err
+	}
+
+	if err := api.validateMove(ctx, orderNumber, rate, amount); err != nil {
+		return nil, err
+	}
+
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_MOVE_ORDER)
 	params.Set("orderNumber", strconv.FormatInt(orderNumber, 10))
@@ -361,20 +413,17 @@ func (api *PoloniexApi) ApiPrivateMoveOrder(orderNumber int64, rate, amount floa
 		params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
 	}
 
-	if _, ok := opts["immediateOrCancel"]; ok {
-		params.Set("immediateOrCancel", "1")
-	}
-	if _, ok := opts["postOnly"]; ok {
-		params.Set("postOnly", "1")
-	}
+	opts.apply(params)
 
 	out := new(Order)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return nil, err
 	}
 
+	api.orderSent()
+
 	return out, nil
 }
 
@@ -388,20 +437,33 @@ Sample output:
 
 {"response":"Withdrew 2398 NXT."}
 
-XXX: Untested method
+An empty paymentId is not sent. See WithdrawGuard for a checked version.
 */
-func (api *PoloniexApi) ApiPrivateWithdraw(currency, address string, amount float64) (string, error) {
+func (api *PoloniexApi) ApiPrivateWithdraw(currency, address string, amount float64, paymentId string) (*WithdrawResponse, error) {
+	return api.ApiPrivateWithdrawContext(context.Background(), currency, address, amount, paymentId)
+}
+
+// ApiPrivateWithdrawContext is ApiPrivateWithdraw with the context of the request.
+func (api *PoloniexApi) ApiPrivateWithdrawContext(ctx context.Context, currency, address string, amount float64, paymentId string) (*WithdrawResponse, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_WITHDRAW)
 	params.Set("currency", currency)
 	params.Set("address", address)
 	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
 
-	var out string
+	if paymentId != "" {
+		params.Set("paymentId", paymentId)
+	}
+
+	out := new(WithdrawResponse)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if out.Error != "" {
+		return nil, &ApiError{Command: CMD_PRIVATE_WITHDRAW, Message: out.Error}
 	}
 
 	return out, nil
@@ -416,12 +478,17 @@ once every 24 hours.
 {"makerFee": "0.00140000", "takerFee": "0.00240000", "thirtyDayVolume": "612.00248891", "nextTier": "1200.00000000"}
 */
 func (api *PoloniexApi) ApiPrivateFeeInfo() (*FeeInfo, error) {
+	return api.ApiPrivateFeeInfoContext(context.Background())
+}
+
+// ApiPrivateFeeInfoContext is ApiPrivateFeeInfo with the context of the request.
+func (api *PoloniexApi) ApiPrivateFeeInfoContext(ctx context.Context) (*FeeInfo, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_FEE_INFO)
 
 	out := new(FeeInfo)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -459,6 +526,11 @@ have any open margin positions or orders. Sample output:
  "XMR":"497.12028113"},"lending":{"DASH":"0.01174765","LTC":"11.99936230"}}
 */
 func (api *PoloniexApi) ApiPrivateAvailableAccountBalances(account string) (map[string]map[string]float64, error) {
+	return api.ApiPrivateAvailableAccountBalancesContext(context.Background(), account)
+}
+
+// ApiPrivateAvailableAccountBalancesContext is ApiPrivateAvailableAccountBalances with the context of the request.
+func (api *PoloniexApi) ApiPrivateAvailableAccountBalancesContext(ctx context.Context, account string) (map[string]map[string]float64, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_AVAILABLE_ACCOUNT_BALANCES)
 	if account != "" {
@@ -467,7 +539,7 @@ func (api *PoloniexApi) ApiPrivateAvailableAccountBalances(account string) (map[
 
 	out_tmp := make(map[string]map[string]json.Number)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out_tmp)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out_tmp)
 	if err != nil {
 		return nil, err
 	}
@@ -491,12 +563,17 @@ continually with market conditions. Sample output:
  "BTC_XMR":{"BTC":"8.50274777","XMR":"3696.84685650"}}
 */
 func (api *PoloniexApi) ApiPrivateTradableBalances() (map[string]map[string]float64, error) {
+	return api.ApiPrivateTradableBalancesContext(context.Background())
+}
+
+// ApiPrivateTradableBalancesContext is ApiPrivateTradableBalances with the context of the request.
+func (api *PoloniexApi) ApiPrivateTradableBalancesContext(ctx context.Context) (map[string]map[string]float64, error) {
 	params := url.Values{}
 	params.Set("command", CMD_PRIVATE_TRADABLE_BALANCES)
 
 	out_tmp := make(map[string]map[string]json.Number)
 
-	_, err := api.queryparse(URL_PRIVATE, params, true, &out_tmp)
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out_tmp)
 	if err != nil {
 		return nil, err
 	}
@@ -508,3 +585,245 @@ func (api *PoloniexApi) ApiPrivateTradableBalances() (map[string]map[string]floa
 
 	return out, nil
 }
+
+/*
+transferBalance
+Transfers funds from one account to another (e.g. from your exchange account
+to your margin account). Required POST parameters are "currency", "amount",
+"fromAccount", and "toAccount". Sample output:
+
+{"success":1,"message":"Transferred 2 BTC from exchange to margin account."}
+*/
+func (api *PoloniexApi) ApiPrivateTransferBalance(currency string, amount float64, fromAccount, toAccount string) (*TransferBalanceResponse, error) {
+	return api.ApiPrivateTransferBalanceContext(context.Background(), currency, amount, fromAccount, toAccount)
+}
+
+// ApiPrivateTransferBalanceContext is ApiPrivateTransferBalance with the context of the request.
+func (api *PoloniexApi) ApiPrivateTransferBalanceContext(ctx context.Context, currency string, amount float64, fromAccount, toAccount string) (*TransferBalanceResponse, error) {
+	params := url.Values{}
+	params.Set("command", CMD_PRIVATE_TRANSFER_BALANCES)
+	params.Set("currency", currency)
+	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+	params.Set("fromAccount", fromAccount)
+	params.Set("toAccount", toAccount)
+
+	out := new(TransferBalanceResponse)
+
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	if out.Error != "" {
+		return nil, &ApiError{Command: CMD_PRIVATE_TRANSFER_BALANCES, Message: out.Error}
+	}
+
+	return out, nil
+}
+
+/*
+returnMarginAccountSummary
+Returns a summary of your entire margin account. This is the same information
+you will find in the Margin Account section of the Margin Trading page, under
+the Markets list. Sample output:
+
+{"totalValue": "0.00346561","pl": "-0.00001220","lendingFees": "0.00000000",
+ "netValue": "0.00345341","totalBorrowedValue": "0.00123220","currentMargin": "2.80263755"}
+*/
+func (api *PoloniexApi) ApiPrivateMarginAccountSummary() (*MarginAccountSummary, error) {
+	return api.ApiPrivateMarginAccountSummaryContext(context.Background())
+}
+
+// ApiPrivateMarginAccountSummaryContext is ApiPrivateMarginAccountSummary with the context of the request.
+func (api *PoloniexApi) ApiPrivateMarginAccountSummaryContext(ctx context.Context) (*MarginAccountSummary, error) {
+	params := url.Values{}
+	params.Set("command", CMD_PRIVATE_MARGIN_ACCOUNT_SUMMARY)
+
+	out := new(MarginAccountSummary)
+
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+/*
+getMarginPosition
+Returns information about your margin position in a given market, specified by
+the "currencyPair" POST parameter. You may set "currencyPair" to "all" if you
+wish to fetch all of your margin positions at once. If you have no margin
+position in the specified market, "type" will be set to "none".
+"liquidationPrice" is an estimate, and does not necessarily represent the
+price at which an actual forced liquidation will occur. If you have no
+liquidation price, the value will be -1. Sample output:
+
+{"amount":"40.94717831","total":"-0.09671314","basePrice":"0.00236190",
+ "liquidationPrice":-1,"pl":"-0.00058655","lendingFees":"-0.00000038","type":"long"}
+
+The result is indexed by pair, also when a single pair is asked for.
+*/
+func (api *PoloniexApi) ApiPrivateMarginPosition(currencyPair string) (map[string]MarginPosition, error) {
+	return api.ApiPrivateMarginPositionContext(context.Background(), currencyPair)
+}
+
+// ApiPrivateMarginPositionContext is ApiPrivateMarginPosition with the context of the request.
+func (api *PoloniexApi) ApiPrivateMarginPositionContext(ctx context.Context, currencyPair string) (map[string]MarginPosition, error) {
+	params := url.Values{}
+	params.Set("command", CMD_PRIVATE_MARGIN_POSITION)
+	params.Set("currencyPair", currencyPair)
+
+	out := make(map[string]MarginPosition)
+
+	if currencyPair != "all" {
+		position := MarginPosition{}
+
+		_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &position)
+		if err != nil {
+			return nil, err
+		}
+
+		out[currencyPair] = position
+
+		return out, nil
+	}
+
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+/*
+closeMarginPosition
+Closes your margin position in a given market (specified by the "currencyPair"
+POST parameter) using a market order. This call will also return success if
+you do not have an open position in the specified market. Sample output:
+
+{"success":1,"message":"Successfully closed margin position.",
+ "resultingTrades":{"BTC_XMR":[{"amount":"7.09215901","date":"2015-05-10 22:38:49",
+ "rate":"0.00235337","total":"0.01669047","tradeID":"1213346","type":"sell"}, ... ]}}
+*/
+func (api *PoloniexApi) ApiPrivateCloseMarginPosition(currencyPair string) (*CloseMarginPositionResponse, error) {
+	return api.ApiPrivateCloseMarginPositionContext(context.Background(), currencyPair)
+}
+
+// ApiPrivateCloseMarginPositionContext is ApiPrivateCloseMarginPosition with the context of the request.
+func (api *PoloniexApi) ApiPrivateCloseMarginPositionContext(ctx context.Context, currencyPair string) (*CloseMarginPositionResponse, error) {
+	params := url.Values{}
+	params.Set("command", CMD_PRIVATE_CLOSE_MARGIN_POSITION)
+	params.Set("currencyPair", currencyPair)
+
+	out := new(CloseMarginPositionResponse)
+
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	api.orderSent()
+
+	return out, nil
+}
+
+/*
+returnLendingHistory
+Returns your lending history within a time range specified by the "start" and
+"end" POST parameters as UNIX timestamps. "limit" may also be specified to limit
+the number of rows returned. Sample output:
+
+[{"id":175589553,"currency":"BTC","rate":"0.00057400","amount":"0.04374404",
+  "duration":"0.47610000","interest":"0.00001196","fee":"-0.00000179",
+  "earned":"0.00001017","open":"2016-09-28 06:47:26","close":"2016-09-28 18:13:03"}, ... ]
+
+A zero limit is not sent.
+*/
+func (api *PoloniexApi) ApiPrivateLendingHistory(start, end int64, limit int) ([]LendingHistoryEntry, error) {
+	return api.ApiPrivateLendingHistoryContext(context.Background(), start, end, limit)
+}
+
+// ApiPrivateLendingHistoryContext is ApiPrivateLendingHistory with the context of the request.
+func (api *PoloniexApi) ApiPrivateLendingHistoryContext(ctx context.Context, start, end int64, limit int) ([]LendingHistoryEntry, error) {
+	params := url.Values{}
+	params.Set("command", CMD_PRIVATE_LENDING_HISTORY)
+	params.Set("start", strconv.FormatInt(start, 10))
+	params.Set("end", strconv.FormatInt(end, 10))
+
+	if limit != 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	out := make([]LendingHistoryEntry, 0)
+
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+/*
+returnOpenLoanOffers
+Returns your open loan offers for each currency. Sample output:
+
+{"BTC":[{"id":10595,"rate":"0.00020000","amount":"3.00000000","duration":2,"autoRenew":1,
+ "date":"2015-05-10 23:33:50"}],"LTC":[{"id":10598,"rate":"0.00002100","amount":"10.00000000",
+ "duration":2,"autoRenew":1,"date":"2015-05-10 23:34:35"}]}
+*/
+func (api *PoloniexApi) ApiPrivateOpenLoanOffers() (map[string][]LoanOffer, error) {
+	return api.ApiPrivateOpenLoanOffersContext(context.Background())
+}
+
+// ApiPrivateOpenLoanOffersContext is ApiPrivateOpenLoanOffers with the context of the request.
+func (api *PoloniexApi) ApiPrivateOpenLoanOffersContext(ctx context.Context) (map[string][]LoanOffer, error) {
+	params := url.Values{}
+	params.Set("command", CMD_PRIVATE_OPEN_LOAD_OFFER)
+
+	out := make(map[string][]LoanOffer)
+
+	// No offer at all comes as an empty array.
+	var raw json.RawMessage
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if string(raw) != "[]" {
+		if _, err = api.parse(raw, &out); err != nil {
+			return nil, &RequestError{Command: CMD_PRIVATE_OPEN_LOAD_OFFER, Err: err}
+		}
+	}
+
+	return out, nil
+}
+
+/*
+returnActiveLoans
+Returns your active loans, those you provided and those you used. Sample output:
+
+{"provided":[{"id":75073,"currency":"LTC","rate":"0.00020000","amount":"0.72234880","range":2,
+ "autoRenew":0,"date":"2015-05-10 23:45:05","fees":"0.00006000"}],"used":[{"id":75238,
+ "currency":"BTC","rate":"0.00020000","amount":"0.04843834","range":2,"date":"2015-05-10 23:51:12",
+ "fees":"-0.00000001"}]}
+*/
+func (api *PoloniexApi) ApiPrivateActiveLoans() (*ActiveLoans, error) {
+	return api.ApiPrivateActiveLoansContext(context.Background())
+}
+
+// ApiPrivateActiveLoansContext is ApiPrivateActiveLoans with the context of the request.
+func (api *PoloniexApi) ApiPrivateActiveLoansContext(ctx context.Context) (*ActiveLoans, error) {
+	params := url.Values{}
+	params.Set("command", CMD_PRIVATE_ACTIVE_LOANS)
+
+	out := new(ActiveLoans)
+
+	_, err := api.queryparse(ctx, URL_PRIVATE, params, true, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package poloniexapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Poloniex allows 6 calls per second; more get the IP banned for a while.
+const DefaultRequestsPerSecond = 6
+
+/*
+RateLimiter spaces requests evenly so that no more than a given number are
+sent per second. It is safe for concurrent use; each PoloniexApi created by New
+has its own, used for every request, and clients sharing a key should share it.
+*/
+type RateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	interval  time.Duration
+	next      time.Time
+	parent    *RateLimiter // the limiter the rate was reserved from, until released
+}
+
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	return &RateLimiter{perSecond: perSecond, interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+/*
+Reserve takes perSecond out of the rate of l and returns a limiter for it, so
+that a component gets requests through even when the rest of the application
+uses all of l. The sum of both rates stays the rate l had, until the reserved
+limiter is released.
+*/
+func (l *RateLimiter) Reserve(perSecond float64) (*RateLimiter, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if perSecond <= 0 || perSecond >= l.perSecond {
+		return nil, fmt.Errorf("Could not reserve %g requests per second out of %g", perSecond, l.perSecond)
+	}
+
+	l.perSecond -= perSecond
+	l.interval = time.Duration(float64(time.Second) / l.perSecond)
+
+	reserved := NewRateLimiter(perSecond)
+	reserved.parent = l
+
+	return reserved, nil
+}
+
+// Release gives the rate of a limiter returned by Reserve back to the limiter
+// it was taken from. It does nothing on other limiters, or when called again.
+func (l *RateLimiter) Release() {
+	l.mu.Lock()
+	parent, perSecond := l.parent, l.perSecond
+	l.parent = nil
+	l.mu.Unlock()
+
+	if parent == nil {
+		return
+	}
+
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+
+	parent.perSecond += perSecond
+	parent.interval = time.Duration(float64(time.Second) / parent.perSecond)
+}
+
+/*
+Wait blocks until a request may be sent, or until ctx is done, and returns how
+long it waited. A request given up gives its turn back when no other was
+scheduled after it.
+*/
+func (l *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	at := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := time.Until(at)
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return wait, nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	if l.next.Equal(at.Add(l.interval)) {
+		l.next = at
+	}
+	l.mu.Unlock()
+
+	return wait - time.Until(at), ctx.Err()
+}
@@ -0,0 +1,181 @@
+package recorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression of the archive files.
+type Compression int
+
+const (
+	None Compression = iota
+	Gzip
+	Zstd
+)
+
+// Extension returns the file name extension of c, after ".jsonl".
+func (c Compression) Extension() string {
+	switch c {
+	case Gzip:
+		return ".gz"
+	case Zstd:
+		return ".zst"
+	}
+	return ""
+}
+
+func (c Compression) String() string {
+	switch c {
+	case Gzip:
+		return "gzip"
+	case Zstd:
+		return "zstd"
+	}
+	return "none"
+}
+
+// ParseCompression reads a compression name, as returned by String.
+func ParseCompression(name string) (Compression, error) {
+	for _, c := range []Compression{None, Gzip, Zstd} {
+		if strings.EqualFold(name, c.String()) {
+			return c, nil
+		}
+	}
+	return None, fmt.Errorf("Unknown compression %s", name)
+}
+
+// dayLayout names the daily files, in UTC.
+const dayLayout = "2006-01-02"
+
+// Path returns the archive file of a pair for the day of t:
+// dir/pair/2006-01-02.jsonl.gz.
+func Path(dir, pair string, t time.Time, c Compression) string {
+	return PartPath(dir, pair, t, c, 0)
+}
+
+// PartPath returns the file of a part of the archive of a pair for the day of
+// t: Path for the first, dir/pair/2006-01-02.1.jsonl.gz for the second...
+func PartPath(dir, pair string, t time.Time, c Compression, part int) string {
+	name := t.UTC().Format(dayLayout)
+	if part > 0 {
+		name += "." + strconv.Itoa(part)
+	}
+
+	return filepath.Join(dir, pair, name+".jsonl"+c.Extension())
+}
+
+// Parts returns the files of the archive of a pair for the day of t, in the
+// order they were written.
+func Parts(dir, pair string, t time.Time, c Compression) ([]string, error) {
+	var parts []string
+
+	for part := 0; ; part++ {
+		path := PartPath(dir, pair, t, c, part)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return parts, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, path)
+	}
+}
+
+/*
+archive is an open file of records. Each archive opened for a day starts a new
+part rather than appending to the last one, which ends in a truncated stream
+when the process writing it crashed.
+*/
+type archive struct {
+	day  string
+	file *os.File
+	zw   io.WriteCloser
+	buf  *bufio.Writer
+	enc  *json.Encoder
+
+	// Last book written, for diffs.
+	book *Record
+}
+
+// openArchive creates the first part of the archive of a pair for the day of
+// t that does not exist yet.
+func openArchive(dir, pair string, t time.Time, c Compression) (*archive, error) {
+	if err := os.MkdirAll(filepath.Join(dir, pair), 0755); err != nil {
+		return nil, err
+	}
+
+	var file *os.File
+	for part := 0; file == nil; part++ {
+		var err error
+		file, err = os.OpenFile(PartPath(dir, pair, t, c, part), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil && !os.IsExist(err) {
+			return nil, err
+		}
+	}
+
+	a := &archive{file: file}
+
+	var w io.Writer = file
+	switch c {
+	case Gzip:
+		a.zw = gzip.NewWriter(file)
+		w = a.zw
+	case Zstd:
+		var err error
+		if a.zw, err = zstd.NewWriter(file); err != nil {
+			file.Close()
+			return nil, err
+		}
+		w = a.zw
+	}
+
+	a.buf = bufio.NewWriter(w)
+	a.enc = json.NewEncoder(a.buf)
+
+	return a, nil
+}
+
+func (a *archive) write(r *Record) error {
+	return a.enc.Encode(r)
+}
+
+// flush pushes buffered records to the file. The compressed stream stays
+// open, so a crash loses at most the records since the last flush.
+func (a *archive) flush() error {
+	if err := a.buf.Flush(); err != nil {
+		return err
+	}
+
+	if f, ok := a.zw.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+func (a *archive) close() error {
+	err := a.buf.Flush()
+
+	if a.zw != nil {
+		if zerr := a.zw.Close(); err == nil {
+			err = zerr
+		}
+	}
+
+	if ferr := a.file.Close(); err == nil {
+		err = ferr
+	}
+
+	return err
+}
@@ -0,0 +1,107 @@
+package recorder
+
+import (
+	"sort"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Kind of a record.
+type Kind string
+
+const (
+	KindTicker Kind = "ticker"
+	KindBook   Kind = "book"
+	KindDiff   Kind = "diff"
+	KindTrade  Kind = "trade"
+)
+
+/*
+Record is a line of an archive.
+
+A book record is a full order book. A diff record holds the levels changed
+since the previous book or diff record of the same file, a level with an
+amount of 0 being removed, as in the Poloniex push API. Each file starts with a
+book record, so it can be replayed on its own.
+*/
+type Record struct {
+	Time     time.Time           `json:"time"`
+	Kind     Kind                `json:"kind"`
+	Pair     string              `json:"pair"`
+	Seq      float64             `json:"seq,omitempty"`
+	IsFrozen int                 `json:"isFrozen,omitempty"`
+	Asks     [][2]float64        `json:"asks,omitempty"`
+	Bids     [][2]float64        `json:"bids,omitempty"`
+	Ticker   *poloniexapi.Ticker `json:"ticker,omitempty"`
+	Trade    *poloniexapi.Trade  `json:"trade,omitempty"`
+}
+
+// diffLevels returns the levels of next differing from prev, and the levels
+// of prev missing from next with an amount of 0.
+func diffLevels(prev, next [][2]float64, descending bool) [][2]float64 {
+	old := make(map[float64]float64, len(prev))
+	for _, level := range prev {
+		old[level[0]] = level[1]
+	}
+
+	out := make([][2]float64, 0)
+	for _, level := range next {
+		if amount, ok := old[level[0]]; !ok || amount != level[1] {
+			out = append(out, level)
+		}
+		delete(old, level[0])
+	}
+
+	for rate := range old {
+		out = append(out, [2]float64{rate, 0})
+	}
+
+	sortLevels(out, descending)
+
+	return out
+}
+
+// ApplyDiff returns book with the levels of a diff record applied.
+func ApplyDiff(book poloniexapi.OrderBookEntry, diff Record) poloniexapi.OrderBookEntry {
+	return poloniexapi.OrderBookEntry{
+		IsFrozen: diff.IsFrozen,
+		Seq:      diff.Seq,
+		Asks:     applyLevels(book.Asks, diff.Asks, false),
+		Bids:     applyLevels(book.Bids, diff.Bids, true),
+	}
+}
+
+func applyLevels(levels, changes [][2]float64, descending bool) [][2]float64 {
+	amounts := make(map[float64]float64, len(levels))
+	for _, level := range levels {
+		amounts[level[0]] = level[1]
+	}
+
+	for _, change := range changes {
+		if change[1] == 0 {
+			delete(amounts, change[0])
+		} else {
+			amounts[change[0]] = change[1]
+		}
+	}
+
+	out := make([][2]float64, 0, len(amounts))
+	for rate, amount := range amounts {
+		out = append(out, [2]float64{rate, amount})
+	}
+
+	sortLevels(out, descending)
+
+	return out
+}
+
+// sortLevels sorts asks by increasing rate, bids by decreasing rate.
+func sortLevels(levels [][2]float64, descending bool) {
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i][0] > levels[j][0]
+		}
+		return levels[i][0] < levels[j][0]
+	})
+}
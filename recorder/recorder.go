@@ -0,0 +1,307 @@
+/*
+Package recorder archives market data for offline research: tickers, order
+books and public trades are written as JSON Lines, one file per pair per day
+(UTC), compressed with gzip or zstd.
+
+	rec := recorder.New(api, "data", "BTC_XMR", "BTC_ETH")
+	rec.Compression = recorder.Zstd
+	err := rec.Run(ctx, func(err error) { log.Print(err) })
+
+Order books are stored as a full book at the start of each file then as diffs,
+with the sequence number of each book (OrderBookEntry.Seq) kept. A Replayer
+rebuilds them and streams them like PoloniexApi.PollOrderBook:
+
+	replayer := recorder.NewReplayer("data", "BTC_XMR")
+	replayer.Speed = 60
+	for ev := range replayer.Replay(ctx, start, end) {
+		...
+	}
+*/
+package recorder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Recorder writes market data to daily archives under Dir.
+type Recorder struct {
+	Dir         string
+	Compression Compression
+
+	// Markets whose order books and trades are polled by Run. Tickers of
+	// other markets are dropped, unless Pairs is empty.
+	Pairs []string
+
+	// Depth of the order books polled by Run.
+	Depth int
+
+	// Polling intervals used by Run, and how often archives are flushed.
+	TickerInterval time.Duration
+	BookInterval   time.Duration
+	TradesInterval time.Duration
+	FlushInterval  time.Duration
+
+	api   *poloniexapi.PoloniexApi
+	mu    sync.Mutex
+	files map[string]*archive
+}
+
+func New(api *poloniexapi.PoloniexApi, dir string, pairs ...string) *Recorder {
+	return &Recorder{
+		Dir:            dir,
+		Compression:    Gzip,
+		Pairs:          pairs,
+		Depth:          50,
+		TickerInterval: 10 * time.Second,
+		BookInterval:   2 * time.Second,
+		TradesInterval: 10 * time.Second,
+		FlushInterval:  time.Minute,
+		api:            api,
+		files:          make(map[string]*archive),
+	}
+}
+
+// archive returns the file of pair for the day of t, rotating the previous
+// one. The caller holds r.mu.
+func (r *Recorder) archive(pair string, t time.Time) (*archive, error) {
+	day := t.UTC().Format(dayLayout)
+
+	a, ok := r.files[pair]
+	if ok && a.day == day {
+		return a, nil
+	}
+
+	if ok {
+		delete(r.files, pair)
+		if err := a.close(); err != nil {
+			return nil, err
+		}
+	}
+
+	a, err := openArchive(r.Dir, pair, t, r.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	a.day = day
+	r.files[pair] = a
+
+	return a, nil
+}
+
+func (r *Recorder) wanted(pair string) bool {
+	if len(r.Pairs) == 0 {
+		return true
+	}
+
+	for _, p := range r.Pairs {
+		if p == pair {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RecordTicker writes the ticker of each recorded pair.
+func (r *Recorder) RecordTicker(ev poloniexapi.TickerEvent) error {
+	if ev.Err != nil {
+		return ev.Err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for pair, ticker := range ev.Tickers {
+		if !r.wanted(pair) {
+			continue
+		}
+
+		a, err := r.archive(pair, ev.Time)
+		if err != nil {
+			return err
+		}
+
+		ticker := ticker
+		if err = a.write(&Record{Time: ev.Time, Kind: KindTicker, Pair: pair, Ticker: &ticker}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+RecordBook writes an order book: in full if it is the first of its file,
+otherwise as a diff with the previous one. Nothing is written when the book
+did not change.
+*/
+func (r *Recorder) RecordBook(ev poloniexapi.OrderBookEvent) error {
+	if ev.Err != nil {
+		return ev.Err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, err := r.archive(ev.Pair, ev.Time)
+	if err != nil {
+		return err
+	}
+
+	book := &Record{
+		Time:     ev.Time,
+		Kind:     KindBook,
+		Pair:     ev.Pair,
+		Seq:      ev.Book.Seq,
+		IsFrozen: ev.Book.IsFrozen,
+		Asks:     ev.Book.Asks,
+		Bids:     ev.Book.Bids,
+	}
+
+	record := book
+	if a.book != nil {
+		record = &Record{
+			Time:     ev.Time,
+			Kind:     KindDiff,
+			Pair:     ev.Pair,
+			Seq:      ev.Book.Seq,
+			IsFrozen: ev.Book.IsFrozen,
+			Asks:     diffLevels(a.book.Asks, ev.Book.Asks, false),
+			Bids:     diffLevels(a.book.Bids, ev.Book.Bids, true),
+		}
+
+		if len(record.Asks) == 0 && len(record.Bids) == 0 && record.Seq == a.book.Seq && record.IsFrozen == a.book.IsFrozen {
+			return nil
+		}
+	}
+
+	if err = a.write(record); err != nil {
+		return err
+	}
+
+	a.book = book
+
+	return nil
+}
+
+// RecordTrades writes public trades, one record each.
+func (r *Recorder) RecordTrades(ev poloniexapi.TradesEvent) error {
+	if ev.Err != nil {
+		return ev.Err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range ev.Trades {
+		a, err := r.archive(ev.Pair, ev.Time)
+		if err != nil {
+			return err
+		}
+
+		if err = a.write(&Record{Time: ev.Time, Kind: KindTrade, Pair: ev.Pair, Trade: &ev.Trades[i]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush writes buffered records of all open archives to disk.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	for _, a := range r.files {
+		if ferr := a.flush(); err == nil {
+			err = ferr
+		}
+	}
+
+	return err
+}
+
+// Close flushes and closes all open archives.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	for pair, a := range r.files {
+		if cerr := a.close(); err == nil {
+			err = cerr
+		}
+		delete(r.files, pair)
+	}
+
+	return err
+}
+
+/*
+Run polls the ticker, and the order book and trades of each of Pairs, and
+records them until ctx is done. Polling and write errors are given to onError
+and do not stop recording. Archives are closed when Run returns.
+*/
+func (r *Recorder) Run(ctx context.Context, onError func(error)) error {
+	report := func(err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	consume := func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+
+	tickers := r.api.PollTicker(ctx, r.TickerInterval)
+	consume(func() {
+		for ev := range tickers {
+			report(r.RecordTicker(ev))
+		}
+	})
+
+	for _, pair := range r.Pairs {
+		books := r.api.PollOrderBook(ctx, pair, r.Depth, r.BookInterval)
+		consume(func() {
+			for ev := range books {
+				report(r.RecordBook(ev))
+			}
+		})
+
+		trades := r.api.PollTrades(ctx, pair, r.TradesInterval)
+		consume(func() {
+			for ev := range trades {
+				report(r.RecordTrades(ev))
+			}
+		})
+	}
+
+	consume(func() {
+		ticker := time.NewTicker(r.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				report(r.Flush())
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	wg.Wait()
+
+	return r.Close()
+}
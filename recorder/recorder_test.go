@@ -0,0 +1,183 @@
+package recorder
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+func TestBookDiffs(t *testing.T) {
+	dir := t.TempDir()
+	rec := New(nil, dir, "BTC_XMR")
+
+	day := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	books := []poloniexapi.OrderBookEntry{
+		{Seq: 1, Asks: [][2]float64{{0.02, 1}, {0.03, 2}}, Bids: [][2]float64{{0.01, 5}}},
+		{Seq: 2, Asks: [][2]float64{{0.02, 1}, {0.025, 3}}, Bids: [][2]float64{{0.015, 1}, {0.01, 4}}},
+		{Seq: 2, Asks: [][2]float64{{0.02, 1}, {0.025, 3}}, Bids: [][2]float64{{0.015, 1}, {0.01, 4}}},
+		{Seq: 3, Asks: [][2]float64{{0.025, 3}}, Bids: [][2]float64{{0.015, 1}, {0.01, 4}}},
+	}
+
+	for i, book := range books {
+		ev := poloniexapi.OrderBookEvent{Time: day.Add(time.Duration(i) * time.Second), Pair: "BTC_XMR", Book: book}
+		if err := rec.RecordBook(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := OpenReader(Path(dir, "BTC_XMR", day, Gzip))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	records := make([]Record, 0)
+	for {
+		r, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected a book and 2 diffs, got %d records", len(records))
+	}
+
+	if records[0].Kind != KindBook || records[1].Kind != KindDiff {
+		t.Fatalf("unexpected kinds %s, %s", records[0].Kind, records[1].Kind)
+	}
+
+	book := poloniexapi.OrderBookEntry{Seq: records[0].Seq, Asks: records[0].Asks, Bids: records[0].Bids}
+	for i, want := range []poloniexapi.OrderBookEntry{books[1], books[3]} {
+		book = ApplyDiff(book, records[i+1])
+		if !reflect.DeepEqual(book, want) {
+			t.Errorf("diff %d: got %+v, want %+v", i, book, want)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+	rec := New(nil, dir, "BTC_XMR")
+	rec.Compression = Zstd
+
+	start := time.Date(2017, 6, 1, 23, 59, 58, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		ev := poloniexapi.OrderBookEvent{
+			Time: start.Add(time.Duration(i) * time.Second),
+			Pair: "BTC_XMR",
+			Book: poloniexapi.OrderBookEntry{Seq: float64(i), Asks: [][2]float64{{0.02, float64(i + 1)}}},
+		}
+		if err := rec.RecordBook(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer := NewReplayer(dir, "BTC_XMR")
+	replayer.Compression = Zstd
+	replayer.Speed = 0
+
+	at, book, err := replayer.BookAt(start.Add(1500 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !at.Equal(start.Add(time.Second)) || book.Seq != 1 {
+		t.Errorf("expected book 1, got %v at %s", book, at)
+	}
+
+	seqs := make([]float64, 0)
+	for ev := range replayer.Replay(context.Background(), start.Add(500*time.Millisecond), start.Add(time.Hour)) {
+		if ev.Err != nil {
+			t.Fatal(ev.Err)
+		}
+		seqs = append(seqs, ev.Book.Seq)
+	}
+
+	// The day changes between the second and third book.
+	if !reflect.DeepEqual(seqs, []float64{0, 1, 2, 3}) {
+		t.Errorf("unexpected replay %v", seqs)
+	}
+}
+
+func TestReplayTruncated(t *testing.T) {
+	start := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	book := func(i int) poloniexapi.OrderBookEvent {
+		return poloniexapi.OrderBookEvent{
+			Time: start.Add(time.Duration(i) * time.Second),
+			Pair: "BTC_XMR",
+			Book: poloniexapi.OrderBookEntry{Seq: float64(i), Asks: [][2]float64{{0.02, float64(i + 1)}}},
+		}
+	}
+
+	for _, c := range []Compression{None, Gzip, Zstd} {
+		dir := t.TempDir()
+
+		// A recorder crashes after a flush, in the middle of a record.
+		rec := New(nil, dir, "BTC_XMR")
+		rec.Compression = c
+		for i := 0; i < 2; i++ {
+			if err := rec.RecordBook(book(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := rec.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		a := rec.files["BTC_XMR"]
+		a.write(&Record{Time: start.Add(2 * time.Second), Kind: KindDiff, Pair: "BTC_XMR", Seq: 2})
+		a.flush()
+		info, _ := a.file.Stat()
+		a.file.Truncate(info.Size() - 8)
+		a.file.Close()
+
+		// Restarted the same day.
+		rec = New(nil, dir, "BTC_XMR")
+		rec.Compression = c
+		for i := 3; i < 5; i++ {
+			if err := rec.RecordBook(book(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := rec.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		parts, err := Parts(dir, "BTC_XMR", start, c)
+		if err != nil || len(parts) != 2 || parts[1] != PartPath(dir, "BTC_XMR", start, c, 1) {
+			t.Fatalf("%s: got parts %v, %v", c, parts, err)
+		}
+
+		replayer := NewReplayer(dir, "BTC_XMR")
+		replayer.Compression = c
+		replayer.Speed = 0
+
+		seqs := make([]float64, 0)
+		for ev := range replayer.Replay(context.Background(), start, start.Add(time.Hour)) {
+			if ev.Err != nil {
+				t.Fatalf("%s: %v", c, ev.Err)
+			}
+			seqs = append(seqs, ev.Book.Seq)
+		}
+
+		if !reflect.DeepEqual(seqs, []float64{0, 1, 3, 4}) {
+			t.Errorf("%s: got replay %v, want the books of both parts", c, seqs)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// ErrNoBook is returned when no order book was recorded before a time.
+var ErrNoBook = errors.New("no order book recorded")
+
+// Reader reads the records of an archive file.
+type Reader struct {
+	file    *os.File
+	closer  func()
+	scanner *bufio.Scanner
+}
+
+// OpenReader opens an archive, uncompressing it according to its extension.
+func OpenReader(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reader{file: file, closer: func() {}}
+
+	var in io.Reader = file
+	switch {
+	case strings.HasSuffix(path, Gzip.Extension()):
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		in = zr
+	case strings.HasSuffix(path, Zstd.Extension()):
+		zr, err := zstd.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		in, r.closer = zr, zr.Close
+	}
+
+	r.scanner = bufio.NewScanner(in)
+	r.scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	r.scanner.Split(scanRecords)
+
+	return r, nil
+}
+
+// scanRecords splits lines like bufio.ScanLines, but fails on a last line
+// without its newline: every record is written with one.
+func scanRecords(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) > 0 && bytes.IndexByte(data, '\n') < 0 {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+
+	return bufio.ScanLines(data, atEOF)
+}
+
+// Next returns the next record, or io.EOF at the end of the file. A file
+// whose last stream was cut short, by a crash of its writer, ends with
+// io.ErrUnexpectedEOF instead.
+func (r *Reader) Next() (Record, error) {
+	var record Record
+
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return record, err
+		}
+		return record, io.EOF
+	}
+
+	err := json.Unmarshal(r.scanner.Bytes(), &record)
+
+	return record, err
+}
+
+func (r *Reader) Close() error {
+	r.closer()
+	return r.file.Close()
+}
+
+/*
+Replayer rebuilds the order book of a market from the archives written by a
+Recorder, and streams it as PollOrderBook does, so the same code can consume
+recorded and live books.
+*/
+type Replayer struct {
+	Dir         string
+	Pair        string
+	Compression Compression
+
+	// Speed of the replay: 1 is real time, 60 a minute per second. Events are
+	// sent as fast as they are consumed when Speed is 0.
+	Speed float64
+}
+
+func NewReplayer(dir, pair string) *Replayer {
+	return &Replayer{Dir: dir, Pair: pair, Compression: Gzip, Speed: 1}
+}
+
+/*
+scan reads the book records of the daily files from the day of start to the
+day of end, calling fn with the book after each record up to end. A day
+without a file is skipped, and the parts of a day are read in order. fn
+returns false to stop.
+*/
+func (r *Replayer) scan(start, end time.Time, fn func(t time.Time, book poloniexapi.OrderBookEntry) bool) error {
+	first := start.UTC().Truncate(24 * time.Hour)
+
+	for day := first; !day.After(end); day = day.AddDate(0, 0, 1) {
+		parts, err := Parts(r.Dir, r.Pair, day, r.Compression)
+		if err != nil {
+			return err
+		}
+
+		for _, path := range parts {
+			more, err := r.scanPart(path, end, fn)
+			if err != nil || !more {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// scanPart reads a file for scan. Each part starts with a full book, and the
+// records of a part cut short are read up to where it was cut. It returns
+// false when fn stopped or end was reached.
+func (r *Replayer) scanPart(path string, end time.Time, fn func(t time.Time, book poloniexapi.OrderBookEntry) bool) (bool, error) {
+	reader, err := OpenReader(path)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+
+	var book *poloniexapi.OrderBookEntry
+	for {
+		record, err := reader.Next()
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if record.Time.After(end) {
+			return false, nil
+		}
+
+		switch {
+		case record.Kind == KindBook:
+			book = &poloniexapi.OrderBookEntry{
+				IsFrozen: record.IsFrozen,
+				Seq:      record.Seq,
+				Asks:     record.Asks,
+				Bids:     record.Bids,
+			}
+		case record.Kind == KindDiff && book != nil:
+			next := ApplyDiff(*book, record)
+			book = &next
+		default:
+			continue
+		}
+
+		if !fn(record.Time, *book) {
+			return false, nil
+		}
+	}
+}
+
+// BookAt returns the order book as recorded at t, with the time it was
+// recorded. Only the file of the day of t is read.
+func (r *Replayer) BookAt(t time.Time) (time.Time, poloniexapi.OrderBookEntry, error) {
+	var at time.Time
+	var book *poloniexapi.OrderBookEntry
+
+	err := r.scan(t, t, func(recorded time.Time, b poloniexapi.OrderBookEntry) bool {
+		at, book = recorded, &b
+		return true
+	})
+	if err != nil {
+		return at, poloniexapi.OrderBookEntry{}, err
+	}
+
+	if book == nil {
+		return at, poloniexapi.OrderBookEntry{}, ErrNoBook
+	}
+
+	return at, *book, nil
+}
+
+/*
+Replay sends the order books recorded between start and end on the returned
+channel, paced by Speed, until the end of the data or ctx is done. The first
+event is the book as it was at start, if any. An error reading the archives is
+sent as the last event.
+*/
+func (r *Replayer) Replay(ctx context.Context, start, end time.Time) <-chan poloniexapi.OrderBookEvent {
+	out := make(chan poloniexapi.OrderBookEvent)
+
+	go func() {
+		defer close(out)
+
+		var pending *poloniexapi.OrderBookEvent
+		var last time.Time
+
+		send := func(ev poloniexapi.OrderBookEvent) bool {
+			if !last.IsZero() && r.Speed > 0 {
+				wait := time.Duration(float64(ev.Time.Sub(last)) / r.Speed)
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return false
+				}
+			}
+			last = ev.Time
+
+			select {
+			case out <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		err := r.scan(start, end, func(t time.Time, book poloniexapi.OrderBookEntry) bool {
+			ev := poloniexapi.OrderBookEvent{Time: t, Pair: r.Pair, Book: book}
+
+			// Books before start only build the state sent at start.
+			if t.Before(start) {
+				ev.Time = start
+				pending = &ev
+				return true
+			}
+
+			if pending != nil {
+				p := *pending
+				pending = nil
+				if !send(p) {
+					return false
+				}
+			}
+
+			return send(ev)
+		})
+
+		if err == nil && pending != nil {
+			send(*pending)
+		}
+
+		if err != nil && ctx.Err() == nil {
+			select {
+			case out <- poloniexapi.OrderBookEvent{Time: time.Now(), Pair: r.Pair, Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,105 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mycroft/poloniex-api/gateway"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Scopes needed by the methods of the service, read for those missing.
+var methodScopes = map[string]gateway.Scope{
+	Poloniex_PlaceOrder_FullMethodName:  gateway.ScopeTrade,
+	Poloniex_CancelOrder_FullMethodName: gateway.ScopeTrade,
+	Poloniex_StreamFills_FullMethodName: gateway.ScopeTrade,
+}
+
+/*
+Auth checks the token of every call against tokens of the gateway format (see
+gateway.LoadTokens), sent as "authorization: Bearer <token>" metadata:
+
+	auth, err := rpc.NewAuth(tokens)
+	server := grpc.NewServer(grpc.UnaryInterceptor(auth.Unary), grpc.StreamInterceptor(auth.Stream))
+
+Calls without a known token fail with Unauthenticated. PlaceOrder,
+CancelOrder and StreamFills need the trade scope, and fail with
+PermissionDenied for read tokens.
+*/
+type Auth struct {
+	tokens []gateway.Token
+}
+
+// NewAuth checks tokens as gateway.NewServer does.
+func NewAuth(tokens []gateway.Token) (*Auth, error) {
+	if err := gateway.CheckTokens(tokens); err != nil {
+		return nil, err
+	}
+
+	return &Auth{tokens: tokens}, nil
+}
+
+// Unary is a grpc.UnaryServerInterceptor.
+func (a *Auth) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// Stream is a grpc.StreamServerInterceptor.
+func (a *Auth) Stream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
+
+func (a *Auth) authorize(ctx context.Context, method string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var given string
+	if values := md.Get("authorization"); len(values) == 1 {
+		given, _ = strings.CutPrefix(values[0], "Bearer ")
+	}
+
+	token, ok := gateway.FindToken(a.tokens, given)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing or invalid token")
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok {
+		scope = gateway.ScopeRead
+	}
+
+	if !token.Scope.Allows(scope) {
+		return status.Errorf(codes.PermissionDenied, "token of %s lacks the %s scope", token.Name, scope)
+	}
+
+	return nil
+}
+
+/*
+WithToken sends token with every call of a client. It is sent over plain
+connections too, for servers on the loopback; use transport credentials
+otherwise.
+*/
+func WithToken(token string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(tokenCredentials(token))
+}
+
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
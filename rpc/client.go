@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"google.golang.org/grpc"
+)
+
+/*
+Client is a PoloniexClient returning the types of package poloniexapi. The
+Poll methods send the streams on channels, as the methods of the same name of
+PoloniexApi, so both can be used in place of the other.
+*/
+type Client struct {
+	PoloniexClient
+
+	conn *grpc.ClientConn
+}
+
+// Dial connects to a server at target, see grpc.NewClient.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{PoloniexClient: NewPoloniexClient(conn), conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Ticker returns the tickers of pairs, or of all markets when none is given.
+func (c *Client) Ticker(ctx context.Context, pairs ...string) (map[string]poloniexapi.Ticker, error) {
+	resp, err := c.GetTicker(ctx, &GetTickerRequest{Pairs: pairs})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromTickers(resp.GetTickers()), nil
+}
+
+func (c *Client) OrderBook(ctx context.Context, pair string, depth int) (poloniexapi.OrderBookEntry, error) {
+	book, err := c.GetOrderBook(ctx, &GetOrderBookRequest{Pair: pair, Depth: int32(depth)})
+	if err != nil {
+		return poloniexapi.OrderBookEntry{}, err
+	}
+
+	return FromOrderBook(book), nil
+}
+
+func (c *Client) TradeHistory(ctx context.Context, pair string, start, end int) ([]poloniexapi.Trade, error) {
+	resp, err := c.GetTradeHistory(ctx, &GetTradeHistoryRequest{Pair: pair, Start: int64(start), End: int64(end)})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromTrades(resp.GetTrades()), nil
+}
+
+func (c *Client) Balances(ctx context.Context) (map[string]poloniexapi.Balance, error) {
+	resp, err := c.GetBalances(ctx, &GetBalancesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]poloniexapi.Balance, len(resp.GetBalances()))
+	for currency, b := range resp.GetBalances() {
+		out[currency] = FromBalance(b)
+	}
+
+	return out, nil
+}
+
+// OpenOrders returns the open orders by market, pair may be "all".
+func (c *Client) OpenOrders(ctx context.Context, pair string) (map[string][]poloniexapi.OpenOrder, error) {
+	resp, err := c.GetOpenOrders(ctx, &GetOpenOrdersRequest{Pair: pair})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]poloniexapi.OpenOrder)
+	for _, o := range resp.GetOrders() {
+		out[o.GetPair()] = append(out[o.GetPair()], FromOpenOrder(o))
+	}
+
+	return out, nil
+}
+
+func (c *Client) FeeInfo(ctx context.Context) (poloniexapi.FeeInfo, error) {
+	info, err := c.GetFeeInfo(ctx, &GetFeeInfoRequest{})
+	if err != nil {
+		return poloniexapi.FeeInfo{}, err
+	}
+
+	return FromFeeInfo(info), nil
+}
+
+// PlaceOrder takes the arguments of PoloniexApi.PlaceOrder, opts may be nil.
+func (c *Client) PlaceOrder(ctx context.Context, side poloniexapi.OrderSide, pair string, rate, amount float64, opts *poloniexapi.OrderOptions) (*poloniexapi.Order, error) {
+	req := &PlaceOrderRequest{Pair: pair, Rate: rate, Amount: amount}
+
+	switch side {
+	case poloniexapi.SideBuy:
+		req.Side = Side_SIDE_BUY
+	case poloniexapi.SideSell:
+		req.Side = Side_SIDE_SELL
+	}
+
+	if opts != nil {
+		req.TimeInForce = TimeInForce(opts.TimeInForce)
+		req.PostOnly = opts.PostOnly
+		req.ClientOrderId = opts.ClientOrderID
+	}
+
+	order, err := c.PoloniexClient.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := FromOrder(order)
+	return &out, nil
+}
+
+func (c *Client) Cancel(ctx context.Context, orderNumber int64) (bool, error) {
+	resp, err := c.CancelOrder(ctx, &CancelOrderRequest{OrderNumber: orderNumber})
+	if err != nil {
+		return false, err
+	}
+
+	return resp.GetSuccess(), nil
+}
+
+/*
+PollTicker streams the tickers of all markets every interval, until ctx is
+done or the stream fails. An error is sent in the Err field of the last
+event.
+*/
+func (c *Client) PollTicker(ctx context.Context, interval time.Duration) <-chan poloniexapi.TickerEvent {
+	out := make(chan poloniexapi.TickerEvent)
+
+	go func() {
+		defer close(out)
+
+		stream, err := c.StreamTickers(ctx, &StreamTickersRequest{IntervalMs: interval.Milliseconds()})
+		for err == nil {
+			var update *TickerUpdate
+			if update, err = stream.Recv(); err != nil {
+				break
+			}
+
+			ev := poloniexapi.TickerEvent{Time: update.GetTime().AsTime(), Tickers: fromTickers(update.GetTickers())}
+			if !sendEvent(ctx, out, ev) {
+				return
+			}
+		}
+
+		if ctx.Err() == nil {
+			sendEvent(ctx, out, poloniexapi.TickerEvent{Time: time.Now(), Err: err})
+		}
+	}()
+
+	return out
+}
+
+// PollOrderBook streams the order book of pair, as PollTicker.
+func (c *Client) PollOrderBook(ctx context.Context, pair string, depth int, interval time.Duration) <-chan poloniexapi.OrderBookEvent {
+	out := make(chan poloniexapi.OrderBookEvent)
+
+	go func() {
+		defer close(out)
+
+		req := &StreamOrderBookRequest{Pair: pair, Depth: int32(depth), IntervalMs: interval.Milliseconds()}
+		stream, err := c.StreamOrderBook(ctx, req)
+		for err == nil {
+			var book *OrderBook
+			if book, err = stream.Recv(); err != nil {
+				break
+			}
+
+			ev := poloniexapi.OrderBookEvent{Time: book.GetTime().AsTime(), Pair: book.GetPair(), Book: FromOrderBook(book)}
+			if !sendEvent(ctx, out, ev) {
+				return
+			}
+		}
+
+		if ctx.Err() == nil {
+			sendEvent(ctx, out, poloniexapi.OrderBookEvent{Time: time.Now(), Pair: pair, Err: err})
+		}
+	}()
+
+	return out
+}
+
+// PollPrivateTrades streams your trades on pair, which may be "all", as
+// PollTicker. Each event holds a single trade.
+func (c *Client) PollPrivateTrades(ctx context.Context, pair string, interval time.Duration) <-chan poloniexapi.TradesEvent {
+	out := make(chan poloniexapi.TradesEvent)
+
+	go func() {
+		defer close(out)
+
+		stream, err := c.StreamFills(ctx, &StreamFillsRequest{Pair: pair, IntervalMs: interval.Milliseconds()})
+		for err == nil {
+			var fill *Fill
+			if fill, err = stream.Recv(); err != nil {
+				break
+			}
+
+			ev := poloniexapi.TradesEvent{
+				Time:   fill.GetTime().AsTime(),
+				Pair:   fill.GetPair(),
+				Trades: []poloniexapi.Trade{FromTrade(fill.GetTrade())},
+			}
+			if !sendEvent(ctx, out, ev) {
+				return
+			}
+		}
+
+		if ctx.Err() == nil {
+			sendEvent(ctx, out, poloniexapi.TradesEvent{Time: time.Now(), Pair: pair, Err: err})
+		}
+	}()
+
+	return out
+}
+
+func sendEvent[T any](ctx context.Context, out chan<- T, ev T) bool {
+	select {
+	case out <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
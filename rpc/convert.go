@@ -0,0 +1,223 @@
+package rpc
+
+import (
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Conversions between the poloniexapi types and their messages.
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func ToTicker(t poloniexapi.Ticker) *Ticker {
+	return &Ticker{
+		Id:            t.Id,
+		Last:          t.Last,
+		LowestAsk:     t.LowestAsk,
+		HighestBid:    t.HighestBid,
+		PercentChange: t.PercentChange,
+		BaseVolume:    t.BaseVolume,
+		QuoteVolume:   t.QuoteVolume,
+		IsFrozen:      t.IsFrozen != 0,
+		High24Hr:      t.High24hr,
+		Low24Hr:       t.Low24hr,
+	}
+}
+
+func FromTicker(t *Ticker) poloniexapi.Ticker {
+	return poloniexapi.Ticker{
+		Id:            t.GetId(),
+		Last:          t.GetLast(),
+		LowestAsk:     t.GetLowestAsk(),
+		HighestBid:    t.GetHighestBid(),
+		PercentChange: t.GetPercentChange(),
+		BaseVolume:    t.GetBaseVolume(),
+		QuoteVolume:   t.GetQuoteVolume(),
+		IsFrozen:      boolInt(t.GetIsFrozen()),
+		High24hr:      t.GetHigh24Hr(),
+		Low24hr:       t.GetLow24Hr(),
+	}
+}
+
+func toTickers(in map[string]poloniexapi.Ticker, pairs []string) map[string]*Ticker {
+	out := make(map[string]*Ticker)
+
+	if len(pairs) == 0 {
+		for pair, t := range in {
+			out[pair] = ToTicker(t)
+		}
+		return out
+	}
+
+	for _, pair := range pairs {
+		if t, ok := in[pair]; ok {
+			out[pair] = ToTicker(t)
+		}
+	}
+
+	return out
+}
+
+func fromTickers(in map[string]*Ticker) map[string]poloniexapi.Ticker {
+	out := make(map[string]poloniexapi.Ticker, len(in))
+	for pair, t := range in {
+		out[pair] = FromTicker(t)
+	}
+	return out
+}
+
+func toLevels(in [][2]float64) []*Level {
+	out := make([]*Level, 0, len(in))
+	for _, l := range in {
+		out = append(out, &Level{Rate: l[0], Amount: l[1]})
+	}
+	return out
+}
+
+func fromLevels(in []*Level) [][2]float64 {
+	out := make([][2]float64, 0, len(in))
+	for _, l := range in {
+		out = append(out, [2]float64{l.GetRate(), l.GetAmount()})
+	}
+	return out
+}
+
+func ToOrderBook(pair string, b poloniexapi.OrderBookEntry) *OrderBook {
+	return &OrderBook{
+		Pair:     pair,
+		Seq:      int64(b.Seq),
+		IsFrozen: b.IsFrozen != 0,
+		Asks:     toLevels(b.Asks),
+		Bids:     toLevels(b.Bids),
+	}
+}
+
+func FromOrderBook(b *OrderBook) poloniexapi.OrderBookEntry {
+	return poloniexapi.OrderBookEntry{
+		IsFrozen: boolInt(b.GetIsFrozen()),
+		Seq:      float64(b.GetSeq()),
+		Asks:     fromLevels(b.GetAsks()),
+		Bids:     fromLevels(b.GetBids()),
+	}
+}
+
+func ToTrade(t poloniexapi.Trade) *Trade {
+	return &Trade{
+		GlobalTradeId: t.GlobalTradeID,
+		TradeId:       t.TradeID,
+		Date:          t.Date,
+		Type:          t.Type,
+		Rate:          t.Rate,
+		Amount:        t.Amount,
+		Total:         t.Total,
+		Fee:           t.Fee,
+		OrderNumber:   t.OrderNumber,
+		Category:      t.Category,
+		ClientOrderId: t.ClientOrderId,
+	}
+}
+
+func FromTrade(t *Trade) poloniexapi.Trade {
+	return poloniexapi.Trade{
+		GlobalTradeID: t.GetGlobalTradeId(),
+		TradeID:       t.GetTradeId(),
+		Date:          t.GetDate(),
+		Type:          t.GetType(),
+		Rate:          t.GetRate(),
+		Amount:        t.GetAmount(),
+		Total:         t.GetTotal(),
+		Fee:           t.GetFee(),
+		OrderNumber:   t.GetOrderNumber(),
+		Category:      t.GetCategory(),
+		ClientOrderId: t.GetClientOrderId(),
+	}
+}
+
+func toTrades(in []poloniexapi.Trade) []*Trade {
+	out := make([]*Trade, 0, len(in))
+	for _, t := range in {
+		out = append(out, ToTrade(t))
+	}
+	return out
+}
+
+func fromTrades(in []*Trade) []poloniexapi.Trade {
+	out := make([]poloniexapi.Trade, 0, len(in))
+	for _, t := range in {
+		out = append(out, FromTrade(t))
+	}
+	return out
+}
+
+func ToOpenOrder(pair string, o poloniexapi.OpenOrder) *OpenOrder {
+	return &OpenOrder{
+		OrderNumber:    o.OrderNumber,
+		Pair:           pair,
+		Type:           o.Type,
+		Rate:           o.Rate,
+		StartingAmount: o.StartingAmount,
+		Amount:         o.Amount,
+		Total:          o.Total,
+		Date:           o.Date,
+		Margin:         o.Margin != 0,
+		ClientOrderId:  o.ClientOrderId,
+	}
+}
+
+func FromOpenOrder(o *OpenOrder) poloniexapi.OpenOrder {
+	return poloniexapi.OpenOrder{
+		OrderNumber:    o.GetOrderNumber(),
+		Type:           o.GetType(),
+		Rate:           o.GetRate(),
+		StartingAmount: o.GetStartingAmount(),
+		Amount:         o.GetAmount(),
+		Total:          o.GetTotal(),
+		Date:           o.GetDate(),
+		Margin:         int64(boolInt(o.GetMargin())),
+		ClientOrderId:  o.GetClientOrderId(),
+	}
+}
+
+func ToBalance(b poloniexapi.Balance) *Balance {
+	return &Balance{Available: b.Available, OnOrders: b.OnOrders, BtcValue: b.BtcValue}
+}
+
+func FromBalance(b *Balance) poloniexapi.Balance {
+	return poloniexapi.Balance{Available: b.GetAvailable(), OnOrders: b.GetOnOrders(), BtcValue: b.GetBtcValue()}
+}
+
+func ToFeeInfo(f poloniexapi.FeeInfo) *FeeInfo {
+	return &FeeInfo{MakerFee: f.MakerFee, TakerFee: f.TakerFee, ThirtyDayVolume: f.ThirtyDayVolume, NextTier: f.NextTier}
+}
+
+func FromFeeInfo(f *FeeInfo) poloniexapi.FeeInfo {
+	return poloniexapi.FeeInfo{
+		MakerFee:        f.GetMakerFee(),
+		TakerFee:        f.GetTakerFee(),
+		ThirtyDayVolume: f.GetThirtyDayVolume(),
+		NextTier:        f.GetNextTier(),
+	}
+}
+
+// ToOrder keeps the resulting trades of pair, the only market of an order.
+func ToOrder(pair string, o poloniexapi.Order) *Order {
+	return &Order{
+		OrderNumber:     o.OrderNumber,
+		ClientOrderId:   o.ClientOrderId,
+		Pair:            pair,
+		ResultingTrades: toTrades(o.ResultingTrades[pair]),
+	}
+}
+
+func FromOrder(o *Order) poloniexapi.Order {
+	return poloniexapi.Order{
+		Success:         1,
+		OrderNumber:     o.GetOrderNumber(),
+		ClientOrderId:   o.GetClientOrderId(),
+		ResultingTrades: map[string][]poloniexapi.Trade{o.GetPair(): fromTrades(o.GetResultingTrades())},
+	}
+}
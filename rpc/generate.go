@@ -0,0 +1,23 @@
+/*
+Package rpc serves a PoloniexApi over gRPC, and provides a Go client for it.
+The service and its messages are defined in poloniex.proto; they mirror the
+types of package poloniexapi.
+
+	auth, err := rpc.NewAuth(tokens)
+	server := grpc.NewServer(grpc.UnaryInterceptor(auth.Unary), grpc.StreamInterceptor(auth.Stream))
+	rpc.RegisterPoloniexServer(server, rpc.NewServer(api))
+	server.Serve(listener)
+
+	client, err := rpc.Dial("localhost:8471", grpc.WithTransportCredentials(insecure.NewCredentials()), rpc.WithToken(token))
+	tickers, err := client.Ticker(ctx)
+	for ev := range client.PollTicker(ctx, 10*time.Second) {
+		...
+	}
+
+The streaming RPCs are polled by the server: StreamTickers, StreamOrderBook,
+and StreamFills for the trades of your orders. Clients are authenticated with
+the tokens of package gateway, see Auth.
+*/
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative poloniex.proto
@@ -0,0 +1,2011 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: poloniex.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Side int32
+
+const (
+	Side_SIDE_UNSPECIFIED Side = 0
+	Side_SIDE_BUY         Side = 1
+	Side_SIDE_SELL        Side = 2
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "SIDE_UNSPECIFIED",
+		1: "SIDE_BUY",
+		2: "SIDE_SELL",
+	}
+	Side_value = map[string]int32{
+		"SIDE_UNSPECIFIED": 0,
+		"SIDE_BUY":         1,
+		"SIDE_SELL":        2,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_poloniex_proto_enumTypes[0].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_poloniex_proto_enumTypes[0]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{0}
+}
+
+type TimeInForce int32
+
+const (
+	TimeInForce_TIME_IN_FORCE_GTC TimeInForce = 0
+	TimeInForce_TIME_IN_FORCE_FOK TimeInForce = 1
+	TimeInForce_TIME_IN_FORCE_IOC TimeInForce = 2
+)
+
+// Enum value maps for TimeInForce.
+var (
+	TimeInForce_name = map[int32]string{
+		0: "TIME_IN_FORCE_GTC",
+		1: "TIME_IN_FORCE_FOK",
+		2: "TIME_IN_FORCE_IOC",
+	}
+	TimeInForce_value = map[string]int32{
+		"TIME_IN_FORCE_GTC": 0,
+		"TIME_IN_FORCE_FOK": 1,
+		"TIME_IN_FORCE_IOC": 2,
+	}
+)
+
+func (x TimeInForce) Enum() *TimeInForce {
+	p := new(TimeInForce)
+	*p = x
+	return p
+}
+
+func (x TimeInForce) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TimeInForce) Descriptor() protoreflect.EnumDescriptor {
+	return file_poloniex_proto_enumTypes[1].Descriptor()
+}
+
+func (TimeInForce) Type() protoreflect.EnumType {
+	return &file_poloniex_proto_enumTypes[1]
+}
+
+func (x TimeInForce) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TimeInForce.Descriptor instead.
+func (TimeInForce) EnumDescriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{1}
+}
+
+type Ticker struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Last          float64                `protobuf:"fixed64,2,opt,name=last,proto3" json:"last,omitempty"`
+	LowestAsk     float64                `protobuf:"fixed64,3,opt,name=lowest_ask,json=lowestAsk,proto3" json:"lowest_ask,omitempty"`
+	HighestBid    float64                `protobuf:"fixed64,4,opt,name=highest_bid,json=highestBid,proto3" json:"highest_bid,omitempty"`
+	PercentChange float64                `protobuf:"fixed64,5,opt,name=percent_change,json=percentChange,proto3" json:"percent_change,omitempty"`
+	BaseVolume    float64                `protobuf:"fixed64,6,opt,name=base_volume,json=baseVolume,proto3" json:"base_volume,omitempty"`
+	QuoteVolume   float64                `protobuf:"fixed64,7,opt,name=quote_volume,json=quoteVolume,proto3" json:"quote_volume,omitempty"`
+	IsFrozen      bool                   `protobuf:"varint,8,opt,name=is_frozen,json=isFrozen,proto3" json:"is_frozen,omitempty"`
+	High24Hr      float64                `protobuf:"fixed64,9,opt,name=high24hr,proto3" json:"high24hr,omitempty"`
+	Low24Hr       float64                `protobuf:"fixed64,10,opt,name=low24hr,proto3" json:"low24hr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ticker) Reset() {
+	*x = Ticker{}
+	mi := &file_poloniex_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticker) ProtoMessage() {}
+
+func (x *Ticker) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticker.ProtoReflect.Descriptor instead.
+func (*Ticker) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Ticker) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Ticker) GetLast() float64 {
+	if x != nil {
+		return x.Last
+	}
+	return 0
+}
+
+func (x *Ticker) GetLowestAsk() float64 {
+	if x != nil {
+		return x.LowestAsk
+	}
+	return 0
+}
+
+func (x *Ticker) GetHighestBid() float64 {
+	if x != nil {
+		return x.HighestBid
+	}
+	return 0
+}
+
+func (x *Ticker) GetPercentChange() float64 {
+	if x != nil {
+		return x.PercentChange
+	}
+	return 0
+}
+
+func (x *Ticker) GetBaseVolume() float64 {
+	if x != nil {
+		return x.BaseVolume
+	}
+	return 0
+}
+
+func (x *Ticker) GetQuoteVolume() float64 {
+	if x != nil {
+		return x.QuoteVolume
+	}
+	return 0
+}
+
+func (x *Ticker) GetIsFrozen() bool {
+	if x != nil {
+		return x.IsFrozen
+	}
+	return false
+}
+
+func (x *Ticker) GetHigh24Hr() float64 {
+	if x != nil {
+		return x.High24Hr
+	}
+	return 0
+}
+
+func (x *Ticker) GetLow24Hr() float64 {
+	if x != nil {
+		return x.Low24Hr
+	}
+	return 0
+}
+
+type Level struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          float64                `protobuf:"fixed64,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Level) Reset() {
+	*x = Level{}
+	mi := &file_poloniex_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Level) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Level) ProtoMessage() {}
+
+func (x *Level) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Level.ProtoReflect.Descriptor instead.
+func (*Level) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Level) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *Level) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type OrderBook struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Pair     string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Seq      int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	IsFrozen bool                   `protobuf:"varint,3,opt,name=is_frozen,json=isFrozen,proto3" json:"is_frozen,omitempty"`
+	Asks     []*Level               `protobuf:"bytes,4,rep,name=asks,proto3" json:"asks,omitempty"`
+	Bids     []*Level               `protobuf:"bytes,5,rep,name=bids,proto3" json:"bids,omitempty"`
+	// When the book was fetched.
+	Time          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBook) Reset() {
+	*x = OrderBook{}
+	mi := &file_poloniex_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBook) ProtoMessage() {}
+
+func (x *OrderBook) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBook.ProtoReflect.Descriptor instead.
+func (*OrderBook) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderBook) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *OrderBook) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *OrderBook) GetIsFrozen() bool {
+	if x != nil {
+		return x.IsFrozen
+	}
+	return false
+}
+
+func (x *OrderBook) GetAsks() []*Level {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *OrderBook) GetBids() []*Level {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *OrderBook) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type Trade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GlobalTradeId int64                  `protobuf:"varint,1,opt,name=global_trade_id,json=globalTradeId,proto3" json:"global_trade_id,omitempty"`
+	TradeId       int64                  `protobuf:"varint,2,opt,name=trade_id,json=tradeId,proto3" json:"trade_id,omitempty"`
+	Date          string                 `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"` // 2006-01-02 15:04:05, UTC
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Rate          float64                `protobuf:"fixed64,5,opt,name=rate,proto3" json:"rate,omitempty"`
+	Amount        float64                `protobuf:"fixed64,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Total         float64                `protobuf:"fixed64,7,opt,name=total,proto3" json:"total,omitempty"`
+	Fee           float64                `protobuf:"fixed64,8,opt,name=fee,proto3" json:"fee,omitempty"`
+	OrderNumber   int64                  `protobuf:"varint,9,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
+	Category      string                 `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
+	ClientOrderId int64                  `protobuf:"varint,11,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trade) Reset() {
+	*x = Trade{}
+	mi := &file_poloniex_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trade) ProtoMessage() {}
+
+func (x *Trade) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trade.ProtoReflect.Descriptor instead.
+func (*Trade) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Trade) GetGlobalTradeId() int64 {
+	if x != nil {
+		return x.GlobalTradeId
+	}
+	return 0
+}
+
+func (x *Trade) GetTradeId() int64 {
+	if x != nil {
+		return x.TradeId
+	}
+	return 0
+}
+
+func (x *Trade) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Trade) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Trade) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *Trade) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Trade) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Trade) GetFee() float64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Trade) GetOrderNumber() int64 {
+	if x != nil {
+		return x.OrderNumber
+	}
+	return 0
+}
+
+func (x *Trade) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Trade) GetClientOrderId() int64 {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return 0
+}
+
+type OpenOrder struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrderNumber    string                 `protobuf:"bytes,1,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
+	Pair           string                 `protobuf:"bytes,2,opt,name=pair,proto3" json:"pair,omitempty"`
+	Type           string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Rate           float64                `protobuf:"fixed64,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	StartingAmount float64                `protobuf:"fixed64,5,opt,name=starting_amount,json=startingAmount,proto3" json:"starting_amount,omitempty"`
+	Amount         float64                `protobuf:"fixed64,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Total          float64                `protobuf:"fixed64,7,opt,name=total,proto3" json:"total,omitempty"`
+	Date           string                 `protobuf:"bytes,8,opt,name=date,proto3" json:"date,omitempty"`
+	Margin         bool                   `protobuf:"varint,9,opt,name=margin,proto3" json:"margin,omitempty"`
+	ClientOrderId  int64                  `protobuf:"varint,10,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OpenOrder) Reset() {
+	*x = OpenOrder{}
+	mi := &file_poloniex_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenOrder) ProtoMessage() {}
+
+func (x *OpenOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenOrder.ProtoReflect.Descriptor instead.
+func (*OpenOrder) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OpenOrder) GetOrderNumber() string {
+	if x != nil {
+		return x.OrderNumber
+	}
+	return ""
+}
+
+func (x *OpenOrder) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *OpenOrder) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *OpenOrder) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *OpenOrder) GetStartingAmount() float64 {
+	if x != nil {
+		return x.StartingAmount
+	}
+	return 0
+}
+
+func (x *OpenOrder) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OpenOrder) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *OpenOrder) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *OpenOrder) GetMargin() bool {
+	if x != nil {
+		return x.Margin
+	}
+	return false
+}
+
+func (x *OpenOrder) GetClientOrderId() int64 {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return 0
+}
+
+type Order struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	OrderNumber     int64                  `protobuf:"varint,1,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
+	ClientOrderId   int64                  `protobuf:"varint,2,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	Pair            string                 `protobuf:"bytes,3,opt,name=pair,proto3" json:"pair,omitempty"`
+	ResultingTrades []*Trade               `protobuf:"bytes,4,rep,name=resulting_trades,json=resultingTrades,proto3" json:"resulting_trades,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_poloniex_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Order) GetOrderNumber() int64 {
+	if x != nil {
+		return x.OrderNumber
+	}
+	return 0
+}
+
+func (x *Order) GetClientOrderId() int64 {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return 0
+}
+
+func (x *Order) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *Order) GetResultingTrades() []*Trade {
+	if x != nil {
+		return x.ResultingTrades
+	}
+	return nil
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Available     float64                `protobuf:"fixed64,1,opt,name=available,proto3" json:"available,omitempty"`
+	OnOrders      float64                `protobuf:"fixed64,2,opt,name=on_orders,json=onOrders,proto3" json:"on_orders,omitempty"`
+	BtcValue      float64                `protobuf:"fixed64,3,opt,name=btc_value,json=btcValue,proto3" json:"btc_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_poloniex_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Balance) GetAvailable() float64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *Balance) GetOnOrders() float64 {
+	if x != nil {
+		return x.OnOrders
+	}
+	return 0
+}
+
+func (x *Balance) GetBtcValue() float64 {
+	if x != nil {
+		return x.BtcValue
+	}
+	return 0
+}
+
+type FeeInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MakerFee        float64                `protobuf:"fixed64,1,opt,name=maker_fee,json=makerFee,proto3" json:"maker_fee,omitempty"`
+	TakerFee        float64                `protobuf:"fixed64,2,opt,name=taker_fee,json=takerFee,proto3" json:"taker_fee,omitempty"`
+	ThirtyDayVolume float64                `protobuf:"fixed64,3,opt,name=thirty_day_volume,json=thirtyDayVolume,proto3" json:"thirty_day_volume,omitempty"`
+	NextTier        float64                `protobuf:"fixed64,4,opt,name=next_tier,json=nextTier,proto3" json:"next_tier,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FeeInfo) Reset() {
+	*x = FeeInfo{}
+	mi := &file_poloniex_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeeInfo) ProtoMessage() {}
+
+func (x *FeeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeeInfo.ProtoReflect.Descriptor instead.
+func (*FeeInfo) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FeeInfo) GetMakerFee() float64 {
+	if x != nil {
+		return x.MakerFee
+	}
+	return 0
+}
+
+func (x *FeeInfo) GetTakerFee() float64 {
+	if x != nil {
+		return x.TakerFee
+	}
+	return 0
+}
+
+func (x *FeeInfo) GetThirtyDayVolume() float64 {
+	if x != nil {
+		return x.ThirtyDayVolume
+	}
+	return 0
+}
+
+func (x *FeeInfo) GetNextTier() float64 {
+	if x != nil {
+		return x.NextTier
+	}
+	return 0
+}
+
+type GetTickerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All markets when empty.
+	Pairs         []string `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTickerRequest) Reset() {
+	*x = GetTickerRequest{}
+	mi := &file_poloniex_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTickerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTickerRequest) ProtoMessage() {}
+
+func (x *GetTickerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTickerRequest.ProtoReflect.Descriptor instead.
+func (*GetTickerRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTickerRequest) GetPairs() []string {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+type GetTickerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tickers       map[string]*Ticker     `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTickerResponse) Reset() {
+	*x = GetTickerResponse{}
+	mi := &file_poloniex_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTickerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTickerResponse) ProtoMessage() {}
+
+func (x *GetTickerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTickerResponse.ProtoReflect.Descriptor instead.
+func (*GetTickerResponse) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTickerResponse) GetTickers() map[string]*Ticker {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+type GetOrderBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pair          string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderBookRequest) Reset() {
+	*x = GetOrderBookRequest{}
+	mi := &file_poloniex_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderBookRequest) ProtoMessage() {}
+
+func (x *GetOrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderBookRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrderBookRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *GetOrderBookRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type GetTradeHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pair          string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Start         int64                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"` // UNIX timestamps, the last trades when 0
+	End           int64                  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradeHistoryRequest) Reset() {
+	*x = GetTradeHistoryRequest{}
+	mi := &file_poloniex_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradeHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradeHistoryRequest) ProtoMessage() {}
+
+func (x *GetTradeHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradeHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetTradeHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetTradeHistoryRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *GetTradeHistoryRequest) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *GetTradeHistoryRequest) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type GetTradeHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trades        []*Trade               `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradeHistoryResponse) Reset() {
+	*x = GetTradeHistoryResponse{}
+	mi := &file_poloniex_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradeHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradeHistoryResponse) ProtoMessage() {}
+
+func (x *GetTradeHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradeHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetTradeHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetTradeHistoryResponse) GetTrades() []*Trade {
+	if x != nil {
+		return x.Trades
+	}
+	return nil
+}
+
+type GetBalancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesRequest) Reset() {
+	*x = GetBalancesRequest{}
+	mi := &file_poloniex_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesRequest) ProtoMessage() {}
+
+func (x *GetBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesRequest.ProtoReflect.Descriptor instead.
+func (*GetBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{13}
+}
+
+type GetBalancesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balances      map[string]*Balance    `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesResponse) Reset() {
+	*x = GetBalancesResponse{}
+	mi := &file_poloniex_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesResponse) ProtoMessage() {}
+
+func (x *GetBalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesResponse.ProtoReflect.Descriptor instead.
+func (*GetBalancesResponse) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetBalancesResponse) GetBalances() map[string]*Balance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+type GetOpenOrdersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All markets when empty.
+	Pair          string `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOpenOrdersRequest) Reset() {
+	*x = GetOpenOrdersRequest{}
+	mi := &file_poloniex_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOpenOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpenOrdersRequest) ProtoMessage() {}
+
+func (x *GetOpenOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpenOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetOpenOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetOpenOrdersRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+type GetOpenOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*OpenOrder           `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOpenOrdersResponse) Reset() {
+	*x = GetOpenOrdersResponse{}
+	mi := &file_poloniex_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOpenOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpenOrdersResponse) ProtoMessage() {}
+
+func (x *GetOpenOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpenOrdersResponse.ProtoReflect.Descriptor instead.
+func (*GetOpenOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetOpenOrdersResponse) GetOrders() []*OpenOrder {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type GetFeeInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFeeInfoRequest) Reset() {
+	*x = GetFeeInfoRequest{}
+	mi := &file_poloniex_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFeeInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeeInfoRequest) ProtoMessage() {}
+
+func (x *GetFeeInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeeInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetFeeInfoRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{17}
+}
+
+type PlaceOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pair          string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Side          Side                   `protobuf:"varint,2,opt,name=side,proto3,enum=poloniex.Side" json:"side,omitempty"`
+	Rate          float64                `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	TimeInForce   TimeInForce            `protobuf:"varint,5,opt,name=time_in_force,json=timeInForce,proto3,enum=poloniex.TimeInForce" json:"time_in_force,omitempty"`
+	PostOnly      bool                   `protobuf:"varint,6,opt,name=post_only,json=postOnly,proto3" json:"post_only,omitempty"`
+	ClientOrderId int64                  `protobuf:"varint,7,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderRequest) Reset() {
+	*x = PlaceOrderRequest{}
+	mi := &file_poloniex_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderRequest) ProtoMessage() {}
+
+func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PlaceOrderRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *PlaceOrderRequest) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *PlaceOrderRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PlaceOrderRequest) GetTimeInForce() TimeInForce {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return TimeInForce_TIME_IN_FORCE_GTC
+}
+
+func (x *PlaceOrderRequest) GetPostOnly() bool {
+	if x != nil {
+		return x.PostOnly
+	}
+	return false
+}
+
+func (x *PlaceOrderRequest) GetClientOrderId() int64 {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return 0
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderNumber   int64                  `protobuf:"varint,1,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_poloniex_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CancelOrderRequest) GetOrderNumber() int64 {
+	if x != nil {
+		return x.OrderNumber
+	}
+	return 0
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_poloniex_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CancelOrderResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelOrderResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CancelOrderResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type StreamTickersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All markets when empty.
+	Pairs         []string `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	IntervalMs    int64    `protobuf:"varint,2,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTickersRequest) Reset() {
+	*x = StreamTickersRequest{}
+	mi := &file_poloniex_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTickersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTickersRequest) ProtoMessage() {}
+
+func (x *StreamTickersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTickersRequest.ProtoReflect.Descriptor instead.
+func (*StreamTickersRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *StreamTickersRequest) GetPairs() []string {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *StreamTickersRequest) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+type TickerUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Tickers       map[string]*Ticker     `protobuf:"bytes,2,rep,name=tickers,proto3" json:"tickers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TickerUpdate) Reset() {
+	*x = TickerUpdate{}
+	mi := &file_poloniex_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TickerUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TickerUpdate) ProtoMessage() {}
+
+func (x *TickerUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TickerUpdate.ProtoReflect.Descriptor instead.
+func (*TickerUpdate) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TickerUpdate) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *TickerUpdate) GetTickers() map[string]*Ticker {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+type StreamOrderBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pair          string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	IntervalMs    int64                  `protobuf:"varint,3,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrderBookRequest) Reset() {
+	*x = StreamOrderBookRequest{}
+	mi := &file_poloniex_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrderBookRequest) ProtoMessage() {}
+
+func (x *StreamOrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrderBookRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StreamOrderBookRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *StreamOrderBookRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *StreamOrderBookRequest) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+type StreamFillsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All markets when empty.
+	Pair          string `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	IntervalMs    int64  `protobuf:"varint,2,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamFillsRequest) Reset() {
+	*x = StreamFillsRequest{}
+	mi := &file_poloniex_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamFillsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamFillsRequest) ProtoMessage() {}
+
+func (x *StreamFillsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamFillsRequest.ProtoReflect.Descriptor instead.
+func (*StreamFillsRequest) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *StreamFillsRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *StreamFillsRequest) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+// Fill is a trade of one of your orders.
+type Fill struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Pair          string                 `protobuf:"bytes,2,opt,name=pair,proto3" json:"pair,omitempty"`
+	Trade         *Trade                 `protobuf:"bytes,3,opt,name=trade,proto3" json:"trade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fill) Reset() {
+	*x = Fill{}
+	mi := &file_poloniex_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fill) ProtoMessage() {}
+
+func (x *Fill) ProtoReflect() protoreflect.Message {
+	mi := &file_poloniex_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fill.ProtoReflect.Descriptor instead.
+func (*Fill) Descriptor() ([]byte, []int) {
+	return file_poloniex_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Fill) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Fill) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *Fill) GetTrade() *Trade {
+	if x != nil {
+		return x.Trade
+	}
+	return nil
+}
+
+var File_poloniex_proto protoreflect.FileDescriptor
+
+const file_poloniex_proto_rawDesc = "" +
+	"\n" +
+	"\x0epoloniex.proto\x12\bpoloniex\x1a\x1fgoogle/protobuf/timestamp.proto\"\xaa\x02\n" +
+	"\x06Ticker\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04last\x18\x02 \x01(\x01R\x04last\x12\x1d\n" +
+	"\n" +
+	"lowest_ask\x18\x03 \x01(\x01R\tlowestAsk\x12\x1f\n" +
+	"\vhighest_bid\x18\x04 \x01(\x01R\n" +
+	"highestBid\x12%\n" +
+	"\x0epercent_change\x18\x05 \x01(\x01R\rpercentChange\x12\x1f\n" +
+	"\vbase_volume\x18\x06 \x01(\x01R\n" +
+	"baseVolume\x12!\n" +
+	"\fquote_volume\x18\a \x01(\x01R\vquoteVolume\x12\x1b\n" +
+	"\tis_frozen\x18\b \x01(\bR\bisFrozen\x12\x1a\n" +
+	"\bhigh24hr\x18\t \x01(\x01R\bhigh24hr\x12\x18\n" +
+	"\alow24hr\x18\n" +
+	" \x01(\x01R\alow24hr\"3\n" +
+	"\x05Level\x12\x12\n" +
+	"\x04rate\x18\x01 \x01(\x01R\x04rate\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\"\xc8\x01\n" +
+	"\tOrderBook\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\x12\x1b\n" +
+	"\tis_frozen\x18\x03 \x01(\bR\bisFrozen\x12#\n" +
+	"\x04asks\x18\x04 \x03(\v2\x0f.poloniex.LevelR\x04asks\x12#\n" +
+	"\x04bids\x18\x05 \x03(\v2\x0f.poloniex.LevelR\x04bids\x12.\n" +
+	"\x04time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\xad\x02\n" +
+	"\x05Trade\x12&\n" +
+	"\x0fglobal_trade_id\x18\x01 \x01(\x03R\rglobalTradeId\x12\x19\n" +
+	"\btrade_id\x18\x02 \x01(\x03R\atradeId\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x12\n" +
+	"\x04rate\x18\x05 \x01(\x01R\x04rate\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x01R\x06amount\x12\x14\n" +
+	"\x05total\x18\a \x01(\x01R\x05total\x12\x10\n" +
+	"\x03fee\x18\b \x01(\x01R\x03fee\x12!\n" +
+	"\forder_number\x18\t \x01(\x03R\vorderNumber\x12\x1a\n" +
+	"\bcategory\x18\n" +
+	" \x01(\tR\bcategory\x12&\n" +
+	"\x0fclient_order_id\x18\v \x01(\x03R\rclientOrderId\"\x95\x02\n" +
+	"\tOpenOrder\x12!\n" +
+	"\forder_number\x18\x01 \x01(\tR\vorderNumber\x12\x12\n" +
+	"\x04pair\x18\x02 \x01(\tR\x04pair\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
+	"\x04rate\x18\x04 \x01(\x01R\x04rate\x12'\n" +
+	"\x0fstarting_amount\x18\x05 \x01(\x01R\x0estartingAmount\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x01R\x06amount\x12\x14\n" +
+	"\x05total\x18\a \x01(\x01R\x05total\x12\x12\n" +
+	"\x04date\x18\b \x01(\tR\x04date\x12\x16\n" +
+	"\x06margin\x18\t \x01(\bR\x06margin\x12&\n" +
+	"\x0fclient_order_id\x18\n" +
+	" \x01(\x03R\rclientOrderId\"\xa2\x01\n" +
+	"\x05Order\x12!\n" +
+	"\forder_number\x18\x01 \x01(\x03R\vorderNumber\x12&\n" +
+	"\x0fclient_order_id\x18\x02 \x01(\x03R\rclientOrderId\x12\x12\n" +
+	"\x04pair\x18\x03 \x01(\tR\x04pair\x12:\n" +
+	"\x10resulting_trades\x18\x04 \x03(\v2\x0f.poloniex.TradeR\x0fresultingTrades\"a\n" +
+	"\aBalance\x12\x1c\n" +
+	"\tavailable\x18\x01 \x01(\x01R\tavailable\x12\x1b\n" +
+	"\ton_orders\x18\x02 \x01(\x01R\bonOrders\x12\x1b\n" +
+	"\tbtc_value\x18\x03 \x01(\x01R\bbtcValue\"\x8c\x01\n" +
+	"\aFeeInfo\x12\x1b\n" +
+	"\tmaker_fee\x18\x01 \x01(\x01R\bmakerFee\x12\x1b\n" +
+	"\ttaker_fee\x18\x02 \x01(\x01R\btakerFee\x12*\n" +
+	"\x11thirty_day_volume\x18\x03 \x01(\x01R\x0fthirtyDayVolume\x12\x1b\n" +
+	"\tnext_tier\x18\x04 \x01(\x01R\bnextTier\"(\n" +
+	"\x10GetTickerRequest\x12\x14\n" +
+	"\x05pairs\x18\x01 \x03(\tR\x05pairs\"\xa5\x01\n" +
+	"\x11GetTickerResponse\x12B\n" +
+	"\atickers\x18\x01 \x03(\v2(.poloniex.GetTickerResponse.TickersEntryR\atickers\x1aL\n" +
+	"\fTickersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
+	"\x05value\x18\x02 \x01(\v2\x10.poloniex.TickerR\x05value:\x028\x01\"?\n" +
+	"\x13GetOrderBookRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\"T\n" +
+	"\x16GetTradeHistoryRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x03R\x03end\"B\n" +
+	"\x17GetTradeHistoryResponse\x12'\n" +
+	"\x06trades\x18\x01 \x03(\v2\x0f.poloniex.TradeR\x06trades\"\x14\n" +
+	"\x12GetBalancesRequest\"\xae\x01\n" +
+	"\x13GetBalancesResponse\x12G\n" +
+	"\bbalances\x18\x01 \x03(\v2+.poloniex.GetBalancesResponse.BalancesEntryR\bbalances\x1aN\n" +
+	"\rBalancesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.poloniex.BalanceR\x05value:\x028\x01\"*\n" +
+	"\x14GetOpenOrdersRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\"D\n" +
+	"\x15GetOpenOrdersResponse\x12+\n" +
+	"\x06orders\x18\x01 \x03(\v2\x13.poloniex.OpenOrderR\x06orders\"\x13\n" +
+	"\x11GetFeeInfoRequest\"\xf7\x01\n" +
+	"\x11PlaceOrderRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\"\n" +
+	"\x04side\x18\x02 \x01(\x0e2\x0e.poloniex.SideR\x04side\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x129\n" +
+	"\rtime_in_force\x18\x05 \x01(\x0e2\x15.poloniex.TimeInForceR\vtimeInForce\x12\x1b\n" +
+	"\tpost_only\x18\x06 \x01(\bR\bpostOnly\x12&\n" +
+	"\x0fclient_order_id\x18\a \x01(\x03R\rclientOrderId\"7\n" +
+	"\x12CancelOrderRequest\x12!\n" +
+	"\forder_number\x18\x01 \x01(\x03R\vorderNumber\"a\n" +
+	"\x13CancelOrderResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"M\n" +
+	"\x14StreamTickersRequest\x12\x14\n" +
+	"\x05pairs\x18\x01 \x03(\tR\x05pairs\x12\x1f\n" +
+	"\vinterval_ms\x18\x02 \x01(\x03R\n" +
+	"intervalMs\"\xcb\x01\n" +
+	"\fTickerUpdate\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12=\n" +
+	"\atickers\x18\x02 \x03(\v2#.poloniex.TickerUpdate.TickersEntryR\atickers\x1aL\n" +
+	"\fTickersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
+	"\x05value\x18\x02 \x01(\v2\x10.poloniex.TickerR\x05value:\x028\x01\"c\n" +
+	"\x16StreamOrderBookRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\x12\x1f\n" +
+	"\vinterval_ms\x18\x03 \x01(\x03R\n" +
+	"intervalMs\"I\n" +
+	"\x12StreamFillsRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x1f\n" +
+	"\vinterval_ms\x18\x02 \x01(\x03R\n" +
+	"intervalMs\"q\n" +
+	"\x04Fill\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x12\n" +
+	"\x04pair\x18\x02 \x01(\tR\x04pair\x12%\n" +
+	"\x05trade\x18\x03 \x01(\v2\x0f.poloniex.TradeR\x05trade*9\n" +
+	"\x04Side\x12\x14\n" +
+	"\x10SIDE_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bSIDE_BUY\x10\x01\x12\r\n" +
+	"\tSIDE_SELL\x10\x02*R\n" +
+	"\vTimeInForce\x12\x15\n" +
+	"\x11TIME_IN_FORCE_GTC\x10\x00\x12\x15\n" +
+	"\x11TIME_IN_FORCE_FOK\x10\x01\x12\x15\n" +
+	"\x11TIME_IN_FORCE_IOC\x10\x022\xa6\x06\n" +
+	"\bPoloniex\x12D\n" +
+	"\tGetTicker\x12\x1a.poloniex.GetTickerRequest\x1a\x1b.poloniex.GetTickerResponse\x12B\n" +
+	"\fGetOrderBook\x12\x1d.poloniex.GetOrderBookRequest\x1a\x13.poloniex.OrderBook\x12V\n" +
+	"\x0fGetTradeHistory\x12 .poloniex.GetTradeHistoryRequest\x1a!.poloniex.GetTradeHistoryResponse\x12J\n" +
+	"\vGetBalances\x12\x1c.poloniex.GetBalancesRequest\x1a\x1d.poloniex.GetBalancesResponse\x12P\n" +
+	"\rGetOpenOrders\x12\x1e.poloniex.GetOpenOrdersRequest\x1a\x1f.poloniex.GetOpenOrdersResponse\x12<\n" +
+	"\n" +
+	"GetFeeInfo\x12\x1b.poloniex.GetFeeInfoRequest\x1a\x11.poloniex.FeeInfo\x12:\n" +
+	"\n" +
+	"PlaceOrder\x12\x1b.poloniex.PlaceOrderRequest\x1a\x0f.poloniex.Order\x12J\n" +
+	"\vCancelOrder\x12\x1c.poloniex.CancelOrderRequest\x1a\x1d.poloniex.CancelOrderResponse\x12I\n" +
+	"\rStreamTickers\x12\x1e.poloniex.StreamTickersRequest\x1a\x16.poloniex.TickerUpdate0\x01\x12J\n" +
+	"\x0fStreamOrderBook\x12 .poloniex.StreamOrderBookRequest\x1a\x13.poloniex.OrderBook0\x01\x12=\n" +
+	"\vStreamFills\x12\x1c.poloniex.StreamFillsRequest\x1a\x0e.poloniex.Fill0\x01B%Z#github.com/mycroft/poloniex-api/rpcb\x06proto3"
+
+var (
+	file_poloniex_proto_rawDescOnce sync.Once
+	file_poloniex_proto_rawDescData []byte
+)
+
+func file_poloniex_proto_rawDescGZIP() []byte {
+	file_poloniex_proto_rawDescOnce.Do(func() {
+		file_poloniex_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_poloniex_proto_rawDesc), len(file_poloniex_proto_rawDesc)))
+	})
+	return file_poloniex_proto_rawDescData
+}
+
+var file_poloniex_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_poloniex_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_poloniex_proto_goTypes = []any{
+	(Side)(0),                       // 0: poloniex.Side
+	(TimeInForce)(0),                // 1: poloniex.TimeInForce
+	(*Ticker)(nil),                  // 2: poloniex.Ticker
+	(*Level)(nil),                   // 3: poloniex.Level
+	(*OrderBook)(nil),               // 4: poloniex.OrderBook
+	(*Trade)(nil),                   // 5: poloniex.Trade
+	(*OpenOrder)(nil),               // 6: poloniex.OpenOrder
+	(*Order)(nil),                   // 7: poloniex.Order
+	(*Balance)(nil),                 // 8: poloniex.Balance
+	(*FeeInfo)(nil),                 // 9: poloniex.FeeInfo
+	(*GetTickerRequest)(nil),        // 10: poloniex.GetTickerRequest
+	(*GetTickerResponse)(nil),       // 11: poloniex.GetTickerResponse
+	(*GetOrderBookRequest)(nil),     // 12: poloniex.GetOrderBookRequest
+	(*GetTradeHistoryRequest)(nil),  // 13: poloniex.GetTradeHistoryRequest
+	(*GetTradeHistoryResponse)(nil), // 14: poloniex.GetTradeHistoryResponse
+	(*GetBalancesRequest)(nil),      // 15: poloniex.GetBalancesRequest
+	(*GetBalancesResponse)(nil),     // 16: poloniex.GetBalancesResponse
+	(*GetOpenOrdersRequest)(nil),    // 17: poloniex.GetOpenOrdersRequest
+	(*GetOpenOrdersResponse)(nil),   // 18: poloniex.GetOpenOrdersResponse
+	(*GetFeeInfoRequest)(nil),       // 19: poloniex.GetFeeInfoRequest
+	(*PlaceOrderRequest)(nil),       // 20: poloniex.PlaceOrderRequest
+	(*CancelOrderRequest)(nil),      // 21: poloniex.CancelOrderRequest
+	(*CancelOrderResponse)(nil),     // 22: poloniex.CancelOrderResponse
+	(*StreamTickersRequest)(nil),    // 23: poloniex.StreamTickersRequest
+	(*TickerUpdate)(nil),            // 24: poloniex.TickerUpdate
+	(*StreamOrderBookRequest)(nil),  // 25: poloniex.StreamOrderBookRequest
+	(*StreamFillsRequest)(nil),      // 26: poloniex.StreamFillsRequest
+	(*Fill)(nil),                    // 27: poloniex.Fill
+	nil,                             // 28: poloniex.GetTickerResponse.TickersEntry
+	nil,                             // 29: poloniex.GetBalancesResponse.BalancesEntry
+	nil,                             // 30: poloniex.TickerUpdate.TickersEntry
+	(*timestamppb.Timestamp)(nil),   // 31: google.protobuf.Timestamp
+}
+var file_poloniex_proto_depIdxs = []int32{
+	3,  // 0: poloniex.OrderBook.asks:type_name -> poloniex.Level
+	3,  // 1: poloniex.OrderBook.bids:type_name -> poloniex.Level
+	31, // 2: poloniex.OrderBook.time:type_name -> google.protobuf.Timestamp
+	5,  // 3: poloniex.Order.resulting_trades:type_name -> poloniex.Trade
+	28, // 4: poloniex.GetTickerResponse.tickers:type_name -> poloniex.GetTickerResponse.TickersEntry
+	5,  // 5: poloniex.GetTradeHistoryResponse.trades:type_name -> poloniex.Trade
+	29, // 6: poloniex.GetBalancesResponse.balances:type_name -> poloniex.GetBalancesResponse.BalancesEntry
+	6,  // 7: poloniex.GetOpenOrdersResponse.orders:type_name -> poloniex.OpenOrder
+	0,  // 8: poloniex.PlaceOrderRequest.side:type_name -> poloniex.Side
+	1,  // 9: poloniex.PlaceOrderRequest.time_in_force:type_name -> poloniex.TimeInForce
+	31, // 10: poloniex.TickerUpdate.time:type_name -> google.protobuf.Timestamp
+	30, // 11: poloniex.TickerUpdate.tickers:type_name -> poloniex.TickerUpdate.TickersEntry
+	31, // 12: poloniex.Fill.time:type_name -> google.protobuf.Timestamp
+	5,  // 13: poloniex.Fill.trade:type_name -> poloniex.Trade
+	2,  // 14: poloniex.GetTickerResponse.TickersEntry.value:type_name -> poloniex.Ticker
+	8,  // 15: poloniex.GetBalancesResponse.BalancesEntry.value:type_name -> poloniex.Balance
+	2,  // 16: poloniex.TickerUpdate.TickersEntry.value:type_name -> poloniex.Ticker
+	10, // 17: poloniex.Poloniex.GetTicker:input_type -> poloniex.GetTickerRequest
+	12, // 18: poloniex.Poloniex.GetOrderBook:input_type -> poloniex.GetOrderBookRequest
+	13, // 19: poloniex.Poloniex.GetTradeHistory:input_type -> poloniex.GetTradeHistoryRequest
+	15, // 20: poloniex.Poloniex.GetBalances:input_type -> poloniex.GetBalancesRequest
+	17, // 21: poloniex.Poloniex.GetOpenOrders:input_type -> poloniex.GetOpenOrdersRequest
+	19, // 22: poloniex.Poloniex.GetFeeInfo:input_type -> poloniex.GetFeeInfoRequest
+	20, // 23: poloniex.Poloniex.PlaceOrder:input_type -> poloniex.PlaceOrderRequest
+	21, // 24: poloniex.Poloniex.CancelOrder:input_type -> poloniex.CancelOrderRequest
+	23, // 25: poloniex.Poloniex.StreamTickers:input_type -> poloniex.StreamTickersRequest
+	25, // 26: poloniex.Poloniex.StreamOrderBook:input_type -> poloniex.StreamOrderBookRequest
+	26, // 27: poloniex.Poloniex.StreamFills:input_type -> poloniex.StreamFillsRequest
+	11, // 28: poloniex.Poloniex.GetTicker:output_type -> poloniex.GetTickerResponse
+	4,  // 29: poloniex.Poloniex.GetOrderBook:output_type -> poloniex.OrderBook
+	14, // 30: poloniex.Poloniex.GetTradeHistory:output_type -> poloniex.GetTradeHistoryResponse
+	16, // 31: poloniex.Poloniex.GetBalances:output_type -> poloniex.GetBalancesResponse
+	18, // 32: poloniex.Poloniex.GetOpenOrders:output_type -> poloniex.GetOpenOrdersResponse
+	9,  // 33: poloniex.Poloniex.GetFeeInfo:output_type -> poloniex.FeeInfo
+	7,  // 34: poloniex.Poloniex.PlaceOrder:output_type -> poloniex.Order
+	22, // 35: poloniex.Poloniex.CancelOrder:output_type -> poloniex.CancelOrderResponse
+	24, // 36: poloniex.Poloniex.StreamTickers:output_type -> poloniex.TickerUpdate
+	4,  // 37: poloniex.Poloniex.StreamOrderBook:output_type -> poloniex.OrderBook
+	27, // 38: poloniex.Poloniex.StreamFills:output_type -> poloniex.Fill
+	28, // [28:39] is the sub-list for method output_type
+	17, // [17:28] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_poloniex_proto_init() }
+func file_poloniex_proto_init() {
+	if File_poloniex_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_poloniex_proto_rawDesc), len(file_poloniex_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_poloniex_proto_goTypes,
+		DependencyIndexes: file_poloniex_proto_depIdxs,
+		EnumInfos:         file_poloniex_proto_enumTypes,
+		MessageInfos:      file_poloniex_proto_msgTypes,
+	}.Build()
+	File_poloniex_proto = out.File
+	file_poloniex_proto_goTypes = nil
+	file_poloniex_proto_depIdxs = nil
+}
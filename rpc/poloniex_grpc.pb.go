@@ -0,0 +1,519 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: poloniex.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Poloniex_GetTicker_FullMethodName       = "/poloniex.Poloniex/GetTicker"
+	Poloniex_GetOrderBook_FullMethodName    = "/poloniex.Poloniex/GetOrderBook"
+	Poloniex_GetTradeHistory_FullMethodName = "/poloniex.Poloniex/GetTradeHistory"
+	Poloniex_GetBalances_FullMethodName     = "/poloniex.Poloniex/GetBalances"
+	Poloniex_GetOpenOrders_FullMethodName   = "/poloniex.Poloniex/GetOpenOrders"
+	Poloniex_GetFeeInfo_FullMethodName      = "/poloniex.Poloniex/GetFeeInfo"
+	Poloniex_PlaceOrder_FullMethodName      = "/poloniex.Poloniex/PlaceOrder"
+	Poloniex_CancelOrder_FullMethodName     = "/poloniex.Poloniex/CancelOrder"
+	Poloniex_StreamTickers_FullMethodName   = "/poloniex.Poloniex/StreamTickers"
+	Poloniex_StreamOrderBook_FullMethodName = "/poloniex.Poloniex/StreamOrderBook"
+	Poloniex_StreamFills_FullMethodName     = "/poloniex.Poloniex/StreamFills"
+)
+
+// PoloniexClient is the client API for Poloniex service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Poloniex serves a PoloniexApi client.
+type PoloniexClient interface {
+	GetTicker(ctx context.Context, in *GetTickerRequest, opts ...grpc.CallOption) (*GetTickerResponse, error)
+	GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*OrderBook, error)
+	GetTradeHistory(ctx context.Context, in *GetTradeHistoryRequest, opts ...grpc.CallOption) (*GetTradeHistoryResponse, error)
+	GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error)
+	GetOpenOrders(ctx context.Context, in *GetOpenOrdersRequest, opts ...grpc.CallOption) (*GetOpenOrdersResponse, error)
+	GetFeeInfo(ctx context.Context, in *GetFeeInfoRequest, opts ...grpc.CallOption) (*FeeInfo, error)
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	// Streams are polled by the server every interval_ms, and end on the first
+	// error.
+	StreamTickers(ctx context.Context, in *StreamTickersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TickerUpdate], error)
+	StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBook], error)
+	StreamFills(ctx context.Context, in *StreamFillsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Fill], error)
+}
+
+type poloniexClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPoloniexClient(cc grpc.ClientConnInterface) PoloniexClient {
+	return &poloniexClient{cc}
+}
+
+func (c *poloniexClient) GetTicker(ctx context.Context, in *GetTickerRequest, opts ...grpc.CallOption) (*GetTickerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTickerResponse)
+	err := c.cc.Invoke(ctx, Poloniex_GetTicker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*OrderBook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderBook)
+	err := c.cc.Invoke(ctx, Poloniex_GetOrderBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) GetTradeHistory(ctx context.Context, in *GetTradeHistoryRequest, opts ...grpc.CallOption) (*GetTradeHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTradeHistoryResponse)
+	err := c.cc.Invoke(ctx, Poloniex_GetTradeHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalancesResponse)
+	err := c.cc.Invoke(ctx, Poloniex_GetBalances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) GetOpenOrders(ctx context.Context, in *GetOpenOrdersRequest, opts ...grpc.CallOption) (*GetOpenOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOpenOrdersResponse)
+	err := c.cc.Invoke(ctx, Poloniex_GetOpenOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) GetFeeInfo(ctx context.Context, in *GetFeeInfoRequest, opts ...grpc.CallOption) (*FeeInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeeInfo)
+	err := c.cc.Invoke(ctx, Poloniex_GetFeeInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, Poloniex_PlaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, Poloniex_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *poloniexClient) StreamTickers(ctx context.Context, in *StreamTickersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TickerUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Poloniex_ServiceDesc.Streams[0], Poloniex_StreamTickers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTickersRequest, TickerUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Poloniex_StreamTickersClient = grpc.ServerStreamingClient[TickerUpdate]
+
+func (c *poloniexClient) StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBook], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Poloniex_ServiceDesc.Streams[1], Poloniex_StreamOrderBook_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrderBookRequest, OrderBook]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Poloniex_StreamOrderBookClient = grpc.ServerStreamingClient[OrderBook]
+
+func (c *poloniexClient) StreamFills(ctx context.Context, in *StreamFillsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Fill], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Poloniex_ServiceDesc.Streams[2], Poloniex_StreamFills_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamFillsRequest, Fill]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Poloniex_StreamFillsClient = grpc.ServerStreamingClient[Fill]
+
+// PoloniexServer is the server API for Poloniex service.
+// All implementations must embed UnimplementedPoloniexServer
+// for forward compatibility.
+//
+// Poloniex serves a PoloniexApi client.
+type PoloniexServer interface {
+	GetTicker(context.Context, *GetTickerRequest) (*GetTickerResponse, error)
+	GetOrderBook(context.Context, *GetOrderBookRequest) (*OrderBook, error)
+	GetTradeHistory(context.Context, *GetTradeHistoryRequest) (*GetTradeHistoryResponse, error)
+	GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error)
+	GetOpenOrders(context.Context, *GetOpenOrdersRequest) (*GetOpenOrdersResponse, error)
+	GetFeeInfo(context.Context, *GetFeeInfoRequest) (*FeeInfo, error)
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*Order, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	// Streams are polled by the server every interval_ms, and end on the first
+	// error.
+	StreamTickers(*StreamTickersRequest, grpc.ServerStreamingServer[TickerUpdate]) error
+	StreamOrderBook(*StreamOrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error
+	StreamFills(*StreamFillsRequest, grpc.ServerStreamingServer[Fill]) error
+	mustEmbedUnimplementedPoloniexServer()
+}
+
+// UnimplementedPoloniexServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPoloniexServer struct{}
+
+func (UnimplementedPoloniexServer) GetTicker(context.Context, *GetTickerRequest) (*GetTickerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicker not implemented")
+}
+func (UnimplementedPoloniexServer) GetOrderBook(context.Context, *GetOrderBookRequest) (*OrderBook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedPoloniexServer) GetTradeHistory(context.Context, *GetTradeHistoryRequest) (*GetTradeHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTradeHistory not implemented")
+}
+func (UnimplementedPoloniexServer) GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
+func (UnimplementedPoloniexServer) GetOpenOrders(context.Context, *GetOpenOrdersRequest) (*GetOpenOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOpenOrders not implemented")
+}
+func (UnimplementedPoloniexServer) GetFeeInfo(context.Context, *GetFeeInfoRequest) (*FeeInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeeInfo not implemented")
+}
+func (UnimplementedPoloniexServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedPoloniexServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedPoloniexServer) StreamTickers(*StreamTickersRequest, grpc.ServerStreamingServer[TickerUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTickers not implemented")
+}
+func (UnimplementedPoloniexServer) StreamOrderBook(*StreamOrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrderBook not implemented")
+}
+func (UnimplementedPoloniexServer) StreamFills(*StreamFillsRequest, grpc.ServerStreamingServer[Fill]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamFills not implemented")
+}
+func (UnimplementedPoloniexServer) mustEmbedUnimplementedPoloniexServer() {}
+func (UnimplementedPoloniexServer) testEmbeddedByValue()                  {}
+
+// UnsafePoloniexServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PoloniexServer will
+// result in compilation errors.
+type UnsafePoloniexServer interface {
+	mustEmbedUnimplementedPoloniexServer()
+}
+
+func RegisterPoloniexServer(s grpc.ServiceRegistrar, srv PoloniexServer) {
+	// If the following call pancis, it indicates UnimplementedPoloniexServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Poloniex_ServiceDesc, srv)
+}
+
+func _Poloniex_GetTicker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTickerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).GetTicker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_GetTicker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).GetTicker(ctx, req.(*GetTickerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).GetOrderBook(ctx, req.(*GetOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_GetTradeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTradeHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).GetTradeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_GetTradeHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).GetTradeHistory(ctx, req.(*GetTradeHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).GetBalances(ctx, req.(*GetBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_GetOpenOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOpenOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).GetOpenOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_GetOpenOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).GetOpenOrders(ctx, req.(*GetOpenOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_GetFeeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFeeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).GetFeeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_GetFeeInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).GetFeeInfo(ctx, req.(*GetFeeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoloniexServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Poloniex_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoloniexServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poloniex_StreamTickers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTickersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PoloniexServer).StreamTickers(m, &grpc.GenericServerStream[StreamTickersRequest, TickerUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Poloniex_StreamTickersServer = grpc.ServerStreamingServer[TickerUpdate]
+
+func _Poloniex_StreamOrderBook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrderBookRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PoloniexServer).StreamOrderBook(m, &grpc.GenericServerStream[StreamOrderBookRequest, OrderBook]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Poloniex_StreamOrderBookServer = grpc.ServerStreamingServer[OrderBook]
+
+func _Poloniex_StreamFills_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamFillsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PoloniexServer).StreamFills(m, &grpc.GenericServerStream[StreamFillsRequest, Fill]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Poloniex_StreamFillsServer = grpc.ServerStreamingServer[Fill]
+
+// Poloniex_ServiceDesc is the grpc.ServiceDesc for Poloniex service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Poloniex_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "poloniex.Poloniex",
+	HandlerType: (*PoloniexServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTicker",
+			Handler:    _Poloniex_GetTicker_Handler,
+		},
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _Poloniex_GetOrderBook_Handler,
+		},
+		{
+			MethodName: "GetTradeHistory",
+			Handler:    _Poloniex_GetTradeHistory_Handler,
+		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _Poloniex_GetBalances_Handler,
+		},
+		{
+			MethodName: "GetOpenOrders",
+			Handler:    _Poloniex_GetOpenOrders_Handler,
+		},
+		{
+			MethodName: "GetFeeInfo",
+			Handler:    _Poloniex_GetFeeInfo_Handler,
+		},
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _Poloniex_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _Poloniex_CancelOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTickers",
+			Handler:       _Poloniex_StreamTickers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamOrderBook",
+			Handler:       _Poloniex_StreamOrderBook_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamFills",
+			Handler:       _Poloniex_StreamFills_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "poloniex.proto",
+}
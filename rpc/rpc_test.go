@@ -0,0 +1,151 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/gateway"
+	"github.com/mycroft/poloniex-api/internal/polotest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	readToken  = "read-token-0123456789"
+	tradeToken = "trade-token-0123456789"
+)
+
+func testClient(t *testing.T) *Client {
+	return testClientToken(t, tradeToken)
+}
+
+// testClientToken returns a client sending token to a server knowing
+// readToken and tradeToken.
+func testClientToken(t *testing.T, token string) *Client {
+	api := polotest.Api(polotest.Answers(map[string]string{
+		"returnTicker": `{"BTC_XMR":{"id":114,"last":"0.0125","lowestAsk":"0.0126","highestBid":"0.0124",
+			"percentChange":"0.01","baseVolume":"10","quoteVolume":"800","isFrozen":"0","high24hr":"0.013","low24hr":"0.012"}}`,
+		"returnOrderBook": `{"asks":[["0.0126",2]],"bids":[["0.0124",3]],"isFrozen":"0","seq":42}`,
+		"buy":             `{"orderNumber":"31226040","resultingTrades":[{"amount":"1","date":"2014-10-18 23:03:21","rate":"0.0125","total":"0.0125","tradeID":"16164","type":"buy"}]}`,
+		"cancelOrder":     `{"error":"Invalid order number, or you are not the person who placed the order."}`,
+	}))
+
+	auth, err := NewAuth([]gateway.Token{
+		{Name: "reader", Token: readToken, Scope: gateway.ScopeRead},
+		{Name: "trader", Token: tradeToken, Scope: gateway.ScopeTrade},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(auth.Unary), grpc.StreamInterceptor(auth.Stream))
+	RegisterPoloniexServer(server, NewServer(api))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()), WithToken(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestUnary(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	tickers, err := client.Ticker(ctx, "BTC_XMR", "BTC_ETH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickers) != 1 || tickers["BTC_XMR"].Last != 0.0125 || tickers["BTC_XMR"].High24hr != 0.013 {
+		t.Errorf("unexpected tickers %+v", tickers)
+	}
+
+	order, err := client.PlaceOrder(ctx, poloniexapi.SideBuy, "BTC_XMR", 0.0125, 1, &poloniexapi.OrderOptions{TimeInForce: poloniexapi.TimeInForceIOC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.OrderNumber != 31226040 || len(order.ResultingTrades["BTC_XMR"]) != 1 {
+		t.Errorf("unexpected order %+v", order)
+	}
+
+	_, err = client.PlaceOrder(ctx, "short", "BTC_XMR", 0.0125, 1, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown side: got %v, want InvalidArgument", err)
+	}
+
+	_, err = client.Cancel(ctx, 12)
+	if status.Code(err) != codes.FailedPrecondition || !strings.Contains(err.Error(), "Invalid order number") {
+		t.Errorf("cancel: got %v, want FailedPrecondition", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	client := testClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ev, ok := <-client.PollOrderBook(ctx, "BTC_XMR", 10, time.Second)
+	if !ok || ev.Err != nil {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	if ev.Pair != "BTC_XMR" || ev.Book.Seq != 42 || ev.Book.Asks[0] != [2]float64{0.0126, 2} || ev.Time.IsZero() {
+		t.Errorf("unexpected book %+v", ev)
+	}
+
+	ev, ok = <-client.PollOrderBook(ctx, "BTC_XMR", 10, time.Millisecond)
+	if !ok || status.Code(ev.Err) != codes.InvalidArgument {
+		t.Errorf("short interval: got %+v, want InvalidArgument", ev)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cases := []struct {
+		token string
+		read  codes.Code
+		trade codes.Code
+	}{
+		{"", codes.Unauthenticated, codes.Unauthenticated},
+		{"wrong-token-0123456789", codes.Unauthenticated, codes.Unauthenticated},
+		{readToken, codes.OK, codes.PermissionDenied},
+		{tradeToken, codes.OK, codes.OK},
+	}
+
+	for _, c := range cases {
+		client := testClientToken(t, c.token)
+
+		if _, err := client.Ticker(ctx); status.Code(err) != c.read {
+			t.Errorf("token %q: ticker got %v, want %s", c.token, err, c.read)
+		}
+
+		if _, err := client.PlaceOrder(ctx, poloniexapi.SideBuy, "BTC_XMR", 0.0125, 1, nil); status.Code(err) != c.trade {
+			t.Errorf("token %q: order got %v, want %s", c.token, err, c.trade)
+		}
+
+		// Streams are checked before their first message.
+		stream, err := client.StreamFills(ctx, &StreamFillsRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if c.trade != codes.OK && status.Code(err) != c.trade {
+			t.Errorf("token %q: fills got %v, want %s", c.token, err, c.trade)
+		}
+	}
+}
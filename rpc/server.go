@@ -0,0 +1,268 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Interval of the streams when a request does not give one, and the shortest
+// accepted.
+var (
+	DefaultStreamInterval = 10 * time.Second
+	MinStreamInterval     = time.Second
+)
+
+// Server implements PoloniexServer with a PoloniexApi.
+type Server struct {
+	UnimplementedPoloniexServer
+
+	api *poloniexapi.PoloniexApi
+}
+
+func NewServer(api *poloniexapi.PoloniexApi) *Server {
+	return &Server{api: api}
+}
+
+/*
+toStatus maps the errors of the api to gRPC codes: InvalidArgument for orders
+refused before being sent, FailedPrecondition when refused by the exchange,
+Unavailable when it did not answer.
+*/
+func toStatus(err error) error {
+	var apiError *poloniexapi.ApiError
+	var validationError *poloniexapi.OrderValidationError
+
+	switch {
+	case errors.Is(err, poloniexapi.ErrInvalidOrderOptions), errors.As(err, &validationError):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &apiError):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case poloniexapi.IsAmbiguous(err):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+func streamInterval(ms int64) (time.Duration, error) {
+	if ms == 0 {
+		return DefaultStreamInterval, nil
+	}
+
+	interval := time.Duration(ms) * time.Millisecond
+	if interval < MinStreamInterval {
+		return 0, status.Errorf(codes.InvalidArgument, "interval must be %s at least", MinStreamInterval)
+	}
+
+	return interval, nil
+}
+
+func (s *Server) GetTicker(ctx context.Context, req *GetTickerRequest) (*GetTickerResponse, error) {
+	tickers, err := s.api.ApiPublicTickerContext(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &GetTickerResponse{Tickers: toTickers(tickers, req.GetPairs())}, nil
+}
+
+func (s *Server) GetOrderBook(ctx context.Context, req *GetOrderBookRequest) (*OrderBook, error) {
+	if req.GetPair() == "" || req.GetPair() == "all" {
+		return nil, status.Error(codes.InvalidArgument, "a single market must be given")
+	}
+
+	books, err := s.api.ApiPublicOrderBookContext(ctx, req.GetPair(), int(req.GetDepth()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	book := ToOrderBook(req.GetPair(), books[req.GetPair()])
+	book.Time = timestamppb.Now()
+
+	return book, nil
+}
+
+func (s *Server) GetTradeHistory(ctx context.Context, req *GetTradeHistoryRequest) (*GetTradeHistoryResponse, error) {
+	trades, err := s.api.ApiPublicTradeHistoryContext(ctx, req.GetPair(), int(req.GetStart()), int(req.GetEnd()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &GetTradeHistoryResponse{Trades: toTrades(trades)}, nil
+}
+
+func (s *Server) GetBalances(ctx context.Context, req *GetBalancesRequest) (*GetBalancesResponse, error) {
+	balances, err := s.api.ApiPrivateCompleteBalancesContext(ctx, false)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	out := make(map[string]*Balance, len(balances))
+	for currency, b := range balances {
+		out[currency] = ToBalance(b)
+	}
+
+	return &GetBalancesResponse{Balances: out}, nil
+}
+
+func (s *Server) GetOpenOrders(ctx context.Context, req *GetOpenOrdersRequest) (*GetOpenOrdersResponse, error) {
+	pair := req.GetPair()
+	if pair == "" {
+		pair = "all"
+	}
+
+	orders, err := s.api.ApiPrivateOpenOrdersContext(ctx, pair)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	pairs := make([]string, 0, len(orders))
+	for p := range orders {
+		pairs = append(pairs, p)
+	}
+	sort.Strings(pairs)
+
+	out := make([]*OpenOrder, 0)
+	for _, p := range pairs {
+		for _, o := range orders[p] {
+			out = append(out, ToOpenOrder(p, o))
+		}
+	}
+
+	return &GetOpenOrdersResponse{Orders: out}, nil
+}
+
+func (s *Server) GetFeeInfo(ctx context.Context, req *GetFeeInfoRequest) (*FeeInfo, error) {
+	info, err := s.api.ApiPrivateFeeInfoContext(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return ToFeeInfo(*info), nil
+}
+
+func (s *Server) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*Order, error) {
+	var side poloniexapi.OrderSide
+
+	switch req.GetSide() {
+	case Side_SIDE_BUY:
+		side = poloniexapi.SideBuy
+	case Side_SIDE_SELL:
+		side = poloniexapi.SideSell
+	default:
+		return nil, status.Error(codes.InvalidArgument, "side must be given")
+	}
+
+	opts := &poloniexapi.OrderOptions{PostOnly: req.GetPostOnly(), ClientOrderID: req.GetClientOrderId()}
+	switch req.GetTimeInForce() {
+	case TimeInForce_TIME_IN_FORCE_GTC:
+	case TimeInForce_TIME_IN_FORCE_FOK:
+		opts.TimeInForce = poloniexapi.TimeInForceFOK
+	case TimeInForce_TIME_IN_FORCE_IOC:
+		opts.TimeInForce = poloniexapi.TimeInForceIOC
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown time in force %s", req.GetTimeInForce())
+	}
+
+	order, err := s.api.PlaceOrderContext(ctx, side, req.GetPair(), req.GetRate(), req.GetAmount(), opts)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return ToOrder(req.GetPair(), *order), nil
+}
+
+func (s *Server) CancelOrder(ctx context.Context, req *CancelOrderRequest) (*CancelOrderResponse, error) {
+	ok, cancel, err := s.api.ApiPrivateCancelContext(ctx, req.GetOrderNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &CancelOrderResponse{Success: ok, Amount: cancel.Amount, Message: cancel.Message}, nil
+}
+
+func (s *Server) StreamTickers(req *StreamTickersRequest, stream Poloniex_StreamTickersServer) error {
+	interval, err := streamInterval(req.GetIntervalMs())
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for ev := range s.api.PollTicker(ctx, interval) {
+		if ev.Err != nil {
+			return toStatus(ev.Err)
+		}
+
+		update := &TickerUpdate{Time: timestamppb.New(ev.Time), Tickers: toTickers(ev.Tickers, req.GetPairs())}
+		if err = stream.Send(update); err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s *Server) StreamOrderBook(req *StreamOrderBookRequest, stream Poloniex_StreamOrderBookServer) error {
+	if req.GetPair() == "" || req.GetPair() == "all" {
+		return status.Error(codes.InvalidArgument, "a single market must be given")
+	}
+
+	interval, err := streamInterval(req.GetIntervalMs())
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for ev := range s.api.PollOrderBook(ctx, req.GetPair(), int(req.GetDepth()), interval) {
+		if ev.Err != nil {
+			return toStatus(ev.Err)
+		}
+
+		book := ToOrderBook(ev.Pair, ev.Book)
+		book.Time = timestamppb.New(ev.Time)
+		if err = stream.Send(book); err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s *Server) StreamFills(req *StreamFillsRequest, stream Poloniex_StreamFillsServer) error {
+	pair := req.GetPair()
+	if pair == "" {
+		pair = "all"
+	}
+
+	interval, err := streamInterval(req.GetIntervalMs())
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for ev := range s.api.PollPrivateTrades(ctx, pair, interval) {
+		if ev.Err != nil {
+			return toStatus(ev.Err)
+		}
+
+		for _, t := range ev.Trades {
+			fill := &Fill{Time: timestamppb.New(ev.Time), Pair: ev.Pair, Trade: ToTrade(t)}
+			if err = stream.Send(fill); err != nil {
+				return err
+			}
+		}
+	}
+
+	return ctx.Err()
+}
@@ -23,8 +23,8 @@ func getHMacSha512(message, secret []byte) []byte {
 	return mac.Sum(nil)
 }
 
-func createPoloniexSignature(values url.Values, secret string) string {
-	macsum := getHMacSha512([]byte(values.Encode()), []byte(secret))
+func createPoloniexSignature(values url.Values, secret []byte) string {
+	macsum := getHMacSha512([]byte(values.Encode()), secret)
 
 	return hex.EncodeToString(macsum)
 }
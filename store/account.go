@@ -0,0 +1,319 @@
+package store
+
+import (
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+func (s *Store) SavePrivateTrades(trades map[string][]poloniexapi.Trade) error {
+	type row struct {
+		pair  string
+		trade poloniexapi.Trade
+	}
+
+	all := make([]row, 0)
+	for pair, list := range trades {
+		for _, t := range list {
+			all = append(all, row{pair, t})
+		}
+	}
+
+	return s.insert(`INSERT OR IGNORE INTO private_trades VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, len(all), func(i int) []interface{} {
+		t := all[i].trade
+		return []interface{}{all[i].pair, t.GlobalTradeID, t.TradeID, t.Date, tradeTime(t.Date), t.Type,
+			t.Rate, t.Amount, t.Total, t.Fee, t.OrderNumber, t.Category, t.ClientOrderId}
+	})
+}
+
+/*
+SyncPrivateTrades fetches your trades on all markets from the last one stored
+(by GlobalTradeID), or from since when there is none, in windows of
+poloniexapi.HistoryWindow saved one after the other. Windows with as many
+trades as the exchange returns at once are narrowed and fetched again (see
+PoloniexApi.LoadTradeHistory).
+*/
+func (s *Store) SyncPrivateTrades(api *poloniexapi.PoloniexApi, since time.Time) error {
+	start, err := s.last(`SELECT ts FROM private_trades ORDER BY global_trade_id DESC LIMIT 1`)
+	if err != nil {
+		return err
+	}
+
+	from := since
+	if start != 0 {
+		from = time.Unix(start, 0)
+	}
+
+	return historyWindows(from, func(from, to time.Time) error {
+		trades, err := api.LoadTradeHistory("all", from, to)
+		if err != nil {
+			return err
+		}
+
+		return s.SavePrivateTrades(trades)
+	})
+}
+
+// historyWindows calls sync on windows of poloniexapi.HistoryWindow from
+// from to now.
+func historyWindows(from time.Time, sync func(from, to time.Time) error) error {
+	for now := time.Now(); from.Before(now); from = from.Add(poloniexapi.HistoryWindow) {
+		to := from.Add(poloniexapi.HistoryWindow)
+		if to.After(now) {
+			to = now
+		}
+
+		if err := sync(from, to); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrivateTrades returns your trades between start and end, by market, as
+// ApiPrivateTradeHistory does.
+func (s *Store) PrivateTrades(start, end time.Time) (map[string][]poloniexapi.Trade, error) {
+	rows, err := s.db.Query(`SELECT pair, global_trade_id, trade_id, date, type, rate, amount, total, fee,
+		order_number, category, client_order_id FROM private_trades
+		WHERE ts >= ? AND ts < ? ORDER BY ts, trade_id`, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]poloniexapi.Trade)
+	for rows.Next() {
+		var pair string
+		var t poloniexapi.Trade
+
+		if err = rows.Scan(&pair, &t.GlobalTradeID, &t.TradeID, &t.Date, &t.Type, &t.Rate, &t.Amount, &t.Total,
+			&t.Fee, &t.OrderNumber, &t.Category, &t.ClientOrderId); err != nil {
+			return nil, err
+		}
+		out[pair] = append(out[pair], t)
+	}
+
+	return out, rows.Err()
+}
+
+/*
+SyncOpenOrders stores your open orders. Orders stored as open that are not
+anymore are kept, marked as closed.
+*/
+func (s *Store) SyncOpenOrders(api *poloniexapi.PoloniexApi) error {
+	open, err := api.ApiPrivateOpenOrders("all")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`UPDATE orders SET open = 0, updated = ? WHERE open = 1`, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for pair, orders := range open {
+		for _, o := range orders {
+			_, err = tx.Exec(`INSERT OR REPLACE INTO orders VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?)`,
+				o.OrderNumber, pair, o.Type, o.Rate, o.StartingAmount, o.Amount, o.Total, o.Date,
+				o.Margin, o.ClientOrderId, now)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// OpenOrders returns the orders open at the last SyncOpenOrders, by market.
+func (s *Store) OpenOrders() (map[string][]poloniexapi.OpenOrder, error) {
+	rows, err := s.db.Query(`SELECT pair, order_number, type, rate, starting_amount, amount, total, date,
+		margin, client_order_id FROM orders WHERE open = 1 ORDER BY pair, order_number`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]poloniexapi.OpenOrder)
+	for rows.Next() {
+		var pair string
+		var o poloniexapi.OpenOrder
+
+		if err = rows.Scan(&pair, &o.OrderNumber, &o.Type, &o.Rate, &o.StartingAmount, &o.Amount, &o.Total,
+			&o.Date, &o.Margin, &o.ClientOrderId); err != nil {
+			return nil, err
+		}
+		out[pair] = append(out[pair], o)
+	}
+
+	return out, rows.Err()
+}
+
+func (s *Store) SaveTransfers(transfers *poloniexapi.DepositWithdrawal) error {
+	err := s.insert(`INSERT OR REPLACE INTO deposits VALUES (?, ?, ?, ?, ?, ?, ?)`, len(transfers.Deposits), func(i int) []interface{} {
+		d := transfers.Deposits[i]
+		return []interface{}{d.Currency, d.Txid, d.Address, d.Amount, d.Confirmations, d.Timestamp, d.Status}
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.insert(`INSERT OR REPLACE INTO withdrawals VALUES (?, ?, ?, ?, ?, ?, ?)`, len(transfers.Withdrawals), func(i int) []interface{} {
+		w := transfers.Withdrawals[i]
+		return []interface{}{w.WithdrawalNumber, w.Currency, w.Address, w.Amount, w.Timestamp, w.Status, w.IpAddress}
+	})
+}
+
+/*
+SyncTransfers fetches deposits and withdrawals from the last one stored, or
+from since when there is none. Transfers still pending are fetched again until
+they complete.
+*/
+func (s *Store) SyncTransfers(api *poloniexapi.PoloniexApi, since time.Time) error {
+	start, err := s.last(`SELECT MIN(timestamp) FROM (
+		SELECT MAX(timestamp) AS timestamp FROM deposits
+		UNION ALL SELECT MIN(timestamp) FROM deposits WHERE status != 'COMPLETE'
+		UNION ALL SELECT MAX(timestamp) FROM withdrawals
+		UNION ALL SELECT MIN(timestamp) FROM withdrawals WHERE status NOT LIKE 'COMPLETE%')`)
+	if err != nil {
+		return err
+	}
+
+	if start == 0 {
+		start = since.Unix()
+	}
+
+	transfers, err := api.ApiPrivateDepositWithdrawals(start, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	return s.SaveTransfers(transfers)
+}
+
+// Transfers returns the deposits and withdrawals between start and end.
+func (s *Store) Transfers(start, end time.Time) (*poloniexapi.DepositWithdrawal, error) {
+	out := &poloniexapi.DepositWithdrawal{
+		Deposits:    make([]poloniexapi.Deposit, 0),
+		Withdrawals: make([]poloniexapi.Withdrawal, 0),
+	}
+
+	rows, err := s.db.Query(`SELECT currency, txid, address, amount, confirmations, timestamp, status
+		FROM deposits WHERE timestamp >= ? AND timestamp < ? ORDER BY timestamp`, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var d poloniexapi.Deposit
+		if err = rows.Scan(&d.Currency, &d.Txid, &d.Address, &d.Amount, &d.Confirmations, &d.Timestamp, &d.Status); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out.Deposits = append(out.Deposits, d)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.Query(`SELECT withdrawal_number, currency, address, amount, timestamp, status, ip_address
+		FROM withdrawals WHERE timestamp >= ? AND timestamp < ? ORDER BY timestamp`, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w poloniexapi.Withdrawal
+		if err = rows.Scan(&w.WithdrawalNumber, &w.Currency, &w.Address, &w.Amount, &w.Timestamp, &w.Status, &w.IpAddress); err != nil {
+			return nil, err
+		}
+		out.Withdrawals = append(out.Withdrawals, w)
+	}
+
+	return out, rows.Err()
+}
+
+func (s *Store) SaveLoans(loans []poloniexapi.LendingHistoryEntry) error {
+	return s.insert(`INSERT OR REPLACE INTO loans VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, len(loans), func(i int) []interface{} {
+		l := loans[i]
+		return []interface{}{l.Id, l.Currency, l.Rate, l.Amount, l.Duration, l.Interest, l.Fee, l.Earned,
+			l.Open, l.Close, tradeTime(l.Close)}
+	})
+}
+
+// SyncLoans fetches the lending history from the last loan closed, or from
+// since when there is none, in windows as SyncPrivateTrades.
+func (s *Store) SyncLoans(api *poloniexapi.PoloniexApi, since time.Time) error {
+	start, err := s.last(`SELECT MAX(close_ts) FROM loans`)
+	if err != nil {
+		return err
+	}
+
+	from := since
+	if start != 0 {
+		from = time.Unix(start, 0)
+	}
+
+	return historyWindows(from, func(from, to time.Time) error {
+		loans, err := api.LoadLendingHistory(from, to)
+		if err != nil {
+			return err
+		}
+
+		return s.SaveLoans(loans)
+	})
+}
+
+// Loans returns the loans closed between start and end.
+func (s *Store) Loans(start, end time.Time) ([]poloniexapi.LendingHistoryEntry, error) {
+	rows, err := s.db.Query(`SELECT id, currency, rate, amount, duration, interest, fee, earned, open, close
+		FROM loans WHERE close_ts >= ? AND close_ts < ? ORDER BY close_ts`, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]poloniexapi.LendingHistoryEntry, 0)
+	for rows.Next() {
+		var l poloniexapi.LendingHistoryEntry
+		if err = rows.Scan(&l.Id, &l.Currency, &l.Rate, &l.Amount, &l.Duration, &l.Interest, &l.Fee, &l.Earned,
+			&l.Open, &l.Close); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+
+	return out, rows.Err()
+}
+
+/*
+SyncAccount runs the account syncs: trades, open orders, transfers and loans,
+from since for those never synced before.
+*/
+func (s *Store) SyncAccount(api *poloniexapi.PoloniexApi, since time.Time) error {
+	if err := s.SyncPrivateTrades(api, since); err != nil {
+		return err
+	}
+
+	if err := s.SyncOpenOrders(api); err != nil {
+		return err
+	}
+
+	if err := s.SyncTransfers(api, since); err != nil {
+		return err
+	}
+
+	return s.SyncLoans(api, since)
+}
@@ -0,0 +1,201 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// SaveTickers stores a ticker snapshot taken at t.
+func (s *Store) SaveTickers(t time.Time, tickers map[string]poloniexapi.Ticker) error {
+	pairs := make([]string, 0, len(tickers))
+	for pair := range tickers {
+		pairs = append(pairs, pair)
+	}
+
+	return s.insert(`INSERT OR REPLACE INTO tickers VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, len(pairs), func(i int) []interface{} {
+		k := tickers[pairs[i]]
+		return []interface{}{t.Unix(), pairs[i], k.Last, k.LowestAsk, k.HighestBid, k.PercentChange,
+			k.BaseVolume, k.QuoteVolume, k.IsFrozen, k.High24hr, k.Low24hr}
+	})
+}
+
+// SyncTickers stores the current ticker of all markets.
+func (s *Store) SyncTickers(api *poloniexapi.PoloniexApi) error {
+	tickers, err := api.ApiPublicTicker()
+	if err != nil {
+		return err
+	}
+
+	return s.SaveTickers(time.Now(), tickers)
+}
+
+// Tickers returns the snapshots of a market between start and end.
+func (s *Store) Tickers(pair string, start, end time.Time) (map[time.Time]poloniexapi.Ticker, error) {
+	rows, err := s.db.Query(`SELECT time, last, lowest_ask, highest_bid, percent_change, base_volume,
+		quote_volume, is_frozen, high_24hr, low_24hr FROM tickers
+		WHERE pair = ? AND time >= ? AND time < ? ORDER BY time`, pair, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[time.Time]poloniexapi.Ticker)
+	for rows.Next() {
+		var at int64
+		var k poloniexapi.Ticker
+
+		if err = rows.Scan(&at, &k.Last, &k.LowestAsk, &k.HighestBid, &k.PercentChange, &k.BaseVolume,
+			&k.QuoteVolume, &k.IsFrozen, &k.High24hr, &k.Low24hr); err != nil {
+			return nil, err
+		}
+		out[time.Unix(at, 0)] = k
+	}
+
+	return out, rows.Err()
+}
+
+func (s *Store) SaveCandles(pair string, period int64, candles []poloniexapi.ChartEntry) error {
+	return s.insert(`INSERT OR REPLACE INTO candles VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, len(candles), func(i int) []interface{} {
+		c := candles[i]
+		return []interface{}{pair, period, c.Date, c.High, c.Low, c.Open, c.Close, c.Volume, c.QuoteVolume, c.WeightedAverage}
+	})
+}
+
+/*
+SyncCandles fetches the candles of a market from the last one stored, or from
+since when there is none. The last stored candle is fetched again, as it may
+have been incomplete.
+*/
+func (s *Store) SyncCandles(api *poloniexapi.PoloniexApi, pair string, period int64, since time.Time) error {
+	start, err := s.last(`SELECT MAX(date) FROM candles WHERE pair = ? AND period = ?`, pair, period)
+	if err != nil {
+		return err
+	}
+
+	if start == 0 {
+		start = since.Unix()
+	}
+
+	candles, err := api.ApiChartData(pair, start, time.Now().Unix(), period)
+	if err != nil {
+		return err
+	}
+
+	// No data is returned as a single candle dated 0.
+	if len(candles) == 1 && candles[0].Date == 0 {
+		return nil
+	}
+
+	return s.SaveCandles(pair, period, candles)
+}
+
+func (s *Store) Candles(pair string, period int64, start, end time.Time) ([]poloniexapi.ChartEntry, error) {
+	rows, err := s.db.Query(`SELECT date, high, low, open, close, volume, quote_volume, weighted_average
+		FROM candles WHERE pair = ? AND period = ? AND date >= ? AND date < ? ORDER BY date`,
+		pair, period, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]poloniexapi.ChartEntry, 0)
+	for rows.Next() {
+		var c poloniexapi.ChartEntry
+		if err = rows.Scan(&c.Date, &c.High, &c.Low, &c.Open, &c.Close, &c.Volume, &c.QuoteVolume, &c.WeightedAverage); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+
+	return out, rows.Err()
+}
+
+func (s *Store) SavePublicTrades(pair string, trades []poloniexapi.Trade) error {
+	return s.insert(`INSERT OR IGNORE INTO public_trades VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, len(trades), func(i int) []interface{} {
+		t := trades[i]
+		return []interface{}{pair, t.GlobalTradeID, t.TradeID, t.Date, tradeTime(t.Date), t.Type, t.Rate, t.Amount, t.Total}
+	})
+}
+
+// Longest range asked at once to returnTradeHistory, and the most trades it
+// returns at once.
+var (
+	PublicTradesWindow = 6 * time.Hour
+	PublicTradesLimit  = 50000
+)
+
+/*
+SyncPublicTrades fetches the public trades of a market from the last one
+stored (by GlobalTradeID), or from since when there is none, in windows of
+PublicTradesWindow. Windows with PublicTradesLimit trades or more are split in
+two and fetched again; it fails when a single second has that many.
+*/
+func (s *Store) SyncPublicTrades(api *poloniexapi.PoloniexApi, pair string, since time.Time) error {
+	start, err := s.last(`SELECT ts FROM public_trades WHERE pair = ? ORDER BY global_trade_id DESC LIMIT 1`, pair)
+	if err != nil {
+		return err
+	}
+
+	from := since
+	if start != 0 {
+		from = time.Unix(start, 0)
+	}
+
+	for now := time.Now(); from.Before(now); from = from.Add(PublicTradesWindow) {
+		to := from.Add(PublicTradesWindow)
+		if to.After(now) {
+			to = now
+		}
+
+		if err = s.syncPublicTrades(api, pair, from.Unix(), to.Unix()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) syncPublicTrades(api *poloniexapi.PoloniexApi, pair string, from, to int64) error {
+	trades, err := api.ApiPublicTradeHistory(pair, int(from), int(to))
+	if err != nil {
+		return err
+	}
+
+	if len(trades) < PublicTradesLimit {
+		return s.SavePublicTrades(pair, trades)
+	}
+
+	if to-from <= 1 {
+		return fmt.Errorf("Could not sync %s trades: %d or more at %s", pair, PublicTradesLimit, time.Unix(from, 0).UTC().Format(dateLayout))
+	}
+
+	middle := from + (to-from)/2
+	if err = s.syncPublicTrades(api, pair, from, middle); err != nil {
+		return err
+	}
+
+	return s.syncPublicTrades(api, pair, middle, to)
+}
+
+func (s *Store) PublicTrades(pair string, start, end time.Time) ([]poloniexapi.Trade, error) {
+	rows, err := s.db.Query(`SELECT global_trade_id, trade_id, date, type, rate, amount, total
+		FROM public_trades WHERE pair = ? AND ts >= ? AND ts < ? ORDER BY ts, trade_id`,
+		pair, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]poloniexapi.Trade, 0)
+	for rows.Next() {
+		var t poloniexapi.Trade
+		if err = rows.Scan(&t.GlobalTradeID, &t.TradeID, &t.Date, &t.Type, &t.Rate, &t.Amount, &t.Total); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+
+	return out, rows.Err()
+}
@@ -0,0 +1,175 @@
+/*
+Package store keeps market data and account history in a local SQLite
+database, so that it is not downloaded again on each run. It uses the pure Go
+driver modernc.org/sqlite, so no C compiler is needed.
+
+	db, err := store.Open("poloniex.db")
+	defer db.Close()
+
+	err = db.SyncCandles(api, "BTC_XMR", 300, time.Now().AddDate(0, -1, 0))
+	candles, err := db.Candles("BTC_XMR", 300, start, end)
+
+The Sync* methods only fetch what is missing: from the last stored trade,
+candle, deposit... to now. Rows already stored are ignored, so overlapping
+syncs are safe.
+*/
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Layout of the dates of trades and loans.
+const dateLayout = "2006-01-02 15:04:05"
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS tickers (
+		time INTEGER NOT NULL,
+		pair TEXT NOT NULL,
+		last REAL, lowest_ask REAL, highest_bid REAL, percent_change REAL,
+		base_volume REAL, quote_volume REAL, is_frozen INTEGER,
+		high_24hr REAL, low_24hr REAL,
+		PRIMARY KEY (pair, time)
+	)`,
+	`CREATE TABLE IF NOT EXISTS candles (
+		pair TEXT NOT NULL,
+		period INTEGER NOT NULL,
+		date INTEGER NOT NULL,
+		high REAL, low REAL, open REAL, close REAL,
+		volume REAL, quote_volume REAL, weighted_average REAL,
+		PRIMARY KEY (pair, period, date)
+	)`,
+	`CREATE TABLE IF NOT EXISTS public_trades (
+		pair TEXT NOT NULL,
+		global_trade_id INTEGER,
+		trade_id INTEGER NOT NULL,
+		date TEXT, ts INTEGER,
+		type TEXT, rate REAL, amount REAL, total REAL,
+		PRIMARY KEY (pair, trade_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS public_trades_ts ON public_trades (pair, ts)`,
+	`CREATE TABLE IF NOT EXISTS private_trades (
+		pair TEXT NOT NULL,
+		global_trade_id INTEGER,
+		trade_id INTEGER NOT NULL,
+		date TEXT, ts INTEGER,
+		type TEXT, rate REAL, amount REAL, total REAL, fee REAL,
+		order_number INTEGER, category TEXT, client_order_id INTEGER,
+		PRIMARY KEY (pair, trade_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS private_trades_ts ON private_trades (ts)`,
+	`CREATE TABLE IF NOT EXISTS orders (
+		order_number TEXT PRIMARY KEY,
+		pair TEXT NOT NULL,
+		type TEXT, rate REAL, starting_amount REAL, amount REAL, total REAL,
+		date TEXT, margin INTEGER, client_order_id INTEGER,
+		open INTEGER NOT NULL,
+		updated INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS deposits (
+		currency TEXT NOT NULL,
+		txid TEXT NOT NULL,
+		address TEXT,
+		amount REAL, confirmations INTEGER,
+		timestamp INTEGER, status TEXT,
+		PRIMARY KEY (currency, txid, address)
+	)`,
+	`CREATE TABLE IF NOT EXISTS withdrawals (
+		withdrawal_number INTEGER PRIMARY KEY,
+		currency TEXT, address TEXT, amount REAL,
+		timestamp INTEGER, status TEXT, ip_address TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS loans (
+		id INTEGER PRIMARY KEY,
+		currency TEXT, rate REAL, amount REAL, duration REAL,
+		interest REAL, fee REAL, earned REAL,
+		open TEXT, close TEXT, close_ts INTEGER
+	)`,
+}
+
+// Store is a SQLite database of Poloniex data.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database at path, and creates missing tables.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer.
+	db.SetMaxOpenConns(1)
+
+	for _, statement := range append([]string{"PRAGMA journal_mode=WAL"}, schema...) {
+		if _, err = db.Exec(statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("Could not initialize database %s (%s)", path, err.Error())
+		}
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB gives access to the database for queries not covered by Store.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// insert runs statement once per row in a transaction.
+func (s *Store) insert(statement string, n int, row func(i int) []interface{}) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(statement)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for i := 0; i < n; i++ {
+		if _, err = stmt.Exec(row(i)...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// last returns the single value of query, or zero on an empty table.
+func (s *Store) last(query string, args ...interface{}) (int64, error) {
+	var value sql.NullInt64
+
+	err := s.db.QueryRow(query, args...).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return value.Int64, nil
+}
+
+func tradeTime(date string) int64 {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return 0
+	}
+
+	return t.Unix()
+}
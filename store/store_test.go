@@ -0,0 +1,251 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/internal/polotest"
+)
+
+func testStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "poloniex.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+// smallHistory makes the exchange return few rows at once, and the syncs
+// ask short windows.
+func smallHistory(t *testing.T) {
+	limit, window := poloniexapi.HistoryLimit, poloniexapi.HistoryWindow
+	poloniexapi.HistoryLimit, poloniexapi.HistoryWindow = 3, 24*time.Hour
+	t.Cleanup(func() { poloniexapi.HistoryLimit, poloniexapi.HistoryWindow = limit, window })
+}
+
+// within returns the rows at times between the start and end of params, at
+// most the limit asked, as the exchange does.
+func within(t *testing.T, times []time.Time, params url.Values, row func(i int, at time.Time) string) []string {
+	from, _ := strconv.ParseInt(params.Get("start"), 10, 64)
+	to, _ := strconv.ParseInt(params.Get("end"), 10, 64)
+
+	limit, err := strconv.Atoi(params.Get("limit"))
+	if err != nil {
+		t.Errorf("%s asked without limit", params.Get("command"))
+		limit = 1
+	}
+
+	out := make([]string, 0)
+	for i, at := range times {
+		if at.Unix() >= from && at.Unix() <= to && len(out) < limit {
+			out = append(out, row(i, at))
+		}
+	}
+
+	return out
+}
+
+func TestSyncPrivateTrades(t *testing.T) {
+	smallHistory(t)
+
+	since := time.Now().Truncate(time.Second).Add(-96 * time.Hour)
+
+	// A busy hour with more trades than returned at once, then one a day.
+	var times []time.Time
+	for i := 0; i < 8; i++ {
+		times = append(times, since.Add(time.Hour+time.Duration(i)*time.Minute))
+	}
+	for i := 1; i < 4; i++ {
+		times = append(times, since.Add(time.Duration(i)*24*time.Hour+time.Hour))
+	}
+
+	var starts []int64
+	s := testStore(t)
+	api := polotest.Api(func(command string, params url.Values) string {
+		if command != poloniexapi.CMD_PRIVATE_TRADE_HISTORY {
+			return `{"error":"unexpected command"}`
+		}
+
+		start, _ := strconv.ParseInt(params.Get("start"), 10, 64)
+		starts = append(starts, start)
+
+		trades := within(t, times, params, func(i int, at time.Time) string {
+			return fmt.Sprintf(`{"globalTradeID":%d,"tradeID":%d,"date":%q,"type":"buy","rate":"0.01","amount":"1","total":"0.01","fee":"0"}`,
+				100+i, i, at.UTC().Format(dateLayout))
+		})
+		if len(trades) == 0 {
+			return `[]`
+		}
+		return `{"BTC_XMR":[` + strings.Join(trades, ",") + `]}`
+	})
+
+	if err := s.SyncPrivateTrades(api, since); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := s.PrivateTrades(since, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored["BTC_XMR"]) != len(times) {
+		t.Fatalf("stored %d trades, want %d", len(stored["BTC_XMR"]), len(times))
+	}
+
+	// Synced again from the last trade.
+	starts = starts[:0]
+	if err = s.SyncPrivateTrades(api, since); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(starts) == 0 || starts[0] != times[len(times)-1].Unix() {
+		t.Errorf("synced again from %v, want %d", starts, times[len(times)-1].Unix())
+	}
+}
+
+func TestSyncPrivateTradesFull(t *testing.T) {
+	smallHistory(t)
+
+	since := time.Now().Truncate(time.Second).Add(-time.Hour)
+
+	// More trades in a second than returned at once.
+	times := make([]time.Time, poloniexapi.HistoryLimit)
+	for i := range times {
+		times[i] = since.Add(time.Minute)
+	}
+
+	s := testStore(t)
+	api := polotest.Api(func(command string, params url.Values) string {
+		trades := within(t, times, params, func(i int, at time.Time) string {
+			return fmt.Sprintf(`{"globalTradeID":%d,"tradeID":%d,"date":%q,"type":"buy","rate":"0.01","amount":"1","total":"0.01","fee":"0"}`,
+				100+i, i, at.UTC().Format(dateLayout))
+		})
+		if len(trades) == 0 {
+			return `[]`
+		}
+		return `{"BTC_XMR":[` + strings.Join(trades, ",") + `]}`
+	})
+
+	if err := s.SyncPrivateTrades(api, since); err == nil {
+		t.Error("no error when trades could not all be fetched")
+	}
+}
+
+// publicTrades answers returnTradeHistory on BTC_XMR with the trades at times
+// between start and end, at most PublicTradesLimit.
+func publicTrades(times []time.Time) polotest.Exchange {
+	return func(command string, params url.Values) string {
+		if command != poloniexapi.CMD_PUBLIC_TRADE_HISTORY || params.Get("currencyPair") != "BTC_XMR" {
+			return `{"error":"unexpected command"}`
+		}
+
+		from, _ := strconv.ParseInt(params.Get("start"), 10, 64)
+		to, _ := strconv.ParseInt(params.Get("end"), 10, 64)
+
+		trades := make([]string, 0)
+		for i, at := range times {
+			if at.Unix() >= from && at.Unix() <= to && len(trades) < PublicTradesLimit {
+				trades = append(trades, fmt.Sprintf(`{"globalTradeID":%d,"tradeID":%d,"date":%q,"type":"buy","rate":"0.01","amount":"1","total":"0.01"}`,
+					100+i, i, at.UTC().Format(dateLayout)))
+			}
+		}
+
+		return `[` + strings.Join(trades, ",") + `]`
+	}
+}
+
+func smallPublicTrades(t *testing.T) {
+	limit, window := PublicTradesLimit, PublicTradesWindow
+	PublicTradesLimit, PublicTradesWindow = 3, 24*time.Hour
+	t.Cleanup(func() { PublicTradesLimit, PublicTradesWindow = limit, window })
+}
+
+func TestSyncPublicTrades(t *testing.T) {
+	smallPublicTrades(t)
+
+	since := time.Now().Truncate(time.Second).Add(-72 * time.Hour)
+
+	// A busy hour with more trades than returned at once, then one a day.
+	var times []time.Time
+	for i := 0; i < 8; i++ {
+		times = append(times, since.Add(time.Hour+time.Duration(i)*time.Minute))
+	}
+	for i := 1; i < 3; i++ {
+		times = append(times, since.Add(time.Duration(i)*24*time.Hour+time.Hour))
+	}
+
+	s := testStore(t)
+	if err := s.SyncPublicTrades(polotest.Api(publicTrades(times)), "BTC_XMR", since); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := s.PublicTrades("BTC_XMR", since, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored) != len(times) {
+		t.Errorf("stored %d trades, want %d", len(stored), len(times))
+	}
+}
+
+func TestSyncPublicTradesFull(t *testing.T) {
+	smallPublicTrades(t)
+
+	since := time.Now().Truncate(time.Second).Add(-time.Hour)
+
+	// More trades in a second than returned at once.
+	times := make([]time.Time, PublicTradesLimit)
+	for i := range times {
+		times[i] = since.Add(time.Minute)
+	}
+
+	s := testStore(t)
+	if err := s.SyncPublicTrades(polotest.Api(publicTrades(times)), "BTC_XMR", since); err == nil {
+		t.Error("no error when trades could not all be fetched")
+	}
+}
+
+func TestSyncLoans(t *testing.T) {
+	smallHistory(t)
+
+	since := time.Now().Truncate(time.Second).Add(-72 * time.Hour)
+
+	var times []time.Time
+	for i := 0; i < 7; i++ {
+		times = append(times, since.Add(time.Duration(i)*10*time.Hour))
+	}
+
+	s := testStore(t)
+	api := polotest.Api(func(command string, params url.Values) string {
+		if command != poloniexapi.CMD_PRIVATE_LENDING_HISTORY {
+			return `{"error":"unexpected command"}`
+		}
+
+		return `[` + strings.Join(within(t, times, params, func(i int, at time.Time) string {
+			return fmt.Sprintf(`{"id":%d,"currency":"BTC","rate":"0.0001","amount":"1","interest":"0.0001","close":%q}`,
+				i, at.UTC().Format(dateLayout))
+		}), ",") + `]`
+	})
+
+	if err := s.SyncLoans(api, since); err != nil {
+		t.Fatal(err)
+	}
+
+	loans, err := s.Loans(since, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loans) != len(times) {
+		t.Errorf("stored %d loans, want %d", len(loans), len(times))
+	}
+}
@@ -0,0 +1,185 @@
+package poloniexapi
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// TickerEvent is sent on the channel returned by PollTicker.
+type TickerEvent struct {
+	Time    time.Time
+	Tickers map[string]Ticker
+	Err     error
+}
+
+// OrderBookEvent is sent on the channel returned by PollOrderBook.
+type OrderBookEvent struct {
+	Time time.Time
+	Pair string
+	Book OrderBookEntry
+	Err  error
+}
+
+// TradesEvent is sent on the channel returned by PollTrades.
+type TradesEvent struct {
+	Time   time.Time
+	Pair   string
+	Trades []Trade
+	Err    error
+}
+
+/*
+PollTicker calls ApiPublicTicker every interval and sends the result on the
+returned channel, until ctx is done. The first event is sent right away.
+Errors are delivered in the Err field of the event and do not stop polling.
+*/
+func (api *PoloniexApi) PollTicker(ctx context.Context, interval time.Duration) <-chan TickerEvent {
+	out := make(chan TickerEvent)
+
+	go poll(ctx, interval, func() bool {
+		tickers, err := api.ApiPublicTickerContext(ctx)
+
+		select {
+		case out <- TickerEvent{Time: time.Now(), Tickers: tickers, Err: err}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(out) })
+
+	return out
+}
+
+/*
+PollOrderBook calls ApiPublicOrderBook for a single pair every interval and
+sends the result on the returned channel, until ctx is done.
+*/
+func (api *PoloniexApi) PollOrderBook(ctx context.Context, pair string, depth int, interval time.Duration) <-chan OrderBookEvent {
+	out := make(chan OrderBookEvent)
+
+	go poll(ctx, interval, func() bool {
+		books, err := api.ApiPublicOrderBookContext(ctx, pair, depth)
+
+		select {
+		case out <- OrderBookEvent{Time: time.Now(), Pair: pair, Book: books[pair], Err: err}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(out) })
+
+	return out
+}
+
+/*
+PollTrades calls ApiPublicTradeHistory for a single pair every interval and
+sends the trades not seen before, oldest first. The first event holds the
+trades returned by the first call.
+*/
+func (api *PoloniexApi) PollTrades(ctx context.Context, pair string, interval time.Duration) <-chan TradesEvent {
+	out := make(chan TradesEvent)
+	var last int64
+
+	go poll(ctx, interval, func() bool {
+		trades, err := api.ApiPublicTradeHistoryContext(ctx, pair, 0, 0)
+
+		fresh := make([]Trade, 0, len(trades))
+		for i := len(trades) - 1; i >= 0; i-- {
+			if trades[i].TradeID > last {
+				fresh = append(fresh, trades[i])
+				last = trades[i].TradeID
+			}
+		}
+
+		if err == nil && len(fresh) == 0 {
+			return true
+		}
+
+		select {
+		case out <- TradesEvent{Time: time.Now(), Pair: pair, Trades: fresh, Err: err}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(out) })
+
+	return out
+}
+
+/*
+PollPrivateTrades calls ApiPrivateTradeHistory every interval and sends your
+trades made since the call, oldest first, one event per market. pair may be
+"all". Only events with trades, or errors, are sent.
+*/
+func (api *PoloniexApi) PollPrivateTrades(ctx context.Context, pair string, interval time.Duration) <-chan TradesEvent {
+	out := make(chan TradesEvent)
+	since := time.Now().Add(-time.Second)
+	last := make(map[string]int64)
+
+	send := func(ev TradesEvent) bool {
+		select {
+		case out <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go poll(ctx, interval, func() bool {
+		trades, err := api.ApiPrivateTradeHistoryContext(ctx, pair, int(since.Unix()), 0)
+		if err != nil {
+			return send(TradesEvent{Time: time.Now(), Pair: pair, Err: err})
+		}
+
+		pairs := make([]string, 0, len(trades))
+		for p := range trades {
+			pairs = append(pairs, p)
+		}
+		sort.Strings(pairs)
+
+		for _, p := range pairs {
+			fresh := make([]Trade, 0)
+			for i := len(trades[p]) - 1; i >= 0; i-- {
+				if t := trades[p][i]; t.TradeID > last[p] {
+					fresh = append(fresh, t)
+					last[p] = t.TradeID
+
+					// Later calls only ask for trades from the last seen.
+					if date, err := time.Parse(TradeDateLayout, t.Date); err == nil && date.After(since) {
+						since = date
+					}
+				}
+			}
+
+			if len(fresh) > 0 && !send(TradesEvent{Time: time.Now(), Pair: p, Trades: fresh}) {
+				return false
+			}
+		}
+
+		return true
+	}, func() { close(out) })
+
+	return out
+}
+
+// poll runs fn immediately then on every tick, until ctx is done or fn
+// returns false.
+func poll(ctx context.Context, interval time.Duration, fn func() bool, done func()) {
+	defer done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !fn() {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package tax
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+/*
+WriteGeneric writes the activity as CSV, one line per trade, deposit,
+withdrawal or lending interest, with every field the API returns that matters
+for accounting. Dates are UTC.
+*/
+func WriteGeneric(w io.Writer, a *Activity) error {
+	entries, err := a.entries()
+	if err != nil {
+		return err
+	}
+
+	return writeCSV(w, []string{
+		"date", "type", "market", "rate",
+		"sent_amount", "sent_currency", "received_amount", "received_currency",
+		"fee_amount", "fee_currency", "id", "txid",
+	}, len(entries), func(i int) []string {
+		e := entries[i]
+
+		rate := ""
+		if e.rate != 0 {
+			rate = formatAmount(e.rate)
+		}
+
+		return []string{
+			e.time.Format("2006-01-02T15:04:05Z"), e.kind.String(), e.pair, rate,
+			formatAmount(e.sentAmount), currencyIf(e.sentAmount, e.sentCurrency),
+			formatAmount(e.receivedAmount), currencyIf(e.receivedAmount, e.receivedCurrency),
+			formatAmount(e.feeAmount), currencyIf(e.feeAmount, e.feeCurrency),
+			e.id, e.txid,
+		}
+	})
+}
+
+// WriteKoinly writes the activity in the Koinly universal CSV layout.
+func WriteKoinly(w io.Writer, a *Activity) error {
+	entries, err := a.entries()
+	if err != nil {
+		return err
+	}
+
+	return writeCSV(w, []string{
+		"Date", "Sent Amount", "Sent Currency", "Received Amount", "Received Currency",
+		"Fee Amount", "Fee Currency", "Net Worth Amount", "Net Worth Currency",
+		"Label", "Description", "TxHash",
+	}, len(entries), func(i int) []string {
+		e := entries[i]
+
+		label, description := "", "Poloniex "+e.kind.String()
+		switch e.kind {
+		case kindBuy, kindSell:
+			description += " on " + e.pair + " (trade " + e.id + ")"
+		case kindLending:
+			label = "lending interest"
+			description += " (loan " + e.id + ")"
+		}
+
+		return []string{
+			e.time.Format("2006-01-02 15:04:05") + " UTC",
+			formatAmount(e.sentAmount), currencyIf(e.sentAmount, e.sentCurrency),
+			formatAmount(e.receivedAmount), currencyIf(e.receivedAmount, e.receivedCurrency),
+			formatAmount(e.feeAmount), currencyIf(e.feeAmount, e.feeCurrency),
+			"", "",
+			label, description, e.txid,
+		}
+	})
+}
+
+// WriteCoinTracking writes the activity in the CoinTracking CSV import layout.
+func WriteCoinTracking(w io.Writer, a *Activity) error {
+	entries, err := a.entries()
+	if err != nil {
+		return err
+	}
+
+	types := map[kind]string{
+		kindBuy:        "Trade",
+		kindSell:       "Trade",
+		kindDeposit:    "Deposit",
+		kindWithdrawal: "Withdrawal",
+		kindLending:    "Lending Income",
+	}
+
+	return writeCSV(w, []string{
+		"Type", "Buy Amount", "Buy Currency", "Sell Amount", "Sell Currency",
+		"Fee", "Fee Currency", "Exchange", "Trade-Group", "Comment", "Date",
+	}, len(entries), func(i int) []string {
+		e := entries[i]
+
+		comment := strings.TrimSpace(e.kind.String() + " " + e.pair + " " + e.id)
+		if e.txid != "" {
+			comment += " " + e.txid
+		}
+
+		return []string{
+			types[e.kind],
+			formatAmount(e.receivedAmount), currencyIf(e.receivedAmount, e.receivedCurrency),
+			formatAmount(e.sentAmount), currencyIf(e.sentAmount, e.sentCurrency),
+			formatAmount(e.feeAmount), currencyIf(e.feeAmount, e.feeCurrency),
+			"Poloniex", "", comment,
+			e.time.Format("2006-01-02 15:04:05"),
+		}
+	})
+}
+
+func writeCSV(w io.Writer, header []string, n int, row func(i int) []string) error {
+	out := csv.NewWriter(w)
+
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for i := 0; i < n; i++ {
+		if err := out.Write(row(i)); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
@@ -0,0 +1,136 @@
+package tax
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Holding period after which a gain is long term in most jurisdictions.
+const OneYear = 365 * 24 * time.Hour
+
+type Term string
+
+const (
+	ShortTerm Term = "short"
+	LongTerm  Term = "long"
+)
+
+/*
+Gain is the disposal of a lot, with the term of the gain. Proceeds, Cost and
+Realized are in Quote, whatever the market the lot was bought or sold on.
+*/
+type Gain struct {
+	poloniexapi.Disposal
+	Quote string
+	Term  Term
+}
+
+// GainTotals sums gains in one currency.
+type GainTotals struct {
+	Currency string
+	Short    float64
+	Long     float64
+	Unknown  int     // disposals of coins whose cost is unknown, not in Short nor Long
+	Proceeds float64 // of these disposals
+}
+
+/*
+CapitalGains matches disposals to lots of the same currency with method, in
+quote (see poloniexapi.PnLEngine), and tells for each disposal whether the lot
+was held longer than longTerm. A lot bought on one market and sold on another
+keeps its cost, and exchanging a coin for another disposes of it: prices
+values these exchanges in quote, and may be nil to carry the cost of the coin
+spent over to the coin received. Deposits are transfers, so coins deposited
+then sold have an unknown cost.
+*/
+func CapitalGains(a *Activity, method poloniexapi.CostMethod, quote string, prices poloniexapi.PriceFunc, longTerm time.Duration) ([]Gain, error) {
+	engine := poloniexapi.NewPnLEngine(method, quote)
+	engine.Prices = prices
+
+	for pair, trades := range a.Trades {
+		if err := engine.AddTrades(pair, trades); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.Transfers != nil {
+		engine.AddTransfers(a.Transfers)
+	}
+
+	report := engine.Report(nil, poloniexapi.PeriodAll)
+
+	out := make([]Gain, 0, len(report.Disposals))
+	for _, d := range report.Disposals {
+		term := ShortTerm
+		if d.Time.Sub(d.Acquired) > longTerm {
+			term = LongTerm
+		}
+
+		out = append(out, Gain{Disposal: d, Quote: quote, Term: term})
+	}
+
+	return out, nil
+}
+
+// SumGains totals gains by the currency they are in.
+func SumGains(gains []Gain) []GainTotals {
+	totals := make(map[string]*GainTotals)
+
+	for _, g := range gains {
+		t := totals[g.Quote]
+		if t == nil {
+			t = &GainTotals{Currency: g.Quote}
+			totals[g.Quote] = t
+		}
+
+		switch {
+		case !g.CostKnown:
+			t.Unknown++
+			t.Proceeds += g.Proceeds
+		case g.Term == LongTerm:
+			t.Long += g.Realized
+		default:
+			t.Short += g.Realized
+		}
+	}
+
+	out := make([]GainTotals, 0, len(totals))
+	for _, t := range totals {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Currency < out[j].Currency })
+
+	return out
+}
+
+// WriteCapitalGains writes one CSV line per disposal, see CapitalGains.
+func WriteCapitalGains(w io.Writer, a *Activity, method poloniexapi.CostMethod, quote string, prices poloniexapi.PriceFunc, longTerm time.Duration) error {
+	gains, err := CapitalGains(a, method, quote, prices, longTerm)
+	if err != nil {
+		return err
+	}
+
+	return writeCSV(w, []string{
+		"date_acquired", "date_sold", "market", "sold_currency", "amount",
+		"proceeds", "cost", "gain", "currency", "term", "cost_known", "carried", "trade_id",
+	}, len(gains), func(i int) []string {
+		g := gains[i]
+
+		cost, gain := "", ""
+		if g.CostKnown {
+			cost, gain = formatAmount(g.Cost), strconv.FormatFloat(g.Realized, 'f', 8, 64)
+		}
+
+		return []string{
+			g.Acquired.Format("2006-01-02T15:04:05Z"), g.Time.Format("2006-01-02T15:04:05Z"),
+			g.Pair, g.Currency, formatAmount(g.Amount),
+			formatAmount(g.Proceeds), cost, gain,
+			g.Quote, string(g.Term), strconv.FormatBool(g.CostKnown), strconv.FormatBool(g.Carried),
+			strconv.FormatInt(g.TradeID, 10),
+		}
+	})
+}
@@ -0,0 +1,199 @@
+/*
+Package tax exports Poloniex activity for accounting: trades, deposits,
+withdrawals and lending interest in a generic CSV layout, in the layouts
+imported by Koinly and CoinTracking, and capital gains split between short and
+long term:
+
+	activity, err := tax.Load(api, start, end)
+	err = tax.WriteKoinly(os.Stdout, activity)
+	err = tax.WriteCapitalGains(os.Stdout, activity, poloniexapi.FIFO, "USDT", nil, tax.OneYear)
+
+Amounts of trades are exported before fees, with the fee in its own columns:
+the fee of a buy is in the bought currency, the fee of a sell in the base
+currency of the market.
+*/
+package tax
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+)
+
+// Activity is everything exported, as returned by the API.
+type Activity struct {
+	Trades    map[string][]poloniexapi.Trade
+	Transfers *poloniexapi.DepositWithdrawal
+	Lending   []poloniexapi.LendingHistoryEntry
+}
+
+/*
+Load fetches the activity of all markets between start and end. Trades and
+loans are fetched in windows (see poloniexapi.LoadTradeHistory), so none are
+left out however many there are.
+*/
+func Load(api *poloniexapi.PoloniexApi, start, end time.Time) (*Activity, error) {
+	trades, err := api.LoadTradeHistory("all", start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := api.ApiPrivateDepositWithdrawals(start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	lending, err := api.LoadLendingHistory(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Activity{Trades: trades, Transfers: transfers, Lending: lending}, nil
+}
+
+type kind int
+
+const (
+	kindBuy kind = iota
+	kindSell
+	kindDeposit
+	kindWithdrawal
+	kindLending
+)
+
+func (k kind) String() string {
+	return [...]string{"buy", "sell", "deposit", "withdrawal", "lending"}[k]
+}
+
+/*
+entry is one line of activity, common to every layout: what was sent and
+received, and the fee.
+*/
+type entry struct {
+	time             time.Time
+	kind             kind
+	pair             string
+	rate             float64
+	sentAmount       float64
+	sentCurrency     string
+	receivedAmount   float64
+	receivedCurrency string
+	feeAmount        float64
+	feeCurrency      string
+	id               string
+	txid             string
+}
+
+// entries returns the activity as entries, sorted by date.
+func (a *Activity) entries() ([]entry, error) {
+	out := make([]entry, 0)
+
+	for pair, trades := range a.Trades {
+		parts := strings.SplitN(pair, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid currency pair %q", pair)
+		}
+		base, quote := parts[0], parts[1]
+
+		for _, t := range trades {
+			date, err := time.Parse(poloniexapi.TradeDateLayout, t.Date)
+			if err != nil {
+				return nil, fmt.Errorf("Could not parse date of trade %d (%s)", t.TradeID, err.Error())
+			}
+
+			e := entry{
+				time: date,
+				pair: pair,
+				rate: t.Rate,
+				id:   strconv.FormatInt(t.TradeID, 10),
+			}
+
+			if t.Type == "buy" {
+				e.kind = kindBuy
+				e.sentAmount, e.sentCurrency = t.Total, base
+				e.receivedAmount, e.receivedCurrency = t.Amount, quote
+				e.feeAmount, e.feeCurrency = t.Amount*t.Fee, quote
+			} else {
+				e.kind = kindSell
+				e.sentAmount, e.sentCurrency = t.Amount, quote
+				e.receivedAmount, e.receivedCurrency = t.Total, base
+				e.feeAmount, e.feeCurrency = t.Total*t.Fee, base
+			}
+
+			out = append(out, e)
+		}
+	}
+
+	if a.Transfers != nil {
+		for _, d := range a.Transfers.Deposits {
+			if d.Status != "COMPLETE" {
+				continue
+			}
+
+			out = append(out, entry{
+				time:             time.Unix(d.Timestamp, 0).UTC(),
+				kind:             kindDeposit,
+				receivedAmount:   d.Amount,
+				receivedCurrency: d.Currency,
+				txid:             d.Txid,
+			})
+		}
+
+		for _, w := range a.Transfers.Withdrawals {
+			if !strings.HasPrefix(w.Status, "COMPLETE") {
+				continue
+			}
+
+			out = append(out, entry{
+				time:         time.Unix(w.Timestamp, 0).UTC(),
+				kind:         kindWithdrawal,
+				sentAmount:   w.Amount,
+				sentCurrency: w.Currency,
+				id:           strconv.FormatInt(w.WithdrawalNumber, 10),
+				txid:         strings.TrimSpace(strings.TrimPrefix(w.Status, "COMPLETE:")),
+			})
+		}
+	}
+
+	for _, l := range a.Lending {
+		date, err := time.Parse(poloniexapi.TradeDateLayout, l.Close)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse close date of loan %d (%s)", l.Id, err.Error())
+		}
+
+		// Interest is reported gross, the fee being negative.
+		out = append(out, entry{
+			time:             date,
+			kind:             kindLending,
+			receivedAmount:   l.Interest,
+			receivedCurrency: l.Currency,
+			feeAmount:        -l.Fee,
+			feeCurrency:      l.Currency,
+			id:               strconv.FormatInt(l.Id, 10),
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].time.Before(out[j].time) })
+
+	return out, nil
+}
+
+func formatAmount(f float64) string {
+	if f == 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(f, 'f', 8, 64)
+}
+
+func currencyIf(amount float64, currency string) string {
+	if amount == 0 {
+		return ""
+	}
+
+	return currency
+}
@@ -0,0 +1,204 @@
+package tax
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	poloniexapi "github.com/mycroft/poloniex-api"
+	"github.com/mycroft/poloniex-api/internal/polotest"
+)
+
+func testActivity() *Activity {
+	return &Activity{
+		Trades: map[string][]poloniexapi.Trade{
+			"BTC_XMR": {
+				{TradeID: 1, Date: "2016-01-01 00:00:00", Type: "buy", Rate: 0.01, Amount: 10, Total: 0.1},
+				{TradeID: 2, Date: "2017-01-01 00:00:00", Type: "buy", Rate: 0.02, Amount: 10, Total: 0.2},
+				{TradeID: 3, Date: "2017-06-01 00:00:00", Type: "sell", Rate: 0.03, Amount: 15, Total: 0.45, Fee: 0.002},
+			},
+		},
+		Transfers: &poloniexapi.DepositWithdrawal{
+			Deposits: []poloniexapi.Deposit{
+				{Currency: "BTC", Amount: 1, Timestamp: 1450000000, Status: "COMPLETE", Txid: "abc"},
+			},
+		},
+		Lending: []poloniexapi.LendingHistoryEntry{
+			{Id: 7, Currency: "BTC", Interest: 0.0001, Fee: -0.000015, Close: "2017-02-01 00:00:00"},
+		},
+	}
+}
+
+func TestCapitalGainsTerms(t *testing.T) {
+	gains, err := CapitalGains(testActivity(), poloniexapi.FIFO, "BTC", nil, OneYear)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gains) != 2 {
+		t.Fatalf("expected 2 disposals, got %d", len(gains))
+	}
+
+	if gains[0].Term != LongTerm || gains[0].Amount != 10 {
+		t.Errorf("first lot should be long term, got %+v", gains[0])
+	}
+
+	if gains[1].Term != ShortTerm || gains[1].Amount != 5 {
+		t.Errorf("second lot should be short term, got %+v", gains[1])
+	}
+
+	totals := SumGains(gains)
+	if len(totals) != 1 || totals[0].Currency != "BTC" {
+		t.Fatalf("unexpected totals %+v", totals)
+	}
+
+	if total := totals[0].Short + totals[0].Long; total < 0.2490 || total > 0.2492 {
+		t.Errorf("expected a gain of 0.2491 BTC, got %f", total)
+	}
+}
+
+func TestCapitalGainsAcrossMarkets(t *testing.T) {
+	// 1 BTC bought for 8000 USDT, half of it exchanged for ETH, sold for
+	// 6000 USDT a year and a half later.
+	a := &Activity{Trades: map[string][]poloniexapi.Trade{
+		"USDT_BTC": {{TradeID: 1, Date: "2016-01-01 00:00:00", Type: "buy", Rate: 8000, Amount: 1, Total: 8000}},
+		"BTC_ETH":  {{TradeID: 2, Date: "2016-02-01 00:00:00", Type: "buy", Rate: 0.05, Amount: 10, Total: 0.5}},
+		"USDT_ETH": {{TradeID: 3, Date: "2017-06-01 00:00:00", Type: "sell", Rate: 600, Amount: 10, Total: 6000}},
+	}}
+
+	gains, err := CapitalGains(a, poloniexapi.FIFO, "USDT", nil, OneYear)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gains) != 2 {
+		t.Fatalf("expected 2 disposals, got %+v", gains)
+	}
+
+	// Without a price, the BTC spent carries its cost to the ETH.
+	if g := gains[0]; g.Currency != "BTC" || !g.Carried || g.Cost != 4000 || g.Realized != 0 {
+		t.Errorf("BTC should be exchanged at its cost, got %+v", g)
+	}
+
+	if g := gains[1]; g.Currency != "ETH" || g.Cost != 4000 || g.Realized != 2000 || g.Term != LongTerm || g.Quote != "USDT" {
+		t.Errorf("ETH should have cost the 4000 USDT of the BTC, got %+v", g)
+	}
+
+	totals := SumGains(gains)
+	if len(totals) != 1 || totals[0].Currency != "USDT" || totals[0].Long != 2000 || totals[0].Short != 0 {
+		t.Errorf("expected a single long term gain of 2000 USDT, got %+v", totals)
+	}
+
+	// At 10000 USDT the BTC, its exchange realizes 1000 and the ETH costs 5000.
+	prices := func(currency string, at time.Time) (float64, bool) { return 10000, currency == "BTC" }
+
+	gains, err = CapitalGains(a, poloniexapi.FIFO, "USDT", prices, OneYear)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gains) != 2 || gains[0].Realized != 1000 || gains[0].Term != ShortTerm || gains[1].Cost != 5000 || gains[1].Realized != 1000 {
+		t.Errorf("expected 1000 USDT on each disposal, got %+v", gains)
+	}
+}
+
+func TestWriteKoinly(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteKoinly(buf, testActivity()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected a header and 5 lines, got %d", len(lines))
+	}
+
+	if !strings.HasPrefix(lines[1], "2015-12-13 09:46:40 UTC,,,1.00000000,BTC,") {
+		t.Errorf("deposit should come first, got %s", lines[1])
+	}
+
+	if !strings.Contains(buf.String(), "0.00010000,BTC,0.00001500,BTC,,,lending interest") {
+		t.Errorf("lending interest missing:\n%s", buf.String())
+	}
+}
+
+func TestSumGainsUnknownCost(t *testing.T) {
+	a := testActivity()
+	a.Trades["BTC_XMR"] = append(a.Trades["BTC_XMR"], poloniexapi.Trade{TradeID: 4, Date: "2017-07-01 00:00:00", Type: "sell", Rate: 0.04, Amount: 10, Total: 0.4})
+
+	gains, err := CapitalGains(a, poloniexapi.FIFO, "BTC", nil, OneYear)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	totals := SumGains(gains)
+	if totals[0].Unknown != 1 || totals[0].Proceeds < 0.1999 || totals[0].Proceeds > 0.2001 {
+		t.Errorf("expected 0.2 BTC of unknown cost, got %+v", totals[0])
+	}
+
+	// 5 XMR left at 0.02 sold at 0.04, the rest has no cost.
+	if total := totals[0].Short + totals[0].Long; total < 0.3490 || total > 0.3492 {
+		t.Errorf("expected a gain of 0.3491 BTC, got %f", total)
+	}
+}
+
+func TestLoadWindows(t *testing.T) {
+	limit, window := poloniexapi.HistoryLimit, poloniexapi.HistoryWindow
+	poloniexapi.HistoryLimit, poloniexapi.HistoryWindow = 2, 24*time.Hour
+	defer func() { poloniexapi.HistoryLimit, poloniexapi.HistoryWindow = limit, window }()
+
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	var times []time.Time
+	for i := 0; i < 5; i++ {
+		times = append(times, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	// Answers at most HistoryLimit rows, like the exchange.
+	rows := func(params url.Values, row func(i int, at time.Time) string) []string {
+		from, _ := strconv.ParseInt(params.Get("start"), 10, 64)
+		to, _ := strconv.ParseInt(params.Get("end"), 10, 64)
+
+		var out []string
+		for i, at := range times {
+			if at.Unix() >= from && at.Unix() <= to && len(out) < poloniexapi.HistoryLimit {
+				out = append(out, row(i, at))
+			}
+		}
+		return out
+	}
+
+	api := polotest.Api(func(command string, params url.Values) string {
+		switch command {
+		case poloniexapi.CMD_PRIVATE_TRADE_HISTORY:
+			trades := rows(params, func(i int, at time.Time) string {
+				return fmt.Sprintf(`{"tradeID":%d,"date":%q,"type":"buy","rate":"0.01","amount":"1","total":"0.01","fee":"0"}`, i, at.Format(poloniexapi.TradeDateLayout))
+			})
+			if len(trades) == 0 {
+				return `[]`
+			}
+			return `{"BTC_XMR":[` + strings.Join(trades, ",") + `]}`
+
+		case poloniexapi.CMD_PRIVATE_LENDING_HISTORY:
+			return `[` + strings.Join(rows(params, func(i int, at time.Time) string {
+				return fmt.Sprintf(`{"id":%d,"currency":"BTC","interest":"0.0001","close":%q}`, i, at.Format(poloniexapi.TradeDateLayout))
+			}), ",") + `]`
+
+		case poloniexapi.CMD_PRIVATE_DEPOSIT_WITHDRAWALS:
+			return `{"deposits":[],"withdrawals":[]}`
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	a, err := Load(api, start, start.Add(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a.Trades["BTC_XMR"]) != len(times) || len(a.Lending) != len(times) {
+		t.Errorf("got %d trades and %d loans, want %d", len(a.Trades["BTC_XMR"]), len(a.Lending), len(times))
+	}
+}
@@ -0,0 +1,95 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name to give the tracer of PoloniexApi.Tracer, e.g.
+// otel.Tracer(poloniexapi.TracerName).
+const TracerName = "github.com/mycroft/poloniex-api"
+
+type attemptKey struct{}
+
+// withAttempt returns ctx for the requests of the retry number attempt of a
+// call, traced as such.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	if attempt == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+/*
+startSpan starts the span of a request to the exchange when a Tracer is set.
+The span is named after the command, and annotated with the market and the
+retry count when there are. A nil span is returned without Tracer.
+*/
+func (api *PoloniexApi) startSpan(ctx context.Context, command string, params url.Values) (context.Context, trace.Span) {
+	if api.Tracer == nil {
+		return ctx, nil
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("poloniex.command", command)}
+
+	if pair := params.Get("currencyPair"); pair != "" {
+		attrs = append(attrs, attribute.String("poloniex.pair", pair))
+	}
+
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		attrs = append(attrs, attribute.Int("poloniex.retry", attempt))
+	}
+
+	return api.Tracer.Start(ctx, "poloniex."+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// limiterWaited records on span the time spent waiting for the rate limiter.
+func limiterWaited(span trace.Span, wait time.Duration) {
+	if span != nil && wait > 0 {
+		span.AddEvent("ratelimiter.wait", trace.WithAttributes(attribute.Int64("poloniex.wait_ms", wait.Milliseconds())))
+	}
+}
+
+// endSpan ends span with the HTTP status of the answer, zero when none was
+// received, and the class of err.
+func endSpan(span trace.Span, status int, err error) {
+	if span == nil {
+		return
+	}
+
+	if status != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+
+	if err != nil {
+		span.SetAttributes(attribute.String("poloniex.error", errorClass(err)))
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// errorClass tells how a request failed: nonce or api when the exchange
+// refused it, request when no usable answer was received.
+func errorClass(err error) string {
+	var apiError *ApiError
+
+	switch {
+	case IsNonceError(err):
+		return "nonce"
+	case errors.As(err, &apiError):
+		return "api"
+	case IsAmbiguous(err):
+		return "request"
+	}
+
+	return "other"
+}
@@ -0,0 +1,614 @@
+package poloniexapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type TriggerKind string
+
+const (
+	StopLoss     TriggerKind = "stop-loss"
+	TakeProfit   TriggerKind = "take-profit"
+	TrailingStop TriggerKind = "trailing-stop"
+)
+
+var ErrInvalidTrigger = errors.New("invalid trigger")
+var ErrUnknownTrigger = errors.New("unknown trigger")
+
+// The order of a fired trigger is tried TriggerMaxAttempts times, waiting
+// TriggerRetryDelay after the first failure and twice as long after each
+// next one, before the trigger is marked failed.
+var TriggerMaxAttempts = 5
+var TriggerRetryDelay = 10 * time.Second
+
+/*
+Trigger is an order held locally until the market reaches a price.
+
+Sell triggers watch the highest bid, buy triggers the lowest ask:
+
+  - a stop-loss fires when the price crosses StopPrice against the position
+    (bid <= StopPrice for a sell, ask >= StopPrice for a buy),
+  - a take-profit fires when it crosses StopPrice in favour of the position
+    (bid >= StopPrice for a sell, ask <= StopPrice for a buy),
+  - a trailing stop follows the best price seen (Extreme) and fires when the
+    price moves back by TrailDelta, or by TrailPercent of Extreme.
+
+When fired, an order of Amount is placed at Rate, or when Rate is zero at the
+price that fired the trigger, moved by Slippage (a fraction, 0.01 for 1%) so
+that it crosses the book. Triggers with the same Group are one-cancels-other:
+when one of them fires, the others are removed.
+*/
+type Trigger struct {
+	ID           int64
+	Kind         TriggerKind
+	Pair         string
+	Side         OrderSide
+	Amount       float64
+	StopPrice    float64       `json:",omitempty"`
+	TrailDelta   float64       `json:",omitempty"`
+	TrailPercent float64       `json:",omitempty"`
+	Extreme      float64       `json:",omitempty"`
+	Rate         float64       `json:",omitempty"`
+	Slippage     float64       `json:",omitempty"`
+	Options      *OrderOptions `json:",omitempty"`
+	Group        string        `json:",omitempty"`
+	CreatedAt    time.Time
+
+	// Set when the trigger fired but its order is not known to be placed yet.
+	FiredAt   time.Time `json:",omitempty"`
+	FiredRate float64   `json:",omitempty"`
+
+	// Set when placing the order failed. Failed triggers are kept, with the
+	// last error, until cancelled.
+	Attempts int       `json:",omitempty"`
+	RetryAt  time.Time `json:",omitempty"`
+	Error    string    `json:",omitempty"`
+	Failed   bool      `json:",omitempty"`
+
+	// Loaded already fired: its order may have been sent before a restart.
+	restored bool
+}
+
+func (t *Trigger) Validate() error {
+	if t.Pair == "" {
+		return fmt.Errorf("%w: no currency pair", ErrInvalidTrigger)
+	}
+
+	if t.Side != SideBuy && t.Side != SideSell {
+		return fmt.Errorf("%w: unknown side %q", ErrInvalidTrigger, t.Side)
+	}
+
+	if t.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidTrigger)
+	}
+
+	if t.Rate < 0 || t.Slippage < 0 || t.Slippage >= 1 {
+		return fmt.Errorf("%w: rate must not be negative, slippage must be in [0, 1)", ErrInvalidTrigger)
+	}
+
+	switch t.Kind {
+	case StopLoss, TakeProfit:
+		if t.StopPrice <= 0 {
+			return fmt.Errorf("%w: %s needs a stop price", ErrInvalidTrigger, t.Kind)
+		}
+	case TrailingStop:
+		if (t.TrailDelta > 0) == (t.TrailPercent > 0) {
+			return fmt.Errorf("%w: trailing stop needs either a delta or a percent", ErrInvalidTrigger)
+		}
+		if t.TrailPercent >= 100 {
+			return fmt.Errorf("%w: trailing percent must be below 100", ErrInvalidTrigger)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidTrigger, t.Kind)
+	}
+
+	return t.Options.Validate()
+}
+
+// price returns the side of the book watched by the trigger.
+func (t *Trigger) price(ticker Ticker) float64 {
+	if t.Side == SideSell {
+		return ticker.HighestBid
+	}
+
+	return ticker.LowestAsk
+}
+
+// update follows the market for trailing stops, and tells if the trigger
+// fires at price. It returns true for changed when Extreme moved.
+func (t *Trigger) update(price float64) (fire, changed bool) {
+	switch t.Kind {
+	case StopLoss:
+		if t.Side == SideSell {
+			return price <= t.StopPrice, false
+		}
+		return price >= t.StopPrice, false
+
+	case TakeProfit:
+		if t.Side == SideSell {
+			return price >= t.StopPrice, false
+		}
+		return price <= t.StopPrice, false
+
+	case TrailingStop:
+		if t.Extreme == 0 ||
+			(t.Side == SideSell && price > t.Extreme) ||
+			(t.Side == SideBuy && price < t.Extreme) {
+			t.Extreme = price
+			changed = true
+		}
+
+		delta := t.TrailDelta
+		if t.TrailPercent > 0 {
+			delta = t.Extreme * t.TrailPercent / 100
+		}
+
+		if t.Side == SideSell {
+			return price <= t.Extreme-delta, changed
+		}
+		return price >= t.Extreme+delta, changed
+	}
+
+	return false, false
+}
+
+/*
+orderRate is the rate of the order placed when the trigger fires at price.
+Rates have 8 decimals: the slipped price is rounded away from the market, down
+for a sell and up for a buy, so that the order still crosses the book and
+passes the precision check.
+*/
+func (t *Trigger) orderRate(price float64) float64 {
+	if t.Rate != 0 {
+		return t.Rate
+	}
+
+	if t.Side == SideSell {
+		return math.Floor(price*(1-t.Slippage)*1e8) / 1e8
+	}
+
+	return math.Ceil(price*(1+t.Slippage)*1e8) / 1e8
+}
+
+// TriggerStore persists pending triggers.
+type TriggerStore interface {
+	Load() ([]Trigger, error)
+	Save([]Trigger) error
+}
+
+// FileTriggerStore keeps triggers in a JSON file, replaced atomically on each
+// save. A missing file means no triggers.
+type FileTriggerStore struct {
+	Path string
+}
+
+func (s FileTriggerStore) Load() ([]Trigger, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var triggers []Trigger
+	if err = json.Unmarshal(data, &triggers); err != nil {
+		return nil, fmt.Errorf("Could not parse triggers file %s (%s)", s.Path, err.Error())
+	}
+
+	return triggers, nil
+}
+
+func (s FileTriggerStore) Save(triggers []Trigger) error {
+	data, err := json.MarshalIndent(triggers, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// TriggerFired is passed to the OnFire callbacks. Rate is the rate of the order
+// sent. Err is set when the order could not be placed; the trigger is then kept
+// and retried after a delay, or marked failed.
+type TriggerFired struct {
+	Trigger Trigger
+	Rate    float64
+	Order   *Order
+	Err     error
+}
+
+/*
+TriggerEngine holds triggers and places their orders when the ticker reaches
+their conditions. Poloniex has no native stop orders, so triggers only fire
+while the engine runs.
+
+Every change is saved to the store before acting on it: a trigger is saved as
+fired, with a client order id, before its order is sent, so that after a crash
+or a restart the order is looked up instead of being placed twice.
+
+Orders are placed in the background, one goroutine per fired trigger, so that
+a slow or failing order does not hold back the others nor Check.
+*/
+type TriggerEngine struct {
+	api   *PoloniexApi
+	store TriggerStore
+
+	mu        sync.Mutex
+	nextID    int64
+	triggers  map[int64]*Trigger
+	placing   map[int64]bool // fired triggers whose order is being placed
+	callbacks []func(TriggerFired)
+	wg        sync.WaitGroup
+}
+
+// NewTriggerEngine loads the pending triggers from store, which may be nil to
+// keep triggers in memory only.
+func NewTriggerEngine(api *PoloniexApi, store TriggerStore) (*TriggerEngine, error) {
+	e := &TriggerEngine{
+		api:      api,
+		store:    store,
+		triggers: make(map[int64]*Trigger),
+		placing:  make(map[int64]bool),
+	}
+
+	if store == nil {
+		return e, nil
+	}
+
+	triggers, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range triggers {
+		t := triggers[i]
+		t.restored = !t.FiredAt.IsZero()
+		e.triggers[t.ID] = &t
+		if t.ID > e.nextID {
+			e.nextID = t.ID
+		}
+	}
+
+	return e, nil
+}
+
+// OnFire registers a callback called each time a trigger fires, from the
+// goroutine that placed its order.
+func (e *TriggerEngine) OnFire(fn func(TriggerFired)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.callbacks = append(e.callbacks, fn)
+}
+
+// Add validates and stores a trigger, and returns its id.
+func (e *TriggerEngine) Add(t Trigger) (int64, error) {
+	ids, err := e.add([]Trigger{t})
+	if err != nil {
+		return 0, err
+	}
+
+	return ids[0], nil
+}
+
+/*
+AddOCO adds two or more triggers as a one-cancels-other group, typically a
+stop-loss and a take-profit on the same position.
+*/
+func (e *TriggerEngine) AddOCO(triggers ...Trigger) ([]int64, error) {
+	if len(triggers) < 2 {
+		return nil, fmt.Errorf("%w: an OCO group needs at least two triggers", ErrInvalidTrigger)
+	}
+
+	group := fmt.Sprintf("oco-%d", NewClientOrderID())
+	for i := range triggers {
+		triggers[i].Group = group
+	}
+
+	return e.add(triggers)
+}
+
+func (e *TriggerEngine) add(triggers []Trigger) ([]int64, error) {
+	for i := range triggers {
+		triggers[i].FiredAt = time.Time{}
+		triggers[i].FiredRate = 0
+		triggers[i].Attempts = 0
+		triggers[i].RetryAt = time.Time{}
+		triggers[i].Error = ""
+		triggers[i].Failed = false
+		if err := triggers[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ids := make([]int64, len(triggers))
+	for i := range triggers {
+		t := triggers[i]
+
+		e.nextID++
+		t.ID = e.nextID
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = time.Now()
+		}
+
+		// Set once, so that an order is never placed twice for a trigger.
+		options := OrderOptions{}
+		if t.Options != nil {
+			options = *t.Options
+		}
+		if options.ClientOrderID == 0 {
+			options.ClientOrderID = NewClientOrderID()
+		}
+		t.Options = &options
+
+		e.triggers[t.ID] = &t
+		ids[i] = t.ID
+	}
+
+	if err := e.save(); err != nil {
+		for _, id := range ids {
+			delete(e.triggers, id)
+		}
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Cancel removes a trigger. Triggers that already fired cannot be cancelled,
+// unless they failed.
+func (e *TriggerEngine) Cancel(id int64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, ok := e.triggers[id]
+	if !ok {
+		return ErrUnknownTrigger
+	}
+
+	if !t.FiredAt.IsZero() && !t.Failed {
+		return fmt.Errorf("Trigger %d already fired", id)
+	}
+
+	delete(e.triggers, id)
+
+	return e.save()
+}
+
+// Triggers returns a copy of the pending triggers, sorted by id.
+func (e *TriggerEngine) Triggers() []Trigger {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.list()
+}
+
+/*
+Check evaluates the triggers against tickers, as returned by ApiPublicTicker,
+and starts placing the orders of those that fire, without waiting for them
+(see Wait). Frozen markets are skipped. Triggers that fired earlier but whose
+order is not confirmed are retried once their RetryAt is past.
+*/
+func (e *TriggerEngine) Check(tickers map[string]Ticker) error {
+	e.mu.Lock()
+
+	var fired []Trigger
+	changed := false
+	now := time.Now()
+
+	for _, t := range e.sorted() {
+		// Removed because another trigger of its group fired.
+		if _, ok := e.triggers[t.ID]; !ok {
+			continue
+		}
+
+		if t.Failed || e.placing[t.ID] {
+			continue
+		}
+
+		if !t.FiredAt.IsZero() {
+			if !now.Before(t.RetryAt) {
+				fired = append(fired, *t)
+			}
+			continue
+		}
+
+		ticker, ok := tickers[t.Pair]
+		if !ok || ticker.IsFrozen != 0 {
+			continue
+		}
+
+		price := t.price(ticker)
+		if price <= 0 {
+			continue
+		}
+
+		fire, moved := t.update(price)
+		changed = changed || moved
+
+		if !fire {
+			continue
+		}
+
+		t.FiredAt = now
+		t.FiredRate = t.orderRate(price)
+		changed = true
+		fired = append(fired, *t)
+
+		if t.Group != "" {
+			for id, other := range e.triggers {
+				if other.Group == t.Group && id != t.ID {
+					delete(e.triggers, id)
+				}
+			}
+		}
+	}
+
+	var err error
+	if changed {
+		err = e.save()
+	}
+
+	// Do not send orders that could not be recorded as fired.
+	if err == nil {
+		for _, t := range fired {
+			e.placing[t.ID] = true
+		}
+		e.wg.Add(len(fired))
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	for _, t := range fired {
+		go e.place(t)
+	}
+
+	return nil
+}
+
+// Wait waits for the orders being placed.
+func (e *TriggerEngine) Wait() {
+	e.wg.Wait()
+}
+
+/*
+place sends the order of a fired trigger. When it may have been sent already,
+before a restart or by a failed attempt, it is looked up first.
+*/
+func (e *TriggerEngine) place(t Trigger) {
+	defer e.wg.Done()
+
+	event := TriggerFired{Trigger: t, Rate: t.FiredRate}
+
+	if t.restored || t.Attempts > 0 {
+		event.Order, event.Err = e.api.FindOrderByClientID(t.Pair, t.Options.ClientOrderID, t.FiredAt.Add(-time.Minute))
+	}
+	if event.Err == nil && event.Order == nil {
+		event.Order, event.Err = e.api.PlaceOrderIdempotent(t.Side, t.Pair, t.FiredRate, t.Amount, t.Options, 3)
+	}
+
+	e.mu.Lock()
+	delete(e.placing, t.ID)
+	if event.Err == nil {
+		delete(e.triggers, t.ID)
+		event.Err = e.save()
+	} else if failed, ok := e.triggers[t.ID]; ok {
+		failed.fail(event.Err)
+		event.Trigger = *failed
+		if err := e.save(); err != nil {
+			event.Err = fmt.Errorf("%s, and could not save the trigger (%s)", event.Err.Error(), err.Error())
+		}
+	}
+	e.mu.Unlock()
+
+	e.mu.Lock()
+	callbacks := append([]func(TriggerFired){}, e.callbacks...)
+	e.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(event)
+	}
+}
+
+// fail records a failed attempt to place the order of a fired trigger.
+// Orders refused by the validator are not retried, they would be refused
+// again.
+func (t *Trigger) fail(err error) {
+	t.Attempts++
+	t.Error = err.Error()
+
+	var validationError *OrderValidationError
+	final := errors.As(err, &validationError) && !errors.Is(err, ErrInsufficientBalance) && !errors.Is(err, ErrMarketFrozen)
+
+	if final || errors.Is(err, ErrInvalidOrderOptions) || t.Attempts >= TriggerMaxAttempts {
+		t.Failed = true
+		return
+	}
+
+	t.RetryAt = time.Now().Add(TriggerRetryDelay << (t.Attempts - 1))
+}
+
+/*
+Run checks the triggers on every ticker from PollTicker until ctx is done,
+then waits for the orders being placed. Errors, from the ticker or from saving
+triggers, are passed to onError, which may be nil.
+*/
+func (e *TriggerEngine) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	for event := range e.api.PollTicker(ctx, interval) {
+		err := event.Err
+		if err == nil {
+			err = e.Check(event.Tickers)
+		}
+
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	e.Wait()
+}
+
+func (e *TriggerEngine) sorted() []*Trigger {
+	out := make([]*Trigger, 0, len(e.triggers))
+	for _, t := range e.triggers {
+		out = append(out, t)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+func (e *TriggerEngine) list() []Trigger {
+	out := make([]Trigger, 0, len(e.triggers))
+	for _, t := range e.sorted() {
+		out = append(out, *t)
+	}
+
+	return out
+}
+
+// save must be called with the lock held.
+func (e *TriggerEngine) save() error {
+	if e.store == nil {
+		return nil
+	}
+
+	return e.store.Save(e.list())
+}
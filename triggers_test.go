@@ -0,0 +1,318 @@
+package poloniexapi
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// memoryTriggerStore keeps the last saved triggers.
+type memoryTriggerStore struct {
+	triggers []Trigger
+	saves    int
+}
+
+func (s *memoryTriggerStore) Load() ([]Trigger, error) {
+	return s.triggers, nil
+}
+
+func (s *memoryTriggerStore) Save(triggers []Trigger) error {
+	s.triggers = triggers
+	s.saves++
+	return nil
+}
+
+func TestTriggerUpdate(t *testing.T) {
+	cases := []struct {
+		trigger Trigger
+		prices  []float64
+		fires   int // index of the price that fires, -1 for none
+	}{
+		{Trigger{Kind: StopLoss, Side: SideSell, StopPrice: 10}, []float64{11, 10.5, 10, 9}, 2},
+		{Trigger{Kind: StopLoss, Side: SideBuy, StopPrice: 10}, []float64{9, 9.5, 10.1}, 2},
+		{Trigger{Kind: TakeProfit, Side: SideSell, StopPrice: 10}, []float64{9, 9.9, 10.2}, 2},
+		{Trigger{Kind: TakeProfit, Side: SideBuy, StopPrice: 10}, []float64{11, 10.5}, -1},
+		{Trigger{Kind: TrailingStop, Side: SideSell, TrailDelta: 1}, []float64{10, 12, 11.5, 11}, 3},
+		{Trigger{Kind: TrailingStop, Side: SideSell, TrailPercent: 10}, []float64{10, 20, 18.5, 17.9}, 3},
+		{Trigger{Kind: TrailingStop, Side: SideBuy, TrailDelta: 1}, []float64{10, 8, 8.5, 9}, 3},
+	}
+
+	for i, c := range cases {
+		fired := -1
+		for j, price := range c.prices {
+			if fire, _ := c.trigger.update(price); fire {
+				fired = j
+				break
+			}
+		}
+
+		if fired != c.fires {
+			t.Errorf("case %d: %s %s fired at %d, want %d", i, c.trigger.Side, c.trigger.Kind, fired, c.fires)
+		}
+	}
+}
+
+func TestTriggerOrderRate(t *testing.T) {
+	cases := []struct {
+		side     OrderSide
+		rate     float64
+		slippage float64
+		price    float64
+		want     float64
+	}{
+		{SideSell, 0, 0.01, 0.0123456789, 0.01222222},
+		{SideBuy, 0, 0.01, 0.0123456789, 0.01246914},
+		{SideBuy, 0, 0, 0.01234567, 0.01234567},
+		{SideSell, 0.012, 0.01, 0.0123456789, 0.012},
+	}
+
+	for _, c := range cases {
+		trigger := Trigger{Side: c.side, Rate: c.rate, Slippage: c.slippage}
+		if got := trigger.orderRate(c.price); got != c.want {
+			t.Errorf("%s at %v with %v slippage: got %v, want %v", c.side, c.price, c.slippage, got, c.want)
+		}
+	}
+}
+
+func TestTriggerEngine(t *testing.T) {
+	sells := 0
+	answer := `{"orderNumber":"10","resultingTrades":{}}`
+
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_SELL:
+			sells++
+			return answer
+		case CMD_PRIVATE_OPEN_ORDERS, CMD_PRIVATE_TRADE_HISTORY:
+			return `[]`
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	store := &memoryTriggerStore{}
+	e, err := NewTriggerEngine(api, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []TriggerFired
+	e.OnFire(func(f TriggerFired) { events = append(events, f) })
+
+	if _, err = e.AddOCO(
+		Trigger{Kind: StopLoss, Pair: "BTC_XMR", Side: SideSell, Amount: 1, StopPrice: 0.01},
+		Trigger{Kind: TakeProfit, Pair: "BTC_XMR", Side: SideSell, Amount: 1, StopPrice: 0.02},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.triggers) != 2 {
+		t.Fatalf("stored %+v, want 2 triggers", store.triggers)
+	}
+
+	// Frozen markets are skipped.
+	if err = e.Check(map[string]Ticker{"BTC_XMR": {HighestBid: 0.009, IsFrozen: 1}}); err != nil || sells != 0 {
+		t.Fatalf("frozen market: sold %d times, %v", sells, err)
+	}
+
+	if err = e.Check(map[string]Ticker{"BTC_XMR": {HighestBid: 0.009}}); err != nil {
+		t.Fatal(err)
+	}
+	e.Wait()
+
+	if sells != 1 || len(events) != 1 || events[0].Err != nil || events[0].Trigger.Kind != StopLoss || len(store.triggers) != 0 {
+		t.Errorf("got %d sells, events %+v and stored %+v, want the stop-loss sold and the group removed", sells, events, store.triggers)
+	}
+}
+
+func TestTriggerEngineRetry(t *testing.T) {
+	attempts, delay := TriggerMaxAttempts, TriggerRetryDelay
+	TriggerMaxAttempts, TriggerRetryDelay = 3, 0
+	defer func() { TriggerMaxAttempts, TriggerRetryDelay = attempts, delay }()
+
+	sells := 0
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_SELL:
+			sells++
+			return `{"error":"Not enough XMR."}`
+		case CMD_PRIVATE_OPEN_ORDERS, CMD_PRIVATE_TRADE_HISTORY:
+			return `[]`
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	store := &memoryTriggerStore{}
+	e, err := NewTriggerEngine(api, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := e.Add(Trigger{Kind: StopLoss, Pair: "BTC_XMR", Side: SideSell, Amount: 1, StopPrice: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tickers := map[string]Ticker{"BTC_XMR": {HighestBid: 0.009}}
+	for i := 0; i < 5; i++ {
+		if err = e.Check(tickers); err != nil {
+			t.Fatal(err)
+		}
+		e.Wait()
+	}
+
+	if sells != 3 || len(store.triggers) != 1 || !store.triggers[0].Failed || store.triggers[0].Attempts != 3 || store.triggers[0].Error == "" {
+		t.Errorf("got %d sells and stored %+v, want 3 attempts then failed", sells, store.triggers)
+	}
+
+	if err = e.Cancel(id); err != nil || len(store.triggers) != 0 {
+		t.Errorf("cancel of a failed trigger: %v, stored %+v", err, store.triggers)
+	}
+
+	// Waits between attempts.
+	TriggerRetryDelay = time.Hour
+	sells = 0
+
+	if _, err = e.Add(Trigger{Kind: StopLoss, Pair: "BTC_XMR", Side: SideSell, Amount: 1, StopPrice: 0.01}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err = e.Check(tickers); err != nil {
+			t.Fatal(err)
+		}
+		e.Wait()
+	}
+
+	if sells != 1 || store.triggers[0].Failed || store.triggers[0].RetryAt.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("got %d sells and stored %+v, want a single attempt and a retry in an hour", sells, store.triggers)
+	}
+}
+
+func TestTriggerEngineRestart(t *testing.T) {
+	sells := 0
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_SELL:
+			sells++
+			return `{"orderNumber":"10","resultingTrades":{}}`
+		case CMD_PRIVATE_OPEN_ORDERS:
+			return `[{"orderNumber":"10","type":"sell","rate":"0.0099","startingAmount":"1","amount":"1","total":"0.0099","date":"2018-03-01 12:00:00","margin":0,"clientOrderId":"42"}]`
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	// Fired and sent right before a crash.
+	store := &memoryTriggerStore{triggers: []Trigger{{
+		ID: 3, Kind: StopLoss, Pair: "BTC_XMR", Side: SideSell, Amount: 1, StopPrice: 0.01,
+		Options: &OrderOptions{ClientOrderID: 42}, FiredAt: time.Now(), FiredRate: 0.0099,
+	}}}
+
+	e, err := NewTriggerEngine(api, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = e.Check(nil); err != nil {
+		t.Fatal(err)
+	}
+	e.Wait()
+
+	if sells != 0 || len(store.triggers) != 0 {
+		t.Errorf("got %d sells and stored %+v, want the order found and the trigger removed", sells, store.triggers)
+	}
+
+	if id, _ := e.Add(Trigger{Kind: StopLoss, Pair: "BTC_XMR", Side: SideSell, Amount: 1, StopPrice: 0.01}); id != 4 {
+		t.Errorf("got id %d after a restart, want 4", id)
+	}
+}
+
+func TestTriggerEngineLookup(t *testing.T) {
+	lookups := 0
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_SELL:
+			return `{"orderNumber":"10","resultingTrades":{}}`
+		case CMD_PRIVATE_OPEN_ORDERS, CMD_PRIVATE_TRADE_HISTORY:
+			lookups++
+			return `[]`
+		}
+		return `{"error":"unexpected command"}`
+	})
+
+	e, err := NewTriggerEngine(api, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = e.Add(Trigger{Kind: StopLoss, Pair: "BTC_XMR", Side: SideSell, Amount: 1, StopPrice: 0.01}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = e.Check(map[string]Ticker{"BTC_XMR": {HighestBid: 0.009}}); err != nil {
+		t.Fatal(err)
+	}
+	e.Wait()
+
+	if lookups != 0 || len(e.Triggers()) != 0 {
+		t.Errorf("got %d lookups and triggers %+v, want the order placed without looking it up", lookups, e.Triggers())
+	}
+}
+
+func TestTriggerEngineConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	api := testApi(func(command string, params url.Values) string {
+		if command != CMD_PRIVATE_SELL {
+			return `{"error":"unexpected command"}`
+		}
+
+		// The exchange hangs on BTC_XMR, then refuses the order.
+		if params.Get("currencyPair") == "BTC_XMR" {
+			<-release
+			return `{"error":"Not enough XMR."}`
+		}
+		return `{"orderNumber":"10","resultingTrades":{}}`
+	})
+
+	e, err := NewTriggerEngine(api, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	placed := make(chan TriggerFired, 2)
+	e.OnFire(func(f TriggerFired) { placed <- f })
+
+	for _, pair := range []string{"BTC_XMR", "BTC_ETH"} {
+		if _, err = e.Add(Trigger{Kind: StopLoss, Pair: pair, Side: SideSell, Amount: 1, StopPrice: 0.01}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tickers := map[string]Ticker{"BTC_XMR": {HighestBid: 0.009}, "BTC_ETH": {HighestBid: 0.009}}
+	if err = e.Check(tickers); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case f := <-placed:
+		if f.Trigger.Pair != "BTC_ETH" || f.Err != nil {
+			t.Errorf("got %+v, want the BTC_ETH order placed", f)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("BTC_ETH order held back by BTC_XMR")
+	}
+
+	// Not sent twice while in flight.
+	if err = e.Check(tickers); err != nil {
+		t.Fatal(err)
+	}
+
+	close(release)
+	e.Wait()
+
+	if f := <-placed; f.Trigger.Pair != "BTC_XMR" || f.Err == nil {
+		t.Errorf("got %+v, want the BTC_XMR order refused", f)
+	}
+	if len(placed) != 0 {
+		t.Errorf("%d more orders placed", len(placed))
+	}
+}
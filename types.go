@@ -1,6 +1,7 @@
 package poloniexapi
 
 import (
+	"bytes"
 	"encoding/json"
 )
 
@@ -35,6 +36,7 @@ type Trade struct {
 	Fee           float64 `json:"fee,string"`
 	OrderNumber   int64   `json:"orderNumber,string"`
 	Category      string  `json:"category"`
+	ClientOrderId int64   `json:"clientOrderId"`
 }
 
 type ChartEntry struct {
@@ -56,6 +58,9 @@ type Currency struct {
 	Name     string
 	TxFee    float64 `json:",string"`
 	MinConf  int64
+
+	// Most that can be withdrawn over 24 hours, 0 when not given.
+	MaxDailyWithdrawal float64
 }
 
 type LoanOrder struct {
@@ -70,6 +75,33 @@ type LoanOrders struct {
 	Offers  []LoanOrder
 }
 
+type LoanOffer struct {
+	Id        int64   `json:"id"`
+	Rate      float64 `json:"rate,string"`
+	Amount    float64 `json:"amount,string"`
+	Duration  int64   `json:"duration"` // in days
+	AutoRenew int64   `json:"autoRenew"`
+	Date      string  `json:"date"`
+}
+
+type Loan struct {
+	Id        int64   `json:"id"`
+	Currency  string  `json:"currency"`
+	Rate      float64 `json:"rate,string"`
+	Amount    float64 `json:"amount,string"`
+	Duration  int64   `json:"range"` // in days
+	AutoRenew int64   `json:"autoRenew"`
+	Date      string  `json:"date"`
+	Fees      float64 `json:"fees,string"`
+}
+
+// ActiveLoans are the loans provided to others, and those used for margin
+// trading.
+type ActiveLoans struct {
+	Provided []Loan `json:"provided"`
+	Used     []Loan `json:"used"`
+}
+
 type Balance struct {
 	Available float64 `json:"available,string"`
 	OnOrders  float64 `json:"onOrders,string"`
@@ -115,12 +147,14 @@ type OpenOrder struct {
 	Total          float64 `json:"total,string"`
 	Date           string  `json:"date"`
 	Margin         int64   `json:"margin"`
+	ClientOrderId  int64   `json:"clientOrderId"`
 }
 
 type Order struct {
 	Success         int64              `json:"success"` // Use for moveOrder
 	OrderNumber     int64              `json:"orderNumber"`
 	ResultingTrades map[string][]Trade `json:"resultingTrades"`
+	ClientOrderId   int64              `json:"clientOrderId"`
 }
 
 type CancelOrder struct {
@@ -130,6 +164,17 @@ type CancelOrder struct {
 	Message string  `json:"message"`
 }
 
+type WithdrawResponse struct {
+	Response string `json:"response"`
+	Error    string `json:"error"`
+}
+
+type TransferBalanceResponse struct {
+	Success int64  `json:"success"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
 type FeeInfo struct {
 	MakerFee        float64 `json:"makerFee,string"`
 	TakerFee        float64 `json:"takerFee,string"`
@@ -142,7 +187,8 @@ func (t *Trade) UnmarshalJSON(data []byte) error {
 
 	type Alias Trade
 	aux := &struct {
-		TradeID json.Number `json:"tradeID"`
+		TradeID       json.Number `json:"tradeID"`
+		ClientOrderId json.Number `json:"clientOrderId"`
 		*Alias
 	}{
 		Alias: (*Alias)(t),
@@ -157,6 +203,58 @@ func (t *Trade) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	t.ClientOrderId, err = optionalInt64(aux.ClientOrderId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// maxDailyWithdrawal is a number or a string depending on the currency.
+func (c *Currency) UnmarshalJSON(data []byte) error {
+	type Alias Currency
+	aux := &struct {
+		MaxDailyWithdrawal json.Number `json:"maxDailyWithdrawal"`
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.MaxDailyWithdrawal == "" {
+		return nil
+	}
+
+	var err error
+	c.MaxDailyWithdrawal, err = aux.MaxDailyWithdrawal.Float64()
+
+	return err
+}
+
+func (o *OpenOrder) UnmarshalJSON(data []byte) error {
+	var err error
+
+	type Alias OpenOrder
+	aux := &struct {
+		ClientOrderId json.Number `json:"clientOrderId"`
+		*Alias
+	}{
+		Alias: (*Alias)(o),
+	}
+
+	if err = json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	o.ClientOrderId, err = optionalInt64(aux.ClientOrderId)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -165,7 +263,9 @@ func (t *Order) UnmarshalJSON(data []byte) error {
 
 	type Alias Order
 	aux := &struct {
-		OrderNumber json.Number `json:"orderNumber"`
+		OrderNumber     json.Number     `json:"orderNumber"`
+		ClientOrderId   json.Number     `json:"clientOrderId"`
+		ResultingTrades json.RawMessage `json:"resultingTrades"`
 		*Alias
 	}{
 		Alias: (*Alias)(t),
@@ -175,10 +275,122 @@ func (t *Order) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// buy and sell return a list of trades, moveOrder a map by pair. The list
+	// is stored under an empty pair, which PlaceOrder replaces.
+	t.ResultingTrades = make(map[string][]Trade)
+	if raw := bytes.TrimSpace(aux.ResultingTrades); len(raw) != 0 && raw[0] == '[' {
+		trades := make([]Trade, 0)
+		if err = json.Unmarshal(raw, &trades); err != nil {
+			return err
+		}
+		t.ResultingTrades[""] = trades
+	} else if len(raw) != 0 && string(raw) != "null" {
+		if err = json.Unmarshal(raw, &t.ResultingTrades); err != nil {
+			return err
+		}
+	}
+
 	t.OrderNumber, err = aux.OrderNumber.Int64()
 	if err != nil {
 		return err
 	}
 
+	t.ClientOrderId, err = optionalInt64(aux.ClientOrderId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// optionalInt64 converts a number that may be missing, or sent as a string.
+func optionalInt64(n json.Number) (int64, error) {
+	if n == "" {
+		return 0, nil
+	}
+
+	return n.Int64()
+}
+
+type MarginAccountSummary struct {
+	TotalValue         float64 `json:"totalValue,string"`
+	PL                 float64 `json:"pl,string"`
+	LendingFees        float64 `json:"lendingFees,string"`
+	NetValue           float64 `json:"netValue,string"`
+	TotalBorrowedValue float64 `json:"totalBorrowedValue,string"`
+	CurrentMargin      float64 `json:"currentMargin,string"`
+}
+
+type MarginPosition struct {
+	Amount           float64
+	Total            float64
+	BasePrice        float64
+	LiquidationPrice float64 // -1 when there is none
+	PL               float64
+	LendingFees      float64
+	Type             string // long, short or none
+}
+
+func (p *MarginPosition) UnmarshalJSON(data []byte) error {
+	// liquidationPrice is a number, the other values are strings.
+	var aux struct {
+		Amount           json.Number `json:"amount"`
+		Total            json.Number `json:"total"`
+		BasePrice        json.Number `json:"basePrice"`
+		LiquidationPrice json.Number `json:"liquidationPrice"`
+		PL               json.Number `json:"pl"`
+		LendingFees      json.Number `json:"lendingFees"`
+		Type             string      `json:"type"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.Type = aux.Type
+
+	fields := []struct {
+		in  json.Number
+		out *float64
+	}{
+		{aux.Amount, &p.Amount},
+		{aux.Total, &p.Total},
+		{aux.BasePrice, &p.BasePrice},
+		{aux.LiquidationPrice, &p.LiquidationPrice},
+		{aux.PL, &p.PL},
+		{aux.LendingFees, &p.LendingFees},
+	}
+
+	for _, f := range fields {
+		if f.in == "" {
+			continue
+		}
+
+		value, err := f.in.Float64()
+		if err != nil {
+			return err
+		}
+		*f.out = value
+	}
+
 	return nil
 }
+
+type CloseMarginPositionResponse struct {
+	Success         int64              `json:"success"`
+	Message         string             `json:"message"`
+	ResultingTrades map[string][]Trade `json:"resultingTrades"`
+}
+
+type LendingHistoryEntry struct {
+	Id       int64   `json:"id"`
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate,string"`
+	Amount   float64 `json:"amount,string"`
+	Duration float64 `json:"duration,string"` // in days
+	Interest float64 `json:"interest,string"`
+	Fee      float64 `json:"fee,string"` // negative
+	Earned   float64 `json:"earned,string"`
+	Open     string  `json:"open"`
+	Close    string  `json:"close"`
+}
@@ -0,0 +1,335 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidPrecision    = errors.New("rate or amount has more than 8 decimals")
+	ErrInvalidRate         = errors.New("rate and amount must be positive")
+	ErrBelowMinimumTotal   = errors.New("order total is below the market minimum")
+	ErrUnknownMarket       = errors.New("unknown market")
+	ErrMarketFrozen        = errors.New("market is frozen")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
+
+/*
+OrderValidationError is returned when an order is refused before being sent.
+Rule is one of the Err* values above, so callers can use errors.Is.
+*/
+type OrderValidationError struct {
+	Pair    string
+	Rule    error
+	Message string
+}
+
+func (e *OrderValidationError) Error() string {
+	if e.Pair == "" {
+		return fmt.Sprintf("Order refused: %s", e.Message)
+	}
+
+	return fmt.Sprintf("Order on %s refused: %s", e.Pair, e.Message)
+}
+
+func (e *OrderValidationError) Unwrap() error {
+	return e.Rule
+}
+
+/*
+OrderValidator checks orders against the market rules before they are signed
+and sent. It is used by PlaceOrder (and so ApiPrivateBuy and ApiPrivateSell)
+and ApiPrivateMoveOrder when set in PoloniexApi.Validator, which is nil by
+default:
+
+	api.Validator = poloniexapi.NewOrderValidator()
+
+Rates and amounts must be positive with at most 8 decimals, and the total of an
+order (rate times amount, in the base currency) must be at least the minimum
+in MinTotals for that base currency; bases missing from MinTotals are not
+checked.
+
+When CheckMarket is set, the market must exist and not be frozen, from
+ApiPublicTicker cached for TickerTTL. When CheckBalance is set, the balance
+spent by the order must be available, from ApiPrivateBalances cached for
+BalanceTTL; the balances are fetched again after each order sent.
+
+moveOrder does not say which market the order is on: moves get the precision
+checks, then the other checks once the order is found in the open orders. The
+balance is only checked for what the moved order spends beyond the order it
+replaces.
+*/
+type OrderValidator struct {
+	MinTotals    map[string]float64
+	CheckMarket  bool
+	TickerTTL    time.Duration
+	CheckBalance bool
+	BalanceTTL   time.Duration
+
+	mu          sync.Mutex
+	tickers     map[string]Ticker
+	tickersAt   time.Time
+	balances    map[string]float64
+	balancesAt  time.Time
+	balancesGen int // changed by Invalidate, so that a fetch started before is not kept
+}
+
+// Totals are rounded to 8 decimals by the exchange.
+const totalEpsilon = 0.000000005
+
+func NewOrderValidator() *OrderValidator {
+	return &OrderValidator{
+		MinTotals:    map[string]float64{"BTC": 0.0001},
+		CheckMarket:  true,
+		TickerTTL:    time.Minute,
+		CheckBalance: true,
+		BalanceTTL:   30 * time.Second,
+	}
+}
+
+// Invalidate drops the cached balances, e.g. after a trade made elsewhere.
+func (v *OrderValidator) Invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.balances = nil
+	v.balancesGen++
+}
+
+/*
+ValidateOrder runs the checks of api.Validator on an order, without sending
+it. It returns nil when the api has no validator.
+*/
+func (api *PoloniexApi) ValidateOrder(side OrderSide, currencyPair string, rate, amount float64) error {
+	return api.validateOrder(context.Background(), side, currencyPair, rate, amount)
+}
+
+func (api *PoloniexApi) validateOrder(ctx context.Context, side OrderSide, currencyPair string, rate, amount float64) error {
+	v := api.Validator
+	if v == nil {
+		return nil
+	}
+
+	if err := v.checkValues(currencyPair, rate, amount); err != nil {
+		return err
+	}
+
+	base, quote, ok := splitPair(currencyPair)
+	if !ok {
+		return &OrderValidationError{currencyPair, ErrUnknownMarket, "currency pair must be BASE_QUOTE"}
+	}
+
+	if err := v.checkTotal(currencyPair, base, rate, amount); err != nil {
+		return err
+	}
+
+	if v.CheckMarket {
+		if err := v.checkMarket(ctx, api, currencyPair); err != nil {
+			return err
+		}
+	}
+
+	if v.CheckBalance {
+		currency, needed := base, rate*amount
+		if side == SideSell {
+			currency, needed = quote, amount
+		}
+
+		if err := v.checkBalance(ctx, api, currencyPair, currency, needed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+validateMove checks the precision of a move, then looks the order up in the
+open orders to check the moved order, with its remaining amount when amount is
+zero. Orders not found are left to the exchange to refuse.
+*/
+func (api *PoloniexApi) validateMove(ctx context.Context, orderNumber int64, rate, amount float64) error {
+	v := api.Validator
+	if v == nil {
+		return nil
+	}
+
+	if err := v.checkPrecision("", "rate", rate); err != nil {
+		return err
+	}
+
+	if amount != 0 {
+		if err := v.checkPrecision("", "amount", amount); err != nil {
+			return err
+		}
+	}
+
+	if len(v.MinTotals) == 0 && !v.CheckMarket && !v.CheckBalance {
+		return nil
+	}
+
+	open, err := api.ApiPrivateOpenOrdersContext(ctx, "all")
+	if err != nil {
+		return err
+	}
+
+	number := strconv.FormatInt(orderNumber, 10)
+	for pair, orders := range open {
+		for _, o := range orders {
+			if o.OrderNumber == number {
+				return v.checkMove(ctx, api, pair, o, rate, amount)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *OrderValidator) checkMove(ctx context.Context, api *PoloniexApi, pair string, o OpenOrder, rate, amount float64) error {
+	if amount == 0 {
+		amount = o.Amount
+	}
+
+	base, quote, _ := splitPair(pair)
+	if err := v.checkTotal(pair, base, rate, amount); err != nil {
+		return err
+	}
+
+	if v.CheckMarket {
+		if err := v.checkMarket(ctx, api, pair); err != nil {
+			return err
+		}
+	}
+
+	if v.CheckBalance {
+		// The order moved gives back what it holds.
+		currency, needed := base, rate*amount-o.Rate*o.Amount
+		if o.Type == string(SideSell) {
+			currency, needed = quote, amount-o.Amount
+		}
+
+		if needed > totalEpsilon {
+			return v.checkBalance(ctx, api, pair, currency, needed)
+		}
+	}
+
+	return nil
+}
+
+// orderSent drops the cached balances after an order changed them.
+func (api *PoloniexApi) orderSent() {
+	if api.Validator != nil {
+		api.Validator.Invalidate()
+	}
+}
+
+func (v *OrderValidator) checkValues(pair string, rate, amount float64) error {
+	if err := v.checkPrecision(pair, "rate", rate); err != nil {
+		return err
+	}
+
+	return v.checkPrecision(pair, "amount", amount)
+}
+
+func (v *OrderValidator) checkPrecision(pair, name string, value float64) error {
+	if value <= 0 {
+		return &OrderValidationError{pair, ErrInvalidRate, fmt.Sprintf("%s %s is not positive", name, formatDecimal(value))}
+	}
+
+	s := strconv.FormatFloat(value, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > 8 {
+		return &OrderValidationError{pair, ErrInvalidPrecision, fmt.Sprintf("%s %s has more than 8 decimals", name, s)}
+	}
+
+	return nil
+}
+
+func (v *OrderValidator) checkTotal(pair, base string, rate, amount float64) error {
+	min, ok := v.MinTotals[base]
+	if !ok || rate*amount >= min-totalEpsilon {
+		return nil
+	}
+
+	return &OrderValidationError{pair, ErrBelowMinimumTotal,
+		fmt.Sprintf("total %s %s is below %s %s", formatDecimal(rate*amount), base, formatDecimal(min), base)}
+}
+
+// The caches are read and filled under the lock, but fetched without it.
+func (v *OrderValidator) checkMarket(ctx context.Context, api *PoloniexApi, pair string) error {
+	v.mu.Lock()
+	tickers := v.tickers
+	if time.Since(v.tickersAt) > v.TickerTTL {
+		tickers = nil
+	}
+	v.mu.Unlock()
+
+	if tickers == nil {
+		var err error
+		if tickers, err = api.ApiPublicTickerContext(ctx); err != nil {
+			return err
+		}
+
+		v.mu.Lock()
+		v.tickers, v.tickersAt = tickers, time.Now()
+		v.mu.Unlock()
+	}
+
+	ticker, ok := tickers[pair]
+	if !ok {
+		return &OrderValidationError{pair, ErrUnknownMarket, "no such market"}
+	}
+
+	if ticker.IsFrozen != 0 {
+		return &OrderValidationError{pair, ErrMarketFrozen, "market is frozen"}
+	}
+
+	return nil
+}
+
+func (v *OrderValidator) checkBalance(ctx context.Context, api *PoloniexApi, pair, currency string, needed float64) error {
+	v.mu.Lock()
+	balances, gen := v.balances, v.balancesGen
+	if time.Since(v.balancesAt) > v.BalanceTTL {
+		balances = nil
+	}
+	v.mu.Unlock()
+
+	if balances == nil {
+		var err error
+		if balances, err = api.ApiPrivateBalancesContext(ctx); err != nil {
+			return err
+		}
+
+		v.mu.Lock()
+		if v.balancesGen == gen {
+			v.balances, v.balancesAt = balances, time.Now()
+		}
+		v.mu.Unlock()
+	}
+
+	if available := balances[currency]; needed-available > totalEpsilon {
+		return &OrderValidationError{pair, ErrInsufficientBalance,
+			fmt.Sprintf("needs %s %s, only %s available", formatDecimal(needed), currency, formatDecimal(available))}
+	}
+
+	return nil
+}
+
+// splitPair splits "BTC_XMR" into its base ("BTC") and quote ("XMR").
+func splitPair(pair string) (string, string, bool) {
+	parts := strings.Split(pair, "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,147 @@
+package poloniexapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestValidatorOptIn(t *testing.T) {
+	if api := New("key", "secret"); api.Validator != nil {
+		t.Errorf("New enabled a validator")
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	balances := 0
+
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PUBLIC_TICKER:
+			return `{"BTC_XMR":{"id":114,"last":"0.0125","isFrozen":"0"},"BTC_DOGE":{"id":27,"last":"0.0000001","isFrozen":"0"},"BTC_FRZ":{"id":1,"last":"0.01","isFrozen":"1"}}`
+		case CMD_PRIVATE_BALANCES:
+			balances++
+			return `{"BTC":"0.5","XMR":"2"}`
+		}
+		return `{"error":"unexpected command"}`
+	})
+	api.Validator = NewOrderValidator()
+
+	cases := []struct {
+		side   OrderSide
+		pair   string
+		rate   float64
+		amount float64
+		err    error
+	}{
+		{SideBuy, "BTC_XMR", 0.0125, 1, nil},
+		{SideBuy, "BTC_XMR", 0.012345678, 1, ErrInvalidPrecision},
+		{SideBuy, "BTC_XMR", 0.0125, -1, ErrInvalidRate},
+		{SideBuy, "BTC_XMR", 0.0125, 0.001, ErrBelowMinimumTotal},
+		{SideBuy, "BTC_DOGE", 0.0000001, 1000, nil}, // exactly the minimum
+		{SideBuy, "BTC_FRZ", 0.01, 1, ErrMarketFrozen},
+		{SideBuy, "BTC_NOPE", 0.01, 1, ErrUnknownMarket},
+		{SideBuy, "BTC_XMR", 0.0125, 40, nil},
+		{SideBuy, "BTC_XMR", 0.0125, 41, ErrInsufficientBalance},
+		{SideSell, "BTC_XMR", 0.0125, 2, nil},
+		{SideSell, "BTC_XMR", 0.0125, 2.1, ErrInsufficientBalance},
+	}
+
+	for _, c := range cases {
+		err := api.ValidateOrder(c.side, c.pair, c.rate, c.amount)
+		if !errors.Is(err, c.err) || (err == nil) != (c.err == nil) {
+			t.Errorf("%s %v %s at %v: got %v, want %v", c.side, c.amount, c.pair, c.rate, err, c.err)
+		}
+	}
+
+	if balances != 1 {
+		t.Errorf("balances fetched %d times, want once", balances)
+	}
+}
+
+func TestValidateMove(t *testing.T) {
+	moves := 0
+
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_OPEN_ORDERS:
+			return `{"BTC_XMR":[{"orderNumber":"10","type":"buy","rate":"0.0125","amount":"0.5","total":"0.00625","date":"2018-03-01 12:00:00","margin":0},{"orderNumber":"13","type":"sell","rate":"0.0125","amount":"0.5","total":"0.00625","date":"2018-03-01 12:00:00","margin":0}],` +
+				`"BTC_FRZ":[{"orderNumber":"20","type":"buy","rate":"0.01","amount":"1","total":"0.01","date":"2018-03-01 12:00:00","margin":0}],"BTC_ETH":[]}`
+		case CMD_PUBLIC_TICKER:
+			return `{"BTC_XMR":{"id":114,"last":"0.0125","isFrozen":"0"},"BTC_FRZ":{"id":1,"last":"0.01","isFrozen":"1"}}`
+		case CMD_PRIVATE_BALANCES:
+			return `{"BTC":"0.015","XMR":"1"}`
+		case CMD_PRIVATE_MOVE_ORDER:
+			moves++
+			return `{"success":1,"orderNumber":"11","resultingTrades":{}}`
+		}
+		return `{"error":"unexpected command"}`
+	})
+	api.Validator = NewOrderValidator()
+
+	cases := []struct {
+		orderNumber int64
+		rate        float64
+		amount      float64
+		err         error
+	}{
+		{10, 0.0125, 0, nil},
+		{10, 0.0001, 0, ErrBelowMinimumTotal}, // remaining 0.5 at 0.0001
+		{10, 0.0001, 0.9, ErrBelowMinimumTotal},
+		{10, 0.0001, 2, nil},
+		{10, 0.000123456789, 2, ErrInvalidPrecision},
+		{12, 0.0001, 0, nil}, // unknown, left to the exchange
+		{20, 0.011, 0, ErrMarketFrozen},
+		{10, 0.02, 1, nil},                      // 0.02 BTC, 0.00625 given back
+		{10, 0.03, 1, ErrInsufficientBalance},   // 0.03 BTC, 0.00625 given back
+		{10, 0.1, 0.1, nil},                     // spends less than before
+		{13, 0.02, 1.5, nil},                    // 1 XMR more
+		{13, 0.02, 1.6, ErrInsufficientBalance}, // 1.1 XMR more
+		{13, 0.02, 0, nil},
+	}
+
+	sent := 0
+	for _, c := range cases {
+		_, err := api.ApiPrivateMoveOrder(c.orderNumber, c.rate, c.amount, nil)
+		if !errors.Is(err, c.err) || (err == nil) != (c.err == nil) {
+			t.Errorf("move %d to %v for %v: got %v, want %v", c.orderNumber, c.rate, c.amount, err, c.err)
+		}
+		if c.err == nil {
+			sent++
+		}
+	}
+
+	if moves != sent {
+		t.Errorf("sent %d moves, want %d", moves, sent)
+	}
+}
+
+func TestValidatorInvalidateDuringFetch(t *testing.T) {
+	v := NewOrderValidator()
+	v.CheckMarket = false
+
+	balances := 0
+	api := testApi(func(command string, params url.Values) string {
+		if command != CMD_PRIVATE_BALANCES {
+			return `{"error":"unexpected command"}`
+		}
+
+		// An order sent elsewhere while the balances are fetched: must not
+		// wait for the fetch, and makes it stale.
+		if balances++; balances == 1 {
+			v.Invalidate()
+		}
+		return `{"BTC":"0.5"}`
+	})
+	api.Validator = v
+
+	for i := 0; i < 3; i++ {
+		if err := api.ValidateOrder(SideBuy, "BTC_XMR", 0.0125, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if balances != 2 {
+		t.Errorf("balances fetched %d times, want 2", balances)
+	}
+}
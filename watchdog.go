@@ -0,0 +1,247 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Requests per second reserved by a running Watchdog out of the client rate
+// limit.
+var WatchdogRequestsPerSecond = 2.0
+
+// WatchdogTrip is passed to Watchdog.OnTrip each time the watchdog fires.
+type WatchdogTrip struct {
+	Time          time.Time
+	LastHeartbeat time.Time
+	Cancels       CancelReport
+	Positions     map[string]*CloseMarginPositionResponse
+	Errors        []error
+}
+
+/*
+Watchdog is a dead man's switch: unless Heartbeat is called at least every
+Timeout, it cancels all open orders and, when CloseMarginPositions is set,
+closes every margin position.
+
+It runs in its own goroutine and sends its requests through a copy of the
+client with its own share of the rate limit, reserved while it runs, so it is
+not slowed down by the rest of the application.
+
+Within the application, it protects against a stalled process (deadlock, stuck
+loop, hung connection), not against one that exits. To cover crashes, run it
+in another process with HeartbeatFile set, e.g. with "poloniex watchdog": the
+modification time of the file then counts as a heartbeat, and the application
+touches it with TouchHeartbeat, or Heartbeat of a watchdog with the same file.
+
+After firing, the watchdog fires again every Timeout until heartbeats resume.
+*/
+type Watchdog struct {
+	Timeout              time.Duration
+	CloseMarginPositions bool
+	OnTrip               func(WatchdogTrip)
+	HeartbeatFile        string
+
+	api *PoloniexApi
+	now func() time.Time
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// Shortest timeout accepted by NewWatchdog: the heartbeats are checked four
+// times per timeout.
+var MinWatchdogTimeout = time.Second
+
+/*
+NewWatchdog creates a watchdog for api. While it runs, WatchdogRequestsPerSecond
+are taken out of the rate limit of api, when it has one.
+*/
+func NewWatchdog(api *PoloniexApi, timeout time.Duration) (*Watchdog, error) {
+	if timeout < MinWatchdogTimeout {
+		return nil, fmt.Errorf("Watchdog timeout %s is below %s", timeout, MinWatchdogTimeout)
+	}
+
+	return &Watchdog{
+		Timeout: timeout,
+		api:     api,
+		now:     time.Now,
+		last:    time.Now(),
+	}, nil
+}
+
+/*
+Heartbeat tells the watchdog the application is alive. When HeartbeatFile is
+set, the file is touched as well, for a watchdog in another process.
+*/
+func (w *Watchdog) Heartbeat() error {
+	w.mu.Lock()
+	w.last = w.now()
+	w.mu.Unlock()
+
+	if w.HeartbeatFile == "" {
+		return nil
+	}
+
+	return TouchHeartbeat(w.HeartbeatFile)
+}
+
+// TouchHeartbeat sets the modification time of a heartbeat file to now,
+// creating it if needed.
+func TouchHeartbeat(path string) error {
+	now := time.Now()
+
+	err := os.Chtimes(path, now, now)
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.WriteFile(path, nil, 0644)
+	}
+
+	return err
+}
+
+/*
+Start reserves the share of the rate limit of the watchdog, and runs it in a
+new goroutine until ctx is done. The reservation is then released.
+*/
+func (w *Watchdog) Start(ctx context.Context) error {
+	api, release, err := w.reserve()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer release()
+		w.run(ctx, api)
+	}()
+
+	return nil
+}
+
+// Run is Start without the goroutine: it watches the heartbeats until ctx is
+// done.
+func (w *Watchdog) Run(ctx context.Context) error {
+	api, release, err := w.reserve()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	w.run(ctx, api)
+
+	return nil
+}
+
+// reserve returns the client of the watchdog, with its own limiter, and the
+// function releasing it.
+func (w *Watchdog) reserve() (*PoloniexApi, func(), error) {
+	client := *w.api
+	if client.Limiter == nil {
+		return &client, func() {}, nil
+	}
+
+	limiter, err := client.Limiter.Reserve(WatchdogRequestsPerSecond)
+	if err != nil {
+		return nil, nil, err
+	}
+	client.Limiter = limiter
+
+	return &client, limiter.Release, nil
+}
+
+func (w *Watchdog) run(ctx context.Context, api *PoloniexApi) {
+	ticker := time.NewTicker(w.Timeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		last, expired := w.expired()
+		if !expired {
+			continue
+		}
+
+		trip := w.trip(api)
+		trip.LastHeartbeat = last
+
+		if w.OnTrip != nil {
+			w.OnTrip(trip)
+		}
+	}
+}
+
+/*
+expired returns the last heartbeat, from Heartbeat or the modification time of
+HeartbeatFile, and tells if it is older than Timeout. When it is, the watchdog
+counts itself as a heartbeat, to fire again after another Timeout.
+*/
+func (w *Watchdog) expired() (time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	last := w.last
+	if w.HeartbeatFile != "" {
+		if info, err := os.Stat(w.HeartbeatFile); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+
+	now := w.now()
+	if now.Sub(last) <= w.Timeout {
+		return last, false
+	}
+
+	w.last = now
+
+	return last, true
+}
+
+// Trip cancels all orders, and closes margin positions if configured, now.
+func (w *Watchdog) Trip() WatchdogTrip {
+	return w.trip(w.api)
+}
+
+func (w *Watchdog) trip(api *PoloniexApi) WatchdogTrip {
+	trip := WatchdogTrip{Time: time.Now()}
+
+	report, err := api.CancelAll("all")
+	trip.Cancels = report
+	if err != nil {
+		trip.Errors = append(trip.Errors, err)
+	}
+	for _, failed := range report.Failed() {
+		trip.Errors = append(trip.Errors, failed.Err)
+	}
+
+	if !w.CloseMarginPositions {
+		return trip
+	}
+
+	positions, err := api.ApiPrivateMarginPosition("all")
+	if err != nil {
+		trip.Errors = append(trip.Errors, err)
+		return trip
+	}
+
+	trip.Positions = make(map[string]*CloseMarginPositionResponse)
+	for pair, position := range positions {
+		if position.Type == "none" || position.Amount == 0 {
+			continue
+		}
+
+		out, err := api.ApiPrivateCloseMarginPosition(pair)
+		if err != nil {
+			trip.Errors = append(trip.Errors, err)
+			continue
+		}
+		trip.Positions[pair] = out
+	}
+
+	return trip
+}
@@ -0,0 +1,195 @@
+package poloniexapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewWatchdogTimeout(t *testing.T) {
+	api := testApi(nil)
+
+	for _, timeout := range []time.Duration{0, time.Nanosecond, -time.Second, MinWatchdogTimeout - 1} {
+		if _, err := NewWatchdog(api, timeout); err == nil {
+			t.Errorf("timeout %s accepted", timeout)
+		}
+	}
+
+	if _, err := NewWatchdog(api, MinWatchdogTimeout); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRateLimiterRelease(t *testing.T) {
+	l := NewRateLimiter(6)
+
+	rate := func() float64 {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.perSecond
+	}
+
+	reserved, err := l.Reserve(2)
+	if err != nil || rate() != 4 {
+		t.Fatalf("reserved 2 out of 6: left %g, %v", rate(), err)
+	}
+
+	if _, err = l.Reserve(4); err == nil {
+		t.Error("reserved the whole rate")
+	}
+
+	reserved.Release()
+	reserved.Release()
+	l.Release()
+
+	if rate() != 6 {
+		t.Errorf("got %g after release, want 6", rate())
+	}
+}
+
+func TestRateLimiterWaitCancel(t *testing.T) {
+	l := NewRateLimiter(1)
+
+	if wait, err := l.Wait(context.Background()); wait != 0 || err != nil {
+		t.Fatalf("first request waited %s, %v", wait, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if waited := time.Since(start); waited > 500*time.Millisecond {
+		t.Errorf("waited %s after the context was done", waited)
+	}
+
+	// The turn given up is taken by the next request.
+	l.mu.Lock()
+	next := time.Until(l.next)
+	l.mu.Unlock()
+	if next > time.Second {
+		t.Errorf("next request in %s, want at most a second", next)
+	}
+
+	// Requests are cancelled while waiting for the limiter.
+	api := testApi(func(command string, params url.Values) string { return `{}` })
+	api.Limiter = l
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := api.ApiPrivateBalancesContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWatchdogHeartbeatFile(t *testing.T) {
+	start := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	w, err := NewWatchdog(testApi(nil), 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.HeartbeatFile = filepath.Join(t.TempDir(), "heartbeat")
+	w.now = func() time.Time { return now }
+	w.last = start
+
+	touch := func(at time.Time) {
+		if err := os.Chtimes(w.HeartbeatFile, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		after   time.Duration
+		touched time.Duration // file modification time, 0 to leave it
+		expired bool
+		last    time.Duration
+	}{
+		{20 * time.Second, 0, false, 0},
+		{40 * time.Second, 0, true, 0}, // no file yet
+		{50 * time.Second, 0, false, 40 * time.Second},
+		{100 * time.Second, 80 * time.Second, false, 80 * time.Second},
+		{115 * time.Second, 0, true, 80 * time.Second},
+		{140 * time.Second, 0, false, 115 * time.Second},
+	}
+
+	for i, c := range cases {
+		if c.touched != 0 {
+			if err = TouchHeartbeat(w.HeartbeatFile); err != nil {
+				t.Fatal(err)
+			}
+			touch(start.Add(c.touched))
+		}
+
+		now = start.Add(c.after)
+		last, expired := w.expired()
+		if expired != c.expired || !last.Equal(start.Add(c.last)) {
+			t.Errorf("case %d: got %v with last heartbeat %s, want %v with %s", i, expired, last, c.expired, start.Add(c.last))
+		}
+	}
+
+	// Heartbeat touches the file.
+	if err = w.Heartbeat(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(w.HeartbeatFile); err != nil || time.Since(info.ModTime()) > time.Minute {
+		t.Errorf("heartbeat file not touched: %v %v", info, err)
+	}
+}
+
+func TestWatchdogReservation(t *testing.T) {
+	cancels := 0
+
+	api := testApi(func(command string, params url.Values) string {
+		switch command {
+		case CMD_PRIVATE_OPEN_ORDERS:
+			return `{"BTC_XMR":[{"orderNumber":"1","type":"buy","rate":"0.0125","startingAmount":"1","amount":"1","total":"0.0125","date":"2018-03-01 12:00:00","margin":0}]}`
+		case CMD_PRIVATE_CANCEL_ORDER:
+			cancels++
+			return `{"success":1,"amount":"1","message":"Order #1 canceled."}`
+		}
+		return `{"error":"unexpected command"}`
+	})
+	api.Limiter = NewRateLimiter(6)
+
+	rate := func() float64 {
+		api.Limiter.mu.Lock()
+		defer api.Limiter.mu.Unlock()
+		return api.Limiter.perSecond
+	}
+
+	w, err := NewWatchdog(api, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trip := w.Trip(); cancels != 1 || len(trip.Cancels) != 1 || len(trip.Errors) != 0 {
+		t.Errorf("got trip %+v after %d cancels", trip, cancels)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err = w.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if rate() != 6-WatchdogRequestsPerSecond {
+		t.Errorf("got %g left while running, want %g", rate(), 6-WatchdogRequestsPerSecond)
+	}
+
+	cancel()
+
+	for deadline := time.Now().Add(time.Second); rate() != 6 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	if rate() != 6 {
+		t.Errorf("got %g after stopping, want 6", rate())
+	}
+}
@@ -0,0 +1,287 @@
+package poloniexapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrAddressNotAllowed    = errors.New("withdrawal address is not in the allowlist")
+	ErrCurrencyUnavailable  = errors.New("currency is unknown, disabled, frozen or delisted")
+	ErrAmountTooSmall       = errors.New("withdrawal amount is too small")
+	ErrDailyLimitExceeded   = errors.New("withdrawal would exceed the daily limit")
+	ErrConfirmationRequired = errors.New("withdrawal must be prepared then confirmed")
+	ErrUnknownConfirmation  = errors.New("unknown or expired withdrawal confirmation token")
+)
+
+type WithdrawRequest struct {
+	Currency  string
+	Address   string
+	Amount    float64
+	PaymentId string
+}
+
+type withdrawalRecord struct {
+	currency string
+	amount   float64
+	time     time.Time
+}
+
+type pendingWithdrawal struct {
+	request WithdrawRequest
+	expires time.Time
+}
+
+/*
+WithdrawGuard sits in front of ApiPrivateWithdraw and refuses withdrawals that
+are not explicitly allowed:
+
+  - the address must be in the allowlist of the currency,
+  - the currency must be known and enabled according to ApiCurrencies, and the
+    amount above its TxFee and any configured minimum,
+  - the amount withdrawn in the last 24 hours, from the exchange history plus
+    the withdrawals made through this guard, must stay within the daily limit
+    of the currency, the lowest of the one set here and the maxDailyWithdrawal
+    of ApiCurrencies.
+
+When RequireConfirmation is set, withdrawals go through Prepare, which checks
+the request and returns a one-time token, then Confirm, which checks it again
+and sends it. Withdrawals are sent one at a time, each checked right before
+being sent, so that concurrent ones cannot both pass the daily limit.
+*/
+type WithdrawGuard struct {
+	api *PoloniexApi
+
+	RequireConfirmation bool
+	ConfirmationTTL     time.Duration
+
+	sendMu sync.Mutex // held from the check of a withdrawal to its record
+
+	mu          sync.Mutex
+	allowlist   map[string]map[string]bool
+	dailyLimits map[string]float64
+	minAmounts  map[string]float64
+	history     []withdrawalRecord
+	pending     map[string]pendingWithdrawal
+	now         func() time.Time
+}
+
+func NewWithdrawGuard(api *PoloniexApi) *WithdrawGuard {
+	return &WithdrawGuard{
+		api:             api,
+		ConfirmationTTL: 5 * time.Minute,
+		allowlist:       make(map[string]map[string]bool),
+		dailyLimits:     make(map[string]float64),
+		minAmounts:      make(map[string]float64),
+		pending:         make(map[string]pendingWithdrawal),
+		now:             time.Now,
+	}
+}
+
+// Allow adds addresses to the allowlist of a currency.
+func (g *WithdrawGuard) Allow(currency string, addresses ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.allowlist[currency] == nil {
+		g.allowlist[currency] = make(map[string]bool)
+	}
+
+	for _, address := range addresses {
+		g.allowlist[currency][address] = true
+	}
+}
+
+// SetDailyLimit caps the amount of a currency withdrawn over any 24 hours.
+// Currencies without a limit are only capped by the exchange.
+func (g *WithdrawGuard) SetDailyLimit(currency string, amount float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.dailyLimits[currency] = amount
+}
+
+// SetMinAmount refuses withdrawals of a currency below amount, on top of the
+// TxFee check.
+func (g *WithdrawGuard) SetMinAmount(currency string, amount float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.minAmounts[currency] = amount
+}
+
+// Check validates a withdrawal request without sending it.
+func (g *WithdrawGuard) Check(req WithdrawRequest) error {
+	g.mu.Lock()
+	allowed := g.allowlist[req.Currency][req.Address]
+	minAmount := g.minAmounts[req.Currency]
+	limit, hasLimit := g.dailyLimits[req.Currency]
+	g.mu.Unlock()
+
+	if !allowed {
+		return fmt.Errorf("%s %s: %w", req.Currency, req.Address, ErrAddressNotAllowed)
+	}
+
+	if req.Amount <= 0 {
+		return fmt.Errorf("%s %f: %w", req.Currency, req.Amount, ErrAmountTooSmall)
+	}
+
+	currencies, err := g.api.ApiCurrencies()
+	if err != nil {
+		return err
+	}
+
+	currency, ok := currencies[req.Currency]
+	if !ok || currency.Disabled != 0 || currency.Frozen != 0 || currency.Delisted != 0 {
+		return fmt.Errorf("%s: %w", req.Currency, ErrCurrencyUnavailable)
+	}
+
+	if req.Amount <= currency.TxFee {
+		return fmt.Errorf("%s: %f does not cover the %f fee: %w", req.Currency, req.Amount, currency.TxFee, ErrAmountTooSmall)
+	}
+
+	if req.Amount < minAmount {
+		return fmt.Errorf("%s: %f is below the %f minimum: %w", req.Currency, req.Amount, minAmount, ErrAmountTooSmall)
+	}
+
+	if currency.MaxDailyWithdrawal > 0 && (!hasLimit || currency.MaxDailyWithdrawal < limit) {
+		limit, hasLimit = currency.MaxDailyWithdrawal, true
+	}
+
+	if hasLimit {
+		withdrawn, err := g.withdrawnSince(req.Currency, g.now().Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+
+		if withdrawn+req.Amount > limit {
+			return fmt.Errorf("%s: %f already withdrawn today, %f more would exceed %f: %w",
+				req.Currency, withdrawn, req.Amount, limit, ErrDailyLimitExceeded)
+		}
+	}
+
+	return nil
+}
+
+/*
+withdrawnSince sums the withdrawals of a currency since a given time, from the
+exchange history and from the local history. Local withdrawals older than the
+latest one reported by the exchange are assumed to be part of its history.
+*/
+func (g *WithdrawGuard) withdrawnSince(currency string, since time.Time) (float64, error) {
+	history, err := g.api.ApiPrivateDepositWithdrawals(since.Unix(), g.now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	var latest time.Time
+
+	for _, w := range history.Withdrawals {
+		if w.Currency != currency || isFailedWithdrawal(w.Status) {
+			continue
+		}
+
+		total += w.Amount
+		if t := time.Unix(w.Timestamp, 0); t.After(latest) {
+			latest = t
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, r := range g.history {
+		if r.currency == currency && r.time.After(since) && r.time.After(latest) {
+			total += r.amount
+		}
+	}
+
+	return total, nil
+}
+
+func isFailedWithdrawal(status string) bool {
+	status = strings.ToUpper(status)
+
+	return strings.HasPrefix(status, "CANCEL") || strings.HasPrefix(status, "FAIL")
+}
+
+// Withdraw checks and sends a withdrawal. It fails with
+// ErrConfirmationRequired when RequireConfirmation is set.
+func (g *WithdrawGuard) Withdraw(req WithdrawRequest) (*WithdrawResponse, error) {
+	if g.RequireConfirmation {
+		return nil, ErrConfirmationRequired
+	}
+
+	return g.send(req)
+}
+
+// Prepare checks a withdrawal and returns the token to pass to Confirm
+// within ConfirmationTTL.
+func (g *WithdrawGuard) Prepare(req WithdrawRequest) (string, error) {
+	if err := g.Check(req); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	for t, p := range g.pending {
+		if now.After(p.expires) {
+			delete(g.pending, t)
+		}
+	}
+
+	g.pending[token] = pendingWithdrawal{request: req, expires: now.Add(g.ConfirmationTTL)}
+
+	return token, nil
+}
+
+// Confirm sends a withdrawal prepared with Prepare. Tokens can only be used
+// once, and the request is checked again before being sent.
+func (g *WithdrawGuard) Confirm(token string) (*WithdrawResponse, error) {
+	g.mu.Lock()
+	p, ok := g.pending[token]
+	delete(g.pending, token)
+	g.mu.Unlock()
+
+	if !ok || g.now().After(p.expires) {
+		return nil, ErrUnknownConfirmation
+	}
+
+	return g.send(p.request)
+}
+
+func (g *WithdrawGuard) send(req WithdrawRequest) (*WithdrawResponse, error) {
+	g.sendMu.Lock()
+	defer g.sendMu.Unlock()
+
+	if err := g.Check(req); err != nil {
+		return nil, err
+	}
+
+	out, err := g.api.ApiPrivateWithdraw(req.Currency, req.Address, req.Amount, req.PaymentId)
+	if err != nil && !IsAmbiguous(err) {
+		return nil, err
+	}
+
+	// A withdrawal that may have been sent counts against the limit, until
+	// the history of the exchange shows it.
+	g.mu.Lock()
+	g.history = append(g.history, withdrawalRecord{req.Currency, req.Amount, g.now()})
+	g.mu.Unlock()
+
+	return out, err
+}
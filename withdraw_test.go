@@ -0,0 +1,211 @@
+package poloniexapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// withdrawExchange knows BTC, withdrawn up to 1 a day, and returns the
+// withdrawals of history made since the start of the request.
+type withdrawExchange struct {
+	mu      sync.Mutex
+	now     time.Time
+	history []Withdrawal
+	sent    []float64
+}
+
+func (e *withdrawExchange) answer(command string, params url.Values) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch command {
+	case CMD_PUBLIC_CURRENCIES:
+		return `{"BTC":{"id":28,"name":"Bitcoin","txFee":"0.0001","minConf":1,"disabled":0,"frozen":0,"delisted":0,"maxDailyWithdrawal":"1"}}`
+
+	case CMD_PRIVATE_DEPOSIT_WITHDRAWALS:
+		start, _ := strconv.ParseInt(params.Get("start"), 10, 64)
+
+		out := `{"deposits":[],"withdrawals":[`
+		for _, w := range e.history {
+			if w.Timestamp < start {
+				continue
+			}
+			if out[len(out)-1] == '}' {
+				out += ","
+			}
+			out += fmt.Sprintf(`{"currency":%q,"amount":"%f","timestamp":%d,"status":"COMPLETE"}`, w.Currency, w.Amount, w.Timestamp)
+		}
+		return out + `]}`
+
+	case CMD_PRIVATE_WITHDRAW:
+		amount, _ := strconv.ParseFloat(params.Get("amount"), 64)
+		e.sent = append(e.sent, amount)
+
+		// Leaves time to concurrent withdrawals to slip through.
+		e.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		e.mu.Lock()
+
+		return fmt.Sprintf(`{"response":"Withdrew %f BTC."}`, amount)
+	}
+
+	return `{"error":"unexpected command"}`
+}
+
+func testGuard(e *withdrawExchange) *WithdrawGuard {
+	e.now = time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	g := NewWithdrawGuard(testApi(e.answer))
+	g.Allow("BTC", "1address")
+	g.now = func() time.Time { return e.now }
+
+	return g
+}
+
+func TestWithdrawDailyLimit(t *testing.T) {
+	e := &withdrawExchange{}
+	g := testGuard(e)
+	e.history = []Withdrawal{
+		{Currency: "BTC", Amount: 0.6, Timestamp: e.now.Add(-time.Hour).Unix()},
+		{Currency: "BTC", Amount: 5, Timestamp: e.now.Add(-25 * time.Hour).Unix()},
+	}
+
+	cases := []struct {
+		limit   float64
+		address string
+		amount  float64
+		err     error
+	}{
+		{0, "2address", 0.1, ErrAddressNotAllowed},
+		{0, "1address", 0.0001, ErrAmountTooSmall},
+		{0, "1address", 0.5, ErrDailyLimitExceeded}, // maxDailyWithdrawal of the exchange
+		{0.8, "1address", 0.3, ErrDailyLimitExceeded},
+		{0.8, "1address", 0.1, nil},
+		{0.8, "1address", 0.15, ErrDailyLimitExceeded}, // 0.1 withdrawn through the guard
+		{5, "1address", 0.3, nil},                      // still capped to 1 by the exchange
+		{5, "1address", 0.01, ErrDailyLimitExceeded},
+	}
+
+	for i, c := range cases {
+		if c.limit > 0 {
+			g.SetDailyLimit("BTC", c.limit)
+		}
+
+		_, err := g.Withdraw(WithdrawRequest{Currency: "BTC", Address: c.address, Amount: c.amount})
+		if !errors.Is(err, c.err) {
+			t.Errorf("case %d: got %v, want %v", i, err, c.err)
+		}
+	}
+
+	if len(e.sent) != 2 {
+		t.Errorf("sent %v, want 2 withdrawals", e.sent)
+	}
+}
+
+func TestWithdrawConfirmation(t *testing.T) {
+	e := &withdrawExchange{}
+	g := testGuard(e)
+	g.RequireConfirmation = true
+
+	req := WithdrawRequest{Currency: "BTC", Address: "1address", Amount: 0.1}
+
+	if _, err := g.Withdraw(req); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("withdraw: got %v, want ErrConfirmationRequired", err)
+	}
+
+	if _, err := g.Prepare(WithdrawRequest{Currency: "BTC", Address: "2address", Amount: 0.1}); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("prepare: got %v, want ErrAddressNotAllowed", err)
+	}
+
+	token, err := g.Prepare(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = g.Confirm(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = g.Confirm(token); !errors.Is(err, ErrUnknownConfirmation) {
+		t.Errorf("second confirm: got %v, want ErrUnknownConfirmation", err)
+	}
+
+	token, err = g.Prepare(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.now = e.now.Add(g.ConfirmationTTL + time.Second)
+	if _, err = g.Confirm(token); !errors.Is(err, ErrUnknownConfirmation) {
+		t.Errorf("expired confirm: got %v, want ErrUnknownConfirmation", err)
+	}
+
+	if len(e.sent) != 1 {
+		t.Errorf("sent %v, want 1 withdrawal", e.sent)
+	}
+}
+
+func TestWithdrawConcurrent(t *testing.T) {
+	e := &withdrawExchange{}
+	g := testGuard(e)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	refused := 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := g.Withdraw(WithdrawRequest{Currency: "BTC", Address: "1address", Amount: 0.3})
+			if errors.Is(err, ErrDailyLimitExceeded) {
+				mu.Lock()
+				refused++
+				mu.Unlock()
+			} else if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(e.sent) != 3 || refused != 7 {
+		t.Errorf("sent %v and refused %d, want 3 withdrawals within the limit of 1", e.sent, refused)
+	}
+}
+
+func TestWithdrawAmbiguous(t *testing.T) {
+	e := &withdrawExchange{}
+	g := testGuard(e)
+
+	// The first withdrawal gets an unreadable answer, though it was sent.
+	answered := false
+	g.api.Client.Transport = exchange(func(command string, params url.Values) string {
+		out := e.answer(command, params)
+		if command == CMD_PRIVATE_WITHDRAW && !answered {
+			answered = true
+			return "<html>502 Bad Gateway</html>"
+		}
+		return out
+	})
+
+	_, err := g.Withdraw(WithdrawRequest{Currency: "BTC", Address: "1address", Amount: 0.6})
+	if !IsAmbiguous(err) {
+		t.Fatalf("got %v, want an ambiguous error", err)
+	}
+
+	_, err = g.Withdraw(WithdrawRequest{Currency: "BTC", Address: "1address", Amount: 0.6})
+	if !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Errorf("got %v, want %v after an ambiguous withdrawal", err, ErrDailyLimitExceeded)
+	}
+
+	if len(e.sent) != 1 {
+		t.Errorf("sent %v, want 1 withdrawal", e.sent)
+	}
+}